@@ -0,0 +1,293 @@
+package mangadex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	// AtHomeBaseURLTTL is how long a baseUrl from /at-home/server stays valid.
+	AtHomeBaseURLTTL = 15 * time.Minute
+
+	// DefaultAtHomeReportURL receives MD@Home image fetch reports.
+	DefaultAtHomeReportURL = "https://api.mangadex.network/report"
+)
+
+// PageQuality selects between original and compressed page images.
+type PageQuality string
+
+const (
+	QualityData      PageQuality = "data"
+	QualityDataSaver PageQuality = "data-saver"
+)
+
+var ErrNoPages = errors.New("chapter has no pages")
+
+// ChapterPages describes where the pages of a chapter can be fetched from.
+type ChapterPages struct {
+	ChapterID openapi_types.UUID
+	BaseURL   string
+	Hash      string
+	Data      []string
+	DataSaver []string
+	FetchedAt time.Time
+}
+
+// ExpiresAt is when BaseURL stops being valid.
+func (p *ChapterPages) ExpiresAt() time.Time {
+	return p.FetchedAt.Add(AtHomeBaseURLTTL)
+}
+
+// FileNames returns the page file names for the given quality, in reading order.
+func (p *ChapterPages) FileNames(quality PageQuality) []string {
+	if quality == QualityDataSaver {
+		return p.DataSaver
+	}
+	return p.Data
+}
+
+// URLs returns the page URLs for the given quality, in reading order.
+func (p *ChapterPages) URLs(quality PageQuality) ([]string, error) {
+	names := p.FileNames(quality)
+	if len(names) == 0 {
+		return nil, ErrNoPages
+	}
+	out := make([]string, len(names))
+	for i, name := range names {
+		u, err := p.URL(quality, name)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = u
+	}
+	return out, nil
+}
+
+// URL returns the URL of a single page file.
+func (p *ChapterPages) URL(quality PageQuality, fileName string) (string, error) {
+	if quality == "" {
+		quality = QualityData
+	}
+	return url.JoinPath(p.BaseURL, string(quality), p.Hash, fileName)
+}
+
+// AtHomeReport is the payload MD@Home expects after every image fetch.
+type AtHomeReport struct {
+	URL      string `json:"url"`
+	Success  bool   `json:"success"`
+	Cached   bool   `json:"cached"`
+	Bytes    int64  `json:"bytes"`
+	Duration int64  `json:"duration"`
+}
+
+// AtHomeResolver resolves chapter page URLs through /at-home/server and
+// re-requests the baseUrl once it is stale.
+type AtHomeResolver struct {
+	Client ClientWithResponsesInterface
+
+	// HTTPClient is used for image fetches and reports. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// ForcePort443 only selects MD@Home servers listening on port 443.
+	ForcePort443 bool
+
+	// ReportURL defaults to DefaultAtHomeReportURL.
+	ReportURL string
+
+	// DisableReports stops Fetch from reporting to MD@Home.
+	DisableReports bool
+
+	// Margin is subtracted from AtHomeBaseURLTTL when deciding staleness so
+	// that fetches started just before expiry still succeed.
+	Margin time.Duration
+
+	now func() time.Time
+
+	mu    sync.Mutex
+	cache map[openapi_types.UUID]*ChapterPages
+}
+
+// NewAtHomeResolver creates an AtHomeResolver using client.
+func NewAtHomeResolver(client ClientWithResponsesInterface) *AtHomeResolver {
+	return &AtHomeResolver{
+		Client: client,
+		Margin: time.Minute,
+	}
+}
+
+// Pages returns the pages of chapterID, requesting a new baseUrl when none is
+// cached or the cached one is stale.
+func (r *AtHomeResolver) Pages(ctx context.Context, chapterID openapi_types.UUID) (*ChapterPages, error) {
+	r.mu.Lock()
+	p, ok := r.cache[chapterID]
+	r.mu.Unlock()
+	if ok && !r.stale(p) {
+		return p, nil
+	}
+	return r.Refresh(ctx, chapterID)
+}
+
+// Refresh always requests a new baseUrl for chapterID, e.g. after a page
+// fetch failed against the current one. A cached client is bypassed, as
+// its response may hold the baseUrl being replaced.
+func (r *AtHomeResolver) Refresh(ctx context.Context, chapterID openapi_types.UUID) (*ChapterPages, error) {
+	var params *GetAtHomeServerChapterIdParams
+	if r.ForcePort443 {
+		params = &GetAtHomeServerChapterIdParams{ForcePort443: Bool(true)}
+	}
+	resp, err := r.Client.GetAtHomeServerChapterIdWithResponse(NoCache(ctx), chapterID, params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, newAPIError(resp.HTTPResponse, resp.JSON404)
+	}
+
+	body := resp.JSON200
+	if body.BaseUrl == nil || body.Chapter == nil || body.Chapter.Hash == nil {
+		return nil, fmt.Errorf("at-home server response for %s is incomplete", chapterID)
+	}
+	p := &ChapterPages{
+		ChapterID: chapterID,
+		BaseURL:   *body.BaseUrl,
+		Hash:      *body.Chapter.Hash,
+		FetchedAt: r.clock(),
+	}
+	if body.Chapter.Data != nil {
+		p.Data = *body.Chapter.Data
+	}
+	if body.Chapter.DataSaver != nil {
+		p.DataSaver = *body.Chapter.DataSaver
+	}
+
+	r.mu.Lock()
+	if r.cache == nil {
+		r.cache = make(map[openapi_types.UUID]*ChapterPages)
+	}
+	r.cache[chapterID] = p
+	r.mu.Unlock()
+	return p, nil
+}
+
+// PageURLs returns the ordered page URLs of chapterID for the given quality.
+func (r *AtHomeResolver) PageURLs(ctx context.Context, chapterID openapi_types.UUID, quality PageQuality) ([]string, error) {
+	p, err := r.Pages(ctx, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	return p.URLs(quality)
+}
+
+// Fetch downloads a page image and reports the outcome to MD@Home. The
+// returned error is the fetch error; report failures are ignored.
+func (r *AtHomeResolver) Fetch(ctx context.Context, pageURL string) ([]byte, error) {
+	start := r.clock()
+	report := AtHomeReport{URL: pageURL}
+
+	data, cached, err := r.fetch(ctx, pageURL)
+	report.Success = err == nil
+	report.Cached = cached
+	report.Bytes = int64(len(data))
+	report.Duration = r.clock().Sub(start).Milliseconds()
+
+	if !r.DisableReports {
+		_ = r.Report(ctx, report)
+	}
+	return data, err
+}
+
+func (r *AtHomeResolver) fetch(ctx context.Context, pageURL string) ([]byte, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	resp, err := r.httpClient().Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	cached := strings.HasPrefix(resp.Header.Get("X-Cache"), "HIT")
+	if resp.StatusCode != http.StatusOK {
+		return nil, cached, fmt.Errorf("fetching %s: %s", pageURL, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, cached, err
+	}
+	return data, cached, nil
+}
+
+// Report sends a fetch report to MD@Home. Reports for images served from
+// mangadex.org itself are skipped, as the MD@Home docs require.
+func (r *AtHomeResolver) Report(ctx context.Context, report AtHomeReport) error {
+	if u, err := url.Parse(report.URL); err == nil && isMangadexHost(u.Hostname()) {
+		return nil
+	}
+
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	target := r.ReportURL
+	if target == "" {
+		target = DefaultAtHomeReportURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("at-home report: %s", resp.Status)
+	}
+	return nil
+}
+
+// Forget drops the cached baseUrl for chapterID.
+func (r *AtHomeResolver) Forget(chapterID openapi_types.UUID) {
+	r.mu.Lock()
+	delete(r.cache, chapterID)
+	r.mu.Unlock()
+}
+
+func (r *AtHomeResolver) stale(p *ChapterPages) bool {
+	return !r.clock().Before(p.ExpiresAt().Add(-r.Margin))
+}
+
+func (r *AtHomeResolver) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+func (r *AtHomeResolver) httpClient() *http.Client {
+	if r.HTTPClient != nil {
+		return r.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+func isMangadexHost(host string) bool {
+	return host == "mangadex.org" || strings.HasSuffix(host, ".mangadex.org")
+}
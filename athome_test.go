@@ -0,0 +1,194 @@
+package mangadex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	goCache "github.com/patrickmn/go-cache"
+)
+
+// fakeAtHome serves /at-home/server with a new baseUrl per lookup and the
+// page images under every baseUrl it handed out.
+type fakeAtHome struct {
+	*httptest.Server
+
+	// Mux takes further handlers, e.g. for the manga feed.
+	Mux *http.ServeMux
+
+	Hash      string
+	Data      map[string][]byte
+	DataSaver map[string][]byte
+
+	mu      sync.Mutex
+	lookups int
+	broken  map[PageQuality]bool
+}
+
+func newFakeAtHome(t *testing.T) *fakeAtHome {
+	t.Helper()
+	f := &fakeAtHome{
+		Hash:      "c0ffee",
+		Data:      map[string][]byte{},
+		DataSaver: map[string][]byte{},
+		broken:    map[PageQuality]bool{},
+	}
+	f.Mux = http.NewServeMux()
+	f.Mux.HandleFunc("GET /at-home/server/{id}", f.server)
+	f.Mux.HandleFunc("GET /{node}/{quality}/{hash}/{name}", f.image)
+	f.Server = httptest.NewServer(f.Mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+// Break makes every image of quality fail with a server error.
+func (f *fakeAtHome) Break(quality PageQuality) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.broken[quality] = true
+}
+
+func (f *fakeAtHome) Lookups() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lookups
+}
+
+func (f *fakeAtHome) server(w http.ResponseWriter, _ *http.Request) {
+	f.mu.Lock()
+	f.lookups++
+	base := fmt.Sprintf("%s/node%d", f.URL, f.lookups)
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"result":  "ok",
+		"baseUrl": base,
+		"chapter": map[string]any{
+			"hash":      f.Hash,
+			"data":      sortedNames(f.Data),
+			"dataSaver": sortedNames(f.DataSaver),
+		},
+	})
+}
+
+func (f *fakeAtHome) image(w http.ResponseWriter, r *http.Request) {
+	quality := PageQuality(r.PathValue("quality"))
+	images := f.Data
+	if quality == QualityDataSaver {
+		images = f.DataSaver
+	}
+	f.mu.Lock()
+	broken := f.broken[quality]
+	f.mu.Unlock()
+	data, ok := images[r.PathValue("name")]
+	switch {
+	case broken:
+		w.WriteHeader(http.StatusBadGateway)
+	case !ok || r.PathValue("hash") != f.Hash:
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		_, _ = w.Write(data)
+	}
+}
+
+// sortedNames returns the page names, which start with their page number.
+func sortedNames(images map[string][]byte) []string {
+	names := make([]string, len(images))
+	for name := range images {
+		var n int
+		fmt.Sscanf(name, "%d-", &n)
+		names[n-1] = name
+	}
+	return names
+}
+
+func (f *fakeAtHome) resolver(t *testing.T, cached bool) *AtHomeResolver {
+	t.Helper()
+	client, err := NewClientWithResponses(f.URL, WithHTTPClient(f.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var api ClientWithResponsesInterface = client
+	if cached {
+		api = NewCachedClientWithResponsesInterface(client, NewLocalCache(goCache.New(time.Hour, time.Hour)))
+	}
+	r := NewAtHomeResolver(api)
+	r.HTTPClient = f.Client()
+	r.DisableReports = true
+	return r
+}
+
+func TestAtHomeResolverRefreshBypassesCache(t *testing.T) {
+	f := newFakeAtHome(t)
+	f.Data["1-aa.png"] = []byte("page")
+	r := f.resolver(t, true)
+	now := time.Now()
+	r.now = func() time.Time { return now }
+	ctx := context.Background()
+	chapter := uuid.New()
+
+	first, err := r.Pages(ctx, chapter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := r.Pages(ctx, chapter); again.BaseURL != first.BaseURL || f.Lookups() != 1 {
+		t.Fatalf("fresh baseUrl re-requested: %s, %d lookups", again.BaseURL, f.Lookups())
+	}
+
+	// once stale, the cached client must not serve the old baseUrl again
+	now = now.Add(AtHomeBaseURLTTL)
+	second, err := r.Pages(ctx, chapter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.BaseURL == first.BaseURL || f.Lookups() != 2 {
+		t.Fatalf("stale baseUrl served: %s, %d lookups", second.BaseURL, f.Lookups())
+	}
+
+	third, err := r.Refresh(ctx, chapter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.BaseURL == second.BaseURL || !strings.HasSuffix(third.BaseURL, "/node3") {
+		t.Fatalf("refresh returned %s", third.BaseURL)
+	}
+}
+
+func TestNoCacheRefreshesStoredResponse(t *testing.T) {
+	f := newFakeAtHome(t)
+	client, err := NewClientWithResponses(f.URL, WithHTTPClient(f.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := NewCachedClientWithResponsesInterface(client, NewLocalCache(goCache.New(time.Hour, time.Hour)))
+	chapter := uuid.New()
+	baseURL := func(ctx context.Context) string {
+		t.Helper()
+		resp, err := api.GetAtHomeServerChapterIdWithResponse(ctx, chapter, nil)
+		if err != nil || resp.JSON200 == nil {
+			t.Fatalf("lookup: %v", err)
+		}
+		return *resp.JSON200.BaseUrl
+	}
+
+	first := baseURL(context.Background())
+	fresh := baseURL(NoCache(context.Background()))
+	if fresh == first {
+		t.Fatalf("NoCache served the stored %s", first)
+	}
+	// later calls see the refreshed response whatever their context
+	type requestID struct{}
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), requestID{}, "r1"), time.Minute)
+	defer cancel()
+	if got := baseURL(ctx); got != fresh || f.Lookups() != 2 {
+		t.Fatalf("got %s after %d lookups, want %s", got, f.Lookups(), fresh)
+	}
+}
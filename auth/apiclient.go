@@ -0,0 +1,273 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Seann-Moser/mangadex"
+)
+
+// DefaultPersonalClientName names the API client SetupPersonalClient looks
+// for and creates.
+const DefaultPersonalClientName = "mangadex-go"
+
+// ErrClientNotApproved is returned while a personal API client awaits
+// moderation, or once it has been rejected.
+var ErrClientNotApproved = errors.New("api client not approved")
+
+var errNoPersonalClient = errors.New("no personal api client")
+
+// PersonalClientOptions selects the personal API client SetupPersonalClient
+// uses.
+type PersonalClientOptions struct {
+	// Name identifies the client among the user's clients. Defaults to
+	// DefaultPersonalClientName.
+	Name string
+
+	// Description is set when the client is created.
+	Description string
+}
+
+// SetupPersonalClient moves a user onto their own personal API client: it
+// finds the client called opts.Name, creating it if needed, fetches its
+// secret and stores it with the user's tokens for every later refresh.
+//
+// Refresh tokens stay bound to the client that issued them, so the current
+// session cannot be refreshed under the personal client. Given a password,
+// SetupPersonalClient restarts the session by logging the user in again
+// under the personal client. Users who signed in without one, such as
+// through an AuthCodeFlow, keep their current tokens only until the next
+// refresh, and must then log in with the returned credentials.
+//
+// api must send its requests to one of the Endpoints' APIHosts, so that
+// ApplyAuth authorizes them.
+func (c *OAuthClient) SetupPersonalClient(
+	ctx context.Context,
+	api mangadex.ClientWithResponsesInterface,
+	userKey, username, password string,
+	opts PersonalClientOptions,
+) (*OAuthCredentials, error) {
+
+	if userKey == "" {
+		return nil, ErrMissingUserKey
+	}
+	name := opts.Name
+	if name == "" {
+		name = DefaultPersonalClientName
+	}
+
+	client, err := c.findPersonalClient(ctx, api, userKey, name, func(a *mangadex.ApiClientAttributes) bool {
+		return a.Name != nil && *a.Name == name
+	})
+	if errors.Is(err, errNoPersonalClient) {
+		client, err = c.createPersonalClient(ctx, api, userKey, name, opts.Description)
+	}
+	if err != nil {
+		return nil, err
+	}
+	clientID, err := approvedClientID(client)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := api.GetApiclientSecretWithResponse(ctx, *client.Id, c.ApplyAuth(userKey))
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil || resp.JSON200.Data == nil {
+		return nil, apiError(resp.HTTPResponse, resp.JSON403)
+	}
+
+	creds := &OAuthCredentials{UserKey: userKey, ClientID: clientID, ClientSecret: *resp.JSON200.Data}
+	if password == "" {
+		if err := c.storeCreds(ctx, userKey, creds); err != nil {
+			return nil, err
+		}
+		return creds, nil
+	}
+	// the login stores the credentials with the new tokens
+	if err := c.Login(ctx, username, password, creds); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
+
+// RegeneratePersonalClientSecret replaces the secret of the personal API
+// client the user's session runs under and stores the new one. The old
+// secret stops working immediately, so other processes sharing the store
+// pick the new one up on their next load.
+func (c *OAuthClient) RegeneratePersonalClientSecret(
+	ctx context.Context,
+	api mangadex.ClientWithResponsesInterface,
+	userKey string,
+) (*OAuthCredentials, error) {
+
+	cur, err := c.current(ctx, userKey)
+	if err != nil {
+		return nil, err
+	}
+	if cur.creds == &c.DefaultCreds {
+		return nil, errNoPersonalClient
+	}
+	clientID := cur.creds.ClientID
+
+	client, err := c.findPersonalClient(ctx, api, userKey, "", func(a *mangadex.ApiClientAttributes) bool {
+		return a.ExternalClientId != nil && *a.ExternalClientId == clientID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := api.PostRegenerateApiclientSecretWithResponse(
+		ctx,
+		*client.Id,
+		&mangadex.PostRegenerateApiclientSecretParams{ContentType: "application/json"},
+		mangadex.PostRegenerateApiclientSecretJSONRequestBody{},
+		c.ApplyAuth(userKey),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil || resp.JSON200.Data == nil {
+		return nil, apiError(resp.HTTPResponse, resp.JSON403)
+	}
+
+	creds := &OAuthCredentials{UserKey: userKey, ClientID: clientID, ClientSecret: *resp.JSON200.Data}
+	if err := c.storeCreds(ctx, userKey, creds); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
+
+// findPersonalClient pages through the user's API clients, filtered by name
+// when set, and returns the first one match accepts.
+func (c *OAuthClient) findPersonalClient(
+	ctx context.Context,
+	api mangadex.ClientWithResponsesInterface,
+	userKey, name string,
+	match func(*mangadex.ApiClientAttributes) bool,
+) (*mangadex.ApiClient, error) {
+
+	params := &mangadex.GetListApiclientsParams{Limit: mangadex.Int(100)}
+	if name != "" {
+		params.Name = &name
+	}
+	for offset := 0; ; {
+		params.Offset = mangadex.Int(offset)
+		resp, err := api.GetListApiclientsWithResponse(ctx, params, c.ApplyAuth(userKey))
+		if err != nil {
+			return nil, err
+		}
+		if resp.JSON200 == nil {
+			return nil, apiError(resp.HTTPResponse)
+		}
+		var page []mangadex.ApiClient
+		if resp.JSON200.Data != nil {
+			page = *resp.JSON200.Data
+		}
+		for i := range page {
+			if page[i].Id != nil && page[i].Attributes != nil && match(page[i].Attributes) {
+				return &page[i], nil
+			}
+		}
+		offset += len(page)
+		if len(page) == 0 || resp.JSON200.Total == nil || offset >= *resp.JSON200.Total {
+			return nil, errNoPersonalClient
+		}
+	}
+}
+
+func (c *OAuthClient) createPersonalClient(
+	ctx context.Context,
+	api mangadex.ClientWithResponsesInterface,
+	userKey, name, description string,
+) (*mangadex.ApiClient, error) {
+
+	body := mangadex.ApiClientCreate{Name: name, Profile: mangadex.Personal}
+	if description != "" {
+		body.Description = &description
+	}
+	resp, err := api.PostCreateApiclientWithResponse(
+		ctx,
+		&mangadex.PostCreateApiclientParams{ContentType: "application/json"},
+		body,
+		c.ApplyAuth(userKey),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil || resp.JSON200.Data == nil || resp.JSON200.Data.Id == nil {
+		return nil, apiError(resp.HTTPResponse, resp.JSON400, resp.JSON403)
+	}
+	return resp.JSON200.Data, nil
+}
+
+// approvedClientID returns the OAuth client id of an approved API client.
+func approvedClientID(client *mangadex.ApiClient) (string, error) {
+	a := client.Attributes
+	if a.State == nil ||
+		(*a.State != mangadex.ApiClientAttributesStateApproved &&
+			*a.State != mangadex.ApiClientAttributesStateAutoapproved) {
+		state := "unknown"
+		if a.State != nil {
+			state = string(*a.State)
+		}
+		return "", fmt.Errorf("%w: client %s is %s", ErrClientNotApproved, client.Id, state)
+	}
+	if a.ExternalClientId == nil || *a.ExternalClientId == "" {
+		return "", fmt.Errorf("client %s has no oauth client id", client.Id)
+	}
+	return *a.ExternalClientId, nil
+}
+
+// storeCreds replaces the credentials stored with the user's tokens,
+// keeping the tokens.
+func (c *OAuthClient) storeCreds(ctx context.Context, userKey string, creds *OAuthCredentials) error {
+	if c.Store == nil {
+		hot, ok := c.hotToken(userKey)
+		if !ok {
+			return errNoAccessToken
+		}
+		hot.creds = creds
+		c.remember(userKey, hot)
+		return nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		rec, err := c.Store.LoadRecord(ctx, userKey)
+		if err != nil {
+			return err
+		}
+		err = c.Store.SaveIfUnchanged(ctx, userKey, rec.Version, rec.Tokens, creds, rec.ExpiresAt)
+		if err == nil {
+			c.remember(userKey, hotToken{
+				tokens:    rec.Tokens,
+				creds:     creds,
+				expiresAt: rec.ExpiresAt,
+				version:   rec.Version + 1,
+			})
+			return nil
+		}
+		// a refresh landed in between; apply the credentials to its tokens
+		if !errors.Is(err, ErrVersionConflict) || attempt == 2 {
+			return err
+		}
+	}
+}
+
+// apiError builds a mangadex.APIError from a response and whichever error
+// body the generated client decoded for it.
+func apiError(resp *http.Response, bodies ...*mangadex.ErrorResponse) error {
+	e := &mangadex.APIError{}
+	if resp != nil {
+		e.StatusCode = resp.StatusCode
+	}
+	for _, b := range bodies {
+		if b != nil && b.Errors != nil {
+			e.Errors = append(e.Errors, *b.Errors...)
+		}
+	}
+	return e
+}
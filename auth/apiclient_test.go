@@ -0,0 +1,288 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Seann-Moser/mangadex"
+	"github.com/Seann-Moser/mangadex/auth"
+	"github.com/Seann-Moser/mangadex/auth/authtest"
+	"github.com/google/uuid"
+)
+
+// fakeAPIClients serves the API client endpoints, registering each client
+// and secret it hands out with the identity provider.
+type fakeAPIClients struct {
+	idp *authtest.Server
+
+	// State is given to created clients.
+	State mangadex.ApiClientAttributesState
+
+	mu      sync.Mutex
+	clients []mangadex.ApiClient
+	secrets map[uuid.UUID]string
+}
+
+func newFakeAPIClients(t *testing.T, c *auth.OAuthClient, idp *authtest.Server) (*fakeAPIClients, mangadex.ClientWithResponsesInterface) {
+	t.Helper()
+	f := &fakeAPIClients{
+		idp:     idp,
+		State:   mangadex.ApiClientAttributesStateAutoapproved,
+		secrets: map[uuid.UUID]string{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /client", f.list)
+	mux.HandleFunc("POST /client", f.create)
+	mux.HandleFunc("GET /client/{id}/secret", f.secret)
+	mux.HandleFunc("POST /client/{id}/secret", f.secret)
+	srv := httptest.NewServer(f.authorized(mux))
+	t.Cleanup(srv.Close)
+
+	u, _ := url.Parse(srv.URL)
+	c.Endpoints.APIHosts = []string{u.Host}
+	api, err := mangadex.NewClientWithResponses(srv.URL, mangadex.WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, api
+}
+
+func (f *fakeAPIClients) authorized(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tok := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if _, ok := f.idp.Username(tok); !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (f *fakeAPIClients) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.clients)
+}
+
+func (f *fakeAPIClients) list(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name := r.URL.Query().Get("name")
+	var data []mangadex.ApiClient
+	for _, c := range f.clients {
+		if name == "" || strings.Contains(*c.Attributes.Name, name) {
+			data = append(data, c)
+		}
+	}
+	writeJSON(w, mangadex.ApiClientList{Data: &data, Total: mangadex.Int(len(data))})
+}
+
+func (f *fakeAPIClients) create(w http.ResponseWriter, r *http.Request) {
+	var body mangadex.ApiClientCreate
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Profile != mangadex.Personal {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := uuid.New()
+	state := f.State
+	external := "personal-client-" + id.String()[:8]
+	f.clients = append(f.clients, mangadex.ApiClient{
+		Id: &id,
+		Attributes: &mangadex.ApiClientAttributes{
+			Name:             &body.Name,
+			State:            &state,
+			ExternalClientId: &external,
+		},
+	})
+	f.rotate(id, external)
+	writeJSON(w, mangadex.ApiClientResponse{Data: &f.clients[len(f.clients)-1]})
+}
+
+func (f *fakeAPIClients) secret(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var external string
+	for _, c := range f.clients {
+		if *c.Id == id {
+			external = *c.Attributes.ExternalClientId
+		}
+	}
+	if external == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if r.Method == http.MethodPost {
+		f.rotate(id, external)
+	}
+	secret := f.secrets[id]
+	writeJSON(w, map[string]any{"result": "ok", "data": secret})
+}
+
+// rotate issues a new secret; the caller holds f.mu.
+func (f *fakeAPIClients) rotate(id uuid.UUID, external string) {
+	secret := uuid.NewString()
+	f.secrets[id] = secret
+	f.idp.AddClient(external, secret)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestSetupPersonalClient(t *testing.T) {
+	c, idp := newTestClient(t)
+	fake, api := newFakeAPIClients(t, c, idp)
+	ctx := context.Background()
+
+	if err := c.Login(ctx, "reader", "hunter2", &auth.OAuthCredentials{UserKey: "u1"}); err != nil {
+		t.Fatal(err)
+	}
+	creds, err := c.SetupPersonalClient(ctx, api, "u1", "reader", "hunter2", auth.PersonalClientOptions{})
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	if !strings.HasPrefix(creds.ClientID, "personal-client-") || creds.ClientSecret == "" {
+		t.Fatalf("creds = %+v", creds)
+	}
+
+	_, stored, _, err := c.Store.Load(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored == nil || *stored != *creds {
+		t.Fatalf("stored creds = %+v, want %+v", stored, creds)
+	}
+
+	// later refreshes run under the personal client
+	if _, err := c.Refresh(ctx, "u1"); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	reqs := idp.Requests()
+	if last := reqs[len(reqs)-1]; last.GrantType != "refresh_token" || last.ClientID != creds.ClientID {
+		t.Fatalf("last token request = %+v", last)
+	}
+
+	// a second setup reuses the client
+	again, err := c.SetupPersonalClient(ctx, api, "u1", "reader", "hunter2", auth.PersonalClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ClientID != creds.ClientID || fake.count() != 1 {
+		t.Fatalf("client not reused: %s, %d clients", again.ClientID, fake.count())
+	}
+}
+
+func TestSetupPersonalClientWithoutPassword(t *testing.T) {
+	flow, idp := newAuthCodeFlow(t)
+	c := flow.Client
+	_, api := newFakeAPIClients(t, c, idp)
+	ctx := context.Background()
+
+	authURL, err := flow.AuthCodeURL(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := signIn(t, idp, authURL).URL.Query()
+	if _, err := flow.Exchange(ctx, q.Get("state"), q.Get("code")); err != nil {
+		t.Fatal(err)
+	}
+	before, _, _, err := c.Store.Load(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	creds, err := c.SetupPersonalClient(ctx, api, "u1", "", "", auth.PersonalClientOptions{})
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	tok, stored, _, err := c.Store.Load(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored == nil || *stored != *creds {
+		t.Fatalf("stored creds = %+v, want %+v", stored, creds)
+	}
+	// the code flow session is kept as is
+	if tok.AccessToken != before.AccessToken || countGrants(idp, "password") != 0 {
+		t.Fatalf("session restarted: %+v, %d password grants", tok, countGrants(idp, "password"))
+	}
+
+	// later logins run under the personal client
+	if err := c.Login(ctx, "reader", "hunter2", creds); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	reqs := idp.Requests()
+	if last := reqs[len(reqs)-1]; last.GrantType != "password" || last.ClientID != creds.ClientID {
+		t.Fatalf("last token request = %+v", last)
+	}
+}
+
+func TestSetupPersonalClientNotApproved(t *testing.T) {
+	c, idp := newTestClient(t)
+	fake, api := newFakeAPIClients(t, c, idp)
+	fake.State = mangadex.ApiClientAttributesStateRequested
+	ctx := context.Background()
+
+	if err := c.Login(ctx, "reader", "hunter2", &auth.OAuthCredentials{UserKey: "u1"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := c.SetupPersonalClient(ctx, api, "u1", "reader", "hunter2", auth.PersonalClientOptions{Name: "bot"})
+	if !errors.Is(err, auth.ErrClientNotApproved) {
+		t.Fatalf("err = %v, want ErrClientNotApproved", err)
+	}
+	if _, stored, _, _ := c.Store.Load(ctx, "u1"); stored != nil && stored.ClientID != "" {
+		t.Fatalf("creds stored for unapproved client: %+v", stored)
+	}
+	if _, err := c.Refresh(ctx, "u1"); err != nil {
+		t.Fatalf("session broken: %v", err)
+	}
+}
+
+func TestRegeneratePersonalClientSecret(t *testing.T) {
+	c, idp := newTestClient(t)
+	_, api := newFakeAPIClients(t, c, idp)
+	ctx := context.Background()
+
+	if err := c.Login(ctx, "reader", "hunter2", &auth.OAuthCredentials{UserKey: "u1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.RegeneratePersonalClientSecret(ctx, api, "u1"); err == nil {
+		t.Fatal("regenerated without a personal client")
+	}
+	old, err := c.SetupPersonalClient(ctx, api, "u1", "reader", "hunter2", auth.PersonalClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	creds, err := c.RegeneratePersonalClientSecret(ctx, api, "u1")
+	if err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+	if creds.ClientID != old.ClientID || creds.ClientSecret == old.ClientSecret {
+		t.Fatalf("creds = %+v, old %+v", creds, old)
+	}
+	_, stored, _, _ := c.Store.Load(ctx, "u1")
+	if stored == nil || *stored != *creds {
+		t.Fatalf("stored creds = %+v, want %+v", stored, creds)
+	}
+
+	// the session survives, refreshing with the new secret
+	if _, err := c.Refresh(ctx, "u1"); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+}
@@ -0,0 +1,282 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultAuthStateTTL is how long a user has to complete an authorization
+// request.
+const DefaultAuthStateTTL = 10 * time.Minute
+
+// AuthStateCookie names the cookie Start binds an authorization request to
+// the browser with.
+const AuthStateCookie = "mangadex_auth_state"
+
+// ErrAuthStateMismatch is returned for a callback that arrives in a browser
+// other than the one the request was started in, as when an attacker lures
+// a user to a callback URL for the attacker's own sign-in.
+var ErrAuthStateMismatch = errors.New("authorization state does not match this browser")
+
+// AuthorizeError is an error the authorization server redirected back with,
+// such as access_denied when the user declines.
+type AuthorizeError struct {
+	Code        string
+	Description string
+}
+
+func (e *AuthorizeError) Error() string {
+	msg := "authorization failed: " + e.Code
+	if e.Description != "" {
+		msg += " (" + e.Description + ")"
+	}
+	return msg
+}
+
+// AuthCodeFlow signs users in through their browser with the authorization
+// code grant and PKCE, so they never hand their password to the
+// application. The client authenticates with Client.DefaultCreds; leave
+// ClientSecret empty for a public client.
+//
+// Begin requests with Start and serve the flow at RedirectURL, where it
+// checks that the callback comes back to the browser that started the
+// request, exchanges the code and saves the tokens under the user key the
+// request was started with.
+type AuthCodeFlow struct {
+	Client *OAuthClient
+
+	// RedirectURL is where the authorization server sends the browser back
+	// to. It must be registered with the client.
+	RedirectURL string
+
+	// Scopes defaults to "openid".
+	Scopes []string
+
+	// States keeps requests between AuthCodeURL and the callback. Defaults
+	// to an in-memory store, which needs the callback to reach the process
+	// that started the request.
+	States AuthStateStore
+
+	// StateTTL defaults to DefaultAuthStateTTL.
+	StateTTL time.Duration
+
+	// OnLogin writes the response once the user is signed in. Defaults to a
+	// short confirmation page.
+	OnLogin func(w http.ResponseWriter, r *http.Request, userKey string)
+
+	// OnError writes the response for a failed callback. Defaults to a
+	// plain-text error.
+	OnError func(w http.ResponseWriter, r *http.Request, err error)
+
+	statesOnce sync.Once
+}
+
+func NewAuthCodeFlow(client *OAuthClient, redirectURL string) *AuthCodeFlow {
+	return &AuthCodeFlow{
+		Client:      client,
+		RedirectURL: redirectURL,
+		States:      NewInMemoryAuthStateStore(),
+	}
+}
+
+func (f *AuthCodeFlow) states() AuthStateStore {
+	f.statesOnce.Do(func() {
+		if f.States == nil {
+			f.States = NewInMemoryAuthStateStore()
+		}
+	})
+	return f.States
+}
+
+func (f *AuthCodeFlow) stateTTL() time.Duration {
+	if f.StateTTL > 0 {
+		return f.StateTTL
+	}
+	return DefaultAuthStateTTL
+}
+
+// Start begins an authorization request for userKey and redirects the
+// browser to the authorization server. It sets the AuthStateCookie, an
+// HttpOnly cookie holding a hash of the request's state, which ServeHTTP
+// requires on the callback.
+func (f *AuthCodeFlow) Start(w http.ResponseWriter, r *http.Request, userKey string) {
+	authURL, state, err := f.authCodeURL(r.Context(), userKey)
+	if err != nil {
+		f.fail(w, r, err)
+		return
+	}
+	http.SetCookie(w, f.stateCookie(stateHash(state), int(f.stateTTL()/time.Second)))
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// AuthCodeURL starts an authorization request for userKey and returns the
+// URL to send the user's browser to. The request is not bound to the
+// browser, so the callback must be completed with Exchange after checking
+// that it belongs to the user; ServeHTTP only completes requests begun with
+// Start.
+func (f *AuthCodeFlow) AuthCodeURL(ctx context.Context, userKey string) (string, error) {
+	authURL, _, err := f.authCodeURL(ctx, userKey)
+	return authURL, err
+}
+
+func (f *AuthCodeFlow) authCodeURL(ctx context.Context, userKey string) (authURL, state string, err error) {
+	if userKey == "" {
+		return "", "", ErrMissingUserKey
+	}
+	state, err = randomURLString()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := randomURLString()
+	if err != nil {
+		return "", "", err
+	}
+	if err := f.states().Put(ctx, state, PendingAuth{
+		UserKey:     userKey,
+		Verifier:    verifier,
+		RedirectURI: f.RedirectURL,
+		ExpiresAt:   time.Now().Add(f.stateTTL()),
+	}); err != nil {
+		return "", "", err
+	}
+
+	u, err := url.Parse(f.Client.authorizeURL())
+	if err != nil {
+		return "", "", err
+	}
+	scopes := f.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid"}
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", f.Client.DefaultCreds.ClientID)
+	q.Set("redirect_uri", f.RedirectURL)
+	q.Set("scope", strings.Join(scopes, " "))
+	q.Set("state", state)
+	q.Set("code_challenge", pkceChallenge(verifier))
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+	return u.String(), state, nil
+}
+
+// Exchange completes the request started with state, trading code for
+// tokens and saving them under its user key, which it returns.
+func (f *AuthCodeFlow) Exchange(ctx context.Context, state, code string) (string, error) {
+	pending, err := f.states().Take(ctx, state)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", pending.RedirectURI)
+	form.Set("code_verifier", pending.Verifier)
+	setClient(form, &f.Client.DefaultCreds)
+	if _, err := f.Client.doTokenRequest(ctx, form, pending.UserKey, nil, nil); err != nil {
+		return "", err
+	}
+	return pending.UserKey, nil
+}
+
+// ServeHTTP handles the authorization server's redirect back to
+// RedirectURL. Callbacks without the AuthStateCookie Start set for their
+// state fail with ErrAuthStateMismatch before the code is exchanged.
+func (f *AuthCodeFlow) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	q := r.URL.Query()
+	cookie, err := r.Cookie(AuthStateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(stateHash(q.Get("state")))) != 1 {
+		f.fail(w, r, ErrAuthStateMismatch)
+		return
+	}
+	// the cookie is spent either way
+	http.SetCookie(w, f.stateCookie("", -1))
+
+	if code := q.Get("error"); code != "" {
+		// the request is over either way
+		_, _ = f.states().Take(r.Context(), q.Get("state"))
+		f.fail(w, r, &AuthorizeError{Code: code, Description: q.Get("error_description")})
+		return
+	}
+
+	userKey, err := f.Exchange(r.Context(), q.Get("state"), q.Get("code"))
+	if err != nil {
+		f.fail(w, r, err)
+		return
+	}
+	if f.OnLogin != nil {
+		f.OnLogin(w, r, userKey)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte("Signed in. You can close this window.\n"))
+}
+
+func (f *AuthCodeFlow) fail(w http.ResponseWriter, r *http.Request, err error) {
+	if f.OnError != nil {
+		f.OnError(w, r, err)
+		return
+	}
+	var authErr *AuthorizeError
+	status := http.StatusBadGateway
+	if errors.Is(err, ErrAuthStateNotFound) || errors.Is(err, ErrAuthStateMismatch) || errors.As(err, &authErr) {
+		status = http.StatusBadRequest
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// stateCookie returns the AuthStateCookie, scoped to the callback path. It
+// is sent on the top-level redirect back from the authorization server,
+// which SameSite=Lax allows and Strict would not.
+func (f *AuthCodeFlow) stateCookie(value string, maxAge int) *http.Cookie {
+	c := &http.Cookie{
+		Name:     AuthStateCookie,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if u, err := url.Parse(f.RedirectURL); err == nil {
+		if u.Path != "" {
+			c.Path = u.Path
+		}
+		c.Secure = u.Scheme == "https"
+	}
+	return c
+}
+
+// stateHash keeps the state itself out of the cookie, hashing it like a
+// PKCE verifier.
+func stateHash(state string) string {
+	return pkceChallenge(state)
+}
+
+// randomURLString returns 256 random bits, URL-safe encoded; long enough
+// for both a state and a PKCE verifier.
+func randomURLString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,222 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Seann-Moser/mangadex/auth"
+	"github.com/Seann-Moser/mangadex/auth/authtest"
+)
+
+func newAuthCodeFlow(t *testing.T) (*auth.AuthCodeFlow, *authtest.Server) {
+	t.Helper()
+	c, idp := newTestClient(t)
+	idp.AddClient("mangadex-web", "")
+	idp.AuthorizeUser = "reader"
+	c.Endpoints.AuthorizeURL = idp.AuthorizeURL()
+	c.DefaultCreds = auth.OAuthCredentials{ClientID: "mangadex-web"}
+	return auth.NewAuthCodeFlow(c, "http://app.example.test/callback"), idp
+}
+
+// signIn follows the authorize URL like a browser and returns the callback
+// request the authorization server redirects to.
+func signIn(t *testing.T, idp *authtest.Server, authURL string) *http.Request {
+	t.Helper()
+	hc := idp.Client()
+	hc.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	resp, err := hc.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize: %s", resp.Status)
+	}
+	return httptest.NewRequest(http.MethodGet, resp.Header.Get("Location"), nil)
+}
+
+// start begins a request through Start and returns the authorize URL it
+// redirects to and the cookie it sets.
+func start(t *testing.T, flow *auth.AuthCodeFlow, userKey string) (string, *http.Cookie) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	flow.Start(rec, httptest.NewRequest(http.MethodGet, "http://app.example.test/login", nil), userKey)
+	if rec.Code != http.StatusFound {
+		t.Fatalf("start: %d %s", rec.Code, rec.Body)
+	}
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == auth.AuthStateCookie {
+			return rec.Header().Get("Location"), c
+		}
+	}
+	t.Fatal("start set no state cookie")
+	return "", nil
+}
+
+func TestAuthCodeFlow(t *testing.T) {
+	flow, idp := newAuthCodeFlow(t)
+	ctx := context.Background()
+
+	authURL, cookie := start(t, flow, "u1")
+	q, _ := url.Parse(authURL)
+	if got := q.Query().Get("code_challenge_method"); got != "S256" {
+		t.Fatalf("code_challenge_method = %q", got)
+	}
+	if !cookie.HttpOnly || cookie.SameSite != http.SameSiteLaxMode || cookie.Path != "/callback" || cookie.Value == q.Query().Get("state") {
+		t.Fatalf("state cookie = %+v", cookie)
+	}
+
+	callback := signIn(t, idp, authURL)
+	callback.AddCookie(cookie)
+	rec := httptest.NewRecorder()
+	flow.ServeHTTP(rec, callback)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("callback: %d %s", rec.Code, rec.Body)
+	}
+
+	tok, _, _, err := flow.Client.Store.Load(ctx, "u1")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if user, _ := idp.Username(tok.AccessToken); user != "reader" {
+		t.Fatalf("token issued to %q", user)
+	}
+
+	// the public client refreshes with its id alone
+	if _, err := flow.Client.Refresh(ctx, "u1"); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	// a state works once
+	rec = httptest.NewRecorder()
+	flow.ServeHTTP(rec, callback)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("replayed callback: %d", rec.Code)
+	}
+	if n := countGrants(idp, "authorization_code"); n != 1 {
+		t.Fatalf("%d code exchanges, want 1", n)
+	}
+}
+
+// tamperedStates hands out a different verifier than the one the request
+// was started with.
+type tamperedStates struct {
+	auth.AuthStateStore
+}
+
+func (s tamperedStates) Take(ctx context.Context, state string) (*auth.PendingAuth, error) {
+	p, err := s.AuthStateStore.Take(ctx, state)
+	if p != nil {
+		p.Verifier += "x"
+	}
+	return p, err
+}
+
+func TestAuthCodeFlowVerifiesPKCE(t *testing.T) {
+	flow, idp := newAuthCodeFlow(t)
+	flow.States = tamperedStates{auth.NewInMemoryAuthStateStore()}
+	var got error
+	flow.OnError = func(w http.ResponseWriter, _ *http.Request, err error) {
+		got = err
+		w.WriteHeader(http.StatusForbidden)
+	}
+
+	authURL, cookie := start(t, flow, "u1")
+	callback := signIn(t, idp, authURL)
+	callback.AddCookie(cookie)
+	flow.ServeHTTP(httptest.NewRecorder(), callback)
+
+	var tokErr *auth.TokenError
+	if !errors.As(got, &tokErr) || tokErr.Code != "invalid_grant" {
+		t.Fatalf("err = %v, want invalid_grant", got)
+	}
+	if _, err := flow.Client.Store.LoadRecord(context.Background(), "u1"); !errors.Is(err, auth.ErrTokenNotFound) {
+		t.Fatalf("tokens saved after failed exchange: %v", err)
+	}
+}
+
+func TestAuthCodeFlowExpiredState(t *testing.T) {
+	flow, idp := newAuthCodeFlow(t)
+	flow.StateTTL = time.Millisecond
+
+	authURL, err := flow.AuthCodeURL(context.Background(), "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	callback := signIn(t, idp, authURL)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := flow.Exchange(context.Background(), callback.URL.Query().Get("state"), callback.URL.Query().Get("code")); !errors.Is(err, auth.ErrAuthStateNotFound) {
+		t.Fatalf("err = %v, want ErrAuthStateNotFound", err)
+	}
+	if n := countGrants(idp, "authorization_code"); n != 0 {
+		t.Fatalf("%d code exchanges for an expired state", n)
+	}
+}
+
+func TestAuthCodeFlowDenied(t *testing.T) {
+	flow, idp := newAuthCodeFlow(t)
+	idp.AuthorizeUser = ""
+
+	authURL, cookie := start(t, flow, "u1")
+	callback := signIn(t, idp, authURL)
+	callback.AddCookie(cookie)
+	rec := httptest.NewRecorder()
+	flow.ServeHTTP(rec, callback)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("callback: %d %s", rec.Code, rec.Body)
+	}
+
+	// the denied request's state is spent
+	state := callback.URL.Query().Get("state")
+	if _, err := flow.States.Take(context.Background(), state); !errors.Is(err, auth.ErrAuthStateNotFound) {
+		t.Fatalf("state still pending: %v", err)
+	}
+}
+
+func TestAuthCodeFlowRequiresStateCookie(t *testing.T) {
+	flow, idp := newAuthCodeFlow(t)
+	ctx := context.Background()
+
+	// an attacker's callback lands in a browser that never started it
+	authURL, cookie := start(t, flow, "u1")
+	_, other := start(t, flow, "u2")
+	for name, c := range map[string]*http.Cookie{"no cookie": nil, "other request": other} {
+		callback := signIn(t, idp, authURL)
+		if c != nil {
+			callback.AddCookie(c)
+		}
+		rec := httptest.NewRecorder()
+		flow.ServeHTTP(rec, callback)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: callback %d %s", name, rec.Code, rec.Body)
+		}
+	}
+	if n := countGrants(idp, "authorization_code"); n != 0 {
+		t.Fatalf("%d code exchanges for mismatched callbacks", n)
+	}
+	if _, err := flow.Client.Store.LoadRecord(ctx, "u1"); !errors.Is(err, auth.ErrTokenNotFound) {
+		t.Fatalf("tokens saved for a mismatched callback: %v", err)
+	}
+
+	// the browser that started the request still completes it
+	callback := signIn(t, idp, authURL)
+	callback.AddCookie(cookie)
+	rec := httptest.NewRecorder()
+	flow.ServeHTTP(rec, callback)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("callback: %d %s", rec.Code, rec.Body)
+	}
+	var cleared bool
+	for _, c := range rec.Result().Cookies() {
+		cleared = cleared || c.Name == auth.AuthStateCookie && c.MaxAge < 0
+	}
+	if !cleared {
+		t.Error("state cookie not cleared")
+	}
+}
@@ -0,0 +1,376 @@
+// Package authtest provides a fake Keycloak-style identity provider for
+// exercising OAuth flows against httptest.
+package authtest
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Paths served by Server, matching Keycloak's OpenID Connect layout.
+const (
+	AuthorizePath  = "/realms/mangadex/protocol/openid-connect/auth"
+	TokenPath      = "/realms/mangadex/protocol/openid-connect/token"
+	RevocationPath = "/realms/mangadex/protocol/openid-connect/revoke"
+)
+
+// codeTTL is how long an authorization code can be exchanged.
+const codeTTL = time.Minute
+
+// Failure makes the next token requests fail.
+type Failure struct {
+	StatusCode  int
+	Code        string
+	Description string
+
+	// Times is how many requests fail; zero means every request until
+	// ClearFailures.
+	Times int
+}
+
+// Server is a fake identity provider supporting the password,
+// authorization_code (with PKCE) and refresh_token grants and token
+// revocation.
+type Server struct {
+	*httptest.Server
+
+	// AccessTokenTTL and RefreshTokenTTL set expires_in and
+	// refresh_expires_in. Refresh tokens are rejected once expired.
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	// RotateRefreshTokens issues a new refresh token on every refresh and
+	// invalidates the old one. When false the refresh response omits it.
+	RotateRefreshTokens bool
+
+	// Now is the server clock, replaceable to expire refresh tokens.
+	Now func() time.Time
+
+	// AuthorizeUser is the user who signs in at the authorize endpoint.
+	// When empty the sign-in is denied with access_denied.
+	AuthorizeUser string
+
+	mu       sync.Mutex
+	users    map[string]string
+	clients  map[string]string
+	refresh  map[string]session
+	codes    map[string]authCode
+	access   map[string]string
+	failures []Failure
+	requests []Request
+}
+
+type session struct {
+	username  string
+	clientID  string
+	expiresAt time.Time
+}
+
+// authCode is an issued authorization code and what it was bound to.
+type authCode struct {
+	session
+	redirectURI string
+	challenge   string
+}
+
+// Request records a call to the authorize, token or revocation endpoint.
+type Request struct {
+	Path      string
+	GrantType string
+	ClientID  string
+	Username  string
+}
+
+// NewServer starts a fake identity provider. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		AccessTokenTTL:      15 * time.Minute,
+		RefreshTokenTTL:     30 * 24 * time.Hour,
+		RotateRefreshTokens: true,
+		Now:                 time.Now,
+		users:               map[string]string{},
+		clients:             map[string]string{},
+		refresh:             map[string]session{},
+		codes:               map[string]authCode{},
+		access:              map[string]string{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+AuthorizePath, s.authorize)
+	mux.HandleFunc("POST "+TokenPath, s.token)
+	mux.HandleFunc("POST "+RevocationPath, s.revoke)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// AuthorizeURL, TokenURL and RevocationURL are the endpoints to configure
+// the client with.
+func (s *Server) AuthorizeURL() string  { return s.URL + AuthorizePath }
+func (s *Server) TokenURL() string      { return s.URL + TokenPath }
+func (s *Server) RevocationURL() string { return s.URL + RevocationPath }
+
+// AddUser registers a resource owner for the password grant.
+func (s *Server) AddUser(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[username] = password
+}
+
+// AddClient registers an OAuth client. An empty secret registers a public
+// client, which authenticates with its client_id alone.
+func (s *Server) AddClient(clientID, secret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clients[clientID] = secret
+}
+
+// Fail queues a failure for upcoming token requests.
+func (s *Server) Fail(f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, f)
+}
+
+// ClearFailures drops all queued failures.
+func (s *Server) ClearFailures() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = nil
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Username returns the user an access token was issued to.
+func (s *Server) Username(accessToken string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.access[accessToken]
+	return u, ok
+}
+
+// ExpireRefreshTokens makes every issued refresh token invalid.
+func (s *Server) ExpireRefreshTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range s.refresh {
+		v.expiresAt = time.Time{}
+		s.refresh[k] = v
+	}
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	grant := r.PostForm.Get("grant_type")
+	clientID := r.PostForm.Get("client_id")
+	s.requests = append(s.requests, Request{
+		Path:      TokenPath,
+		GrantType: grant,
+		ClientID:  clientID,
+		Username:  r.PostForm.Get("username"),
+	})
+
+	if f, ok := s.nextFailure(); ok {
+		writeError(w, f.StatusCode, f.Code, f.Description)
+		return
+	}
+	if secret, ok := s.clients[clientID]; !ok || secret != r.PostForm.Get("client_secret") {
+		writeError(w, http.StatusUnauthorized, "unauthorized_client", "Invalid client or Invalid client credentials")
+		return
+	}
+
+	switch grant {
+	case "password":
+		username := r.PostForm.Get("username")
+		if pw, ok := s.users[username]; !ok || pw != r.PostForm.Get("password") {
+			writeError(w, http.StatusUnauthorized, "invalid_grant", "Invalid user credentials")
+			return
+		}
+		s.issue(w, session{username: username, clientID: clientID}, true)
+
+	case "authorization_code":
+		code, ok := s.codes[r.PostForm.Get("code")]
+		delete(s.codes, r.PostForm.Get("code"))
+		if !ok || code.clientID != clientID || !s.Now().Before(code.expiresAt) {
+			writeError(w, http.StatusBadRequest, "invalid_grant", "Code not valid")
+			return
+		}
+		if code.redirectURI != r.PostForm.Get("redirect_uri") {
+			writeError(w, http.StatusBadRequest, "invalid_grant", "Incorrect redirect_uri")
+			return
+		}
+		if pkceChallenge(r.PostForm.Get("code_verifier")) != code.challenge {
+			writeError(w, http.StatusBadRequest, "invalid_grant", "PKCE verification failed")
+			return
+		}
+		s.issue(w, code.session, true)
+
+	case "refresh_token":
+		old := r.PostForm.Get("refresh_token")
+		sess, ok := s.refresh[old]
+		if !ok || sess.clientID != clientID {
+			writeError(w, http.StatusBadRequest, "invalid_grant", "Invalid refresh token")
+			return
+		}
+		if !s.Now().Before(sess.expiresAt) {
+			delete(s.refresh, old)
+			writeError(w, http.StatusBadRequest, "invalid_grant", "Token is not active")
+			return
+		}
+		if s.RotateRefreshTokens {
+			delete(s.refresh, old)
+		}
+		s.issue(w, sess, s.RotateRefreshTokens)
+
+	default:
+		writeError(w, http.StatusBadRequest, "unsupported_grant_type", "Unsupported grant_type")
+	}
+}
+
+// issue writes a token response; the caller holds s.mu.
+func (s *Server) issue(w http.ResponseWriter, sess session, newRefresh bool) {
+	access := randomToken()
+	s.access[access] = sess.username
+	body := map[string]any{
+		"access_token": access,
+		"token_type":   "Bearer",
+		"expires_in":   int(s.AccessTokenTTL / time.Second),
+		"scope":        "openid groups profile email",
+	}
+	if newRefresh {
+		refresh := randomToken()
+		sess.expiresAt = s.Now().Add(s.RefreshTokenTTL)
+		s.refresh[refresh] = sess
+		body["refresh_token"] = refresh
+		body["refresh_expires_in"] = int(s.RefreshTokenTTL / time.Second)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// authorize signs AuthorizeUser in and redirects back with a code. Only
+// PKCE requests with the S256 method are accepted.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clientID := q.Get("client_id")
+	s.requests = append(s.requests, Request{Path: AuthorizePath, ClientID: clientID})
+	if _, ok := s.clients[clientID]; !ok {
+		http.Error(w, "Client not found", http.StatusBadRequest)
+		return
+	}
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || !redirect.IsAbs() {
+		http.Error(w, "Invalid parameter: redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	back := redirect.Query()
+	back.Set("state", q.Get("state"))
+	switch {
+	case q.Get("response_type") != "code":
+		back.Set("error", "unsupported_response_type")
+	case q.Get("code_challenge") == "" || q.Get("code_challenge_method") != "S256":
+		back.Set("error", "invalid_request")
+		back.Set("error_description", "Missing parameter: code_challenge_method")
+	case s.AuthorizeUser == "":
+		back.Set("error", "access_denied")
+	default:
+		code := randomToken()
+		s.codes[code] = authCode{
+			session:     session{username: s.AuthorizeUser, clientID: clientID, expiresAt: s.Now().Add(codeTTL)},
+			redirectURI: q.Get("redirect_uri"),
+			challenge:   q.Get("code_challenge"),
+		}
+		back.Set("code", code)
+	}
+	redirect.RawQuery = back.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (s *Server) revoke(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clientID := r.PostForm.Get("client_id")
+	s.requests = append(s.requests, Request{Path: RevocationPath, ClientID: clientID})
+	if f, ok := s.nextFailure(); ok {
+		writeError(w, f.StatusCode, f.Code, f.Description)
+		return
+	}
+	if secret, ok := s.clients[clientID]; !ok || secret != r.PostForm.Get("client_secret") {
+		writeError(w, http.StatusUnauthorized, "unauthorized_client", "Invalid client or Invalid client credentials")
+		return
+	}
+	// unknown tokens are not an error, per RFC 7009
+	delete(s.refresh, r.PostForm.Get("token"))
+	w.WriteHeader(http.StatusOK)
+}
+
+// nextFailure pops a queued failure; the caller holds s.mu.
+func (s *Server) nextFailure() (Failure, bool) {
+	if len(s.failures) == 0 {
+		return Failure{}, false
+	}
+	f := s.failures[0]
+	if f.Times > 0 {
+		f.Times--
+		if f.Times == 0 {
+			s.failures = s.failures[1:]
+		} else {
+			s.failures[0] = f
+		}
+	}
+	if f.StatusCode == 0 {
+		f.StatusCode = http.StatusInternalServerError
+	}
+	return f, true
+}
+
+func writeError(w http.ResponseWriter, status int, code, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	body := map[string]string{}
+	if code != "" {
+		body["error"] = code
+	}
+	if description != "" {
+		body["error_description"] = description
+	}
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomToken() string {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -12,77 +14,438 @@ import (
 	"time"
 )
 
-const (
-	tokenURL  = "https://auth.mangadex.org/realms/mangadex/protocol/openid-connect/token"
-	apiDomain = "api.mangadex.org"
+// Endpoints locates the identity provider and the API hosts that receive
+// bearer tokens.
+type Endpoints struct {
+	AuthorizeURL  string
+	TokenURL      string
+	RevocationURL string
+
+	// APIHosts are the hosts ApplyAuth adds the Authorization header for.
+	// An entry without a port matches any port.
+	APIHosts []string
+}
+
+var (
+	// MangaDexEndpoints is the production identity provider and API.
+	MangaDexEndpoints = Endpoints{
+		AuthorizeURL:  "https://auth.mangadex.org/realms/mangadex/protocol/openid-connect/auth",
+		TokenURL:      "https://auth.mangadex.org/realms/mangadex/protocol/openid-connect/token",
+		RevocationURL: "https://auth.mangadex.org/realms/mangadex/protocol/openid-connect/revoke",
+		APIHosts:      []string{"api.mangadex.org"},
+	}
+
+	// SandboxEndpoints is the MangaDex developer sandbox.
+	SandboxEndpoints = Endpoints{
+		AuthorizeURL:  "https://auth.mangadex.dev/realms/mangadex/protocol/openid-connect/auth",
+		TokenURL:      "https://auth.mangadex.dev/realms/mangadex/protocol/openid-connect/token",
+		RevocationURL: "https://auth.mangadex.dev/realms/mangadex/protocol/openid-connect/revoke",
+		APIHosts:      []string{"api.mangadex.dev"},
+	}
+)
+
+// DefaultRefreshBefore is how long before expiry an access token is
+// refreshed.
+const DefaultRefreshBefore = time.Minute
+
+var (
+	ErrMissingUserKey = errors.New("missing user key")
+
+	// ErrSessionExpired is returned when the refresh token has expired and
+	// the user has to log in again.
+	ErrSessionExpired = errors.New("session expired")
+
+	errNoAccessToken  = errors.New("no access token")
+	errNoRefreshToken = errors.New("no refresh token")
 )
 
+// TokenError is an error response from the token or revocation endpoint.
+type TokenError struct {
+	StatusCode  int
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *TokenError) Error() string {
+	msg := fmt.Sprintf("oauth request failed: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Code != "" {
+		msg += ": " + e.Code
+	}
+	if e.Description != "" {
+		msg += " (" + e.Description + ")"
+	}
+	return msg
+}
+
 type OAuthClient struct {
 	HTTPClient *http.Client
 
+	// Endpoints defaults to MangaDexEndpoints; empty fields fall back to
+	// their production value.
+	Endpoints Endpoints
+
 	// defaults (used when user has none)
 	DefaultCreds OAuthCredentials
 
 	UserKey string
 	Store   TokenStore
 
-	mu        sync.Mutex
+	// RefreshBefore is how long before expiry a token is refreshed in the
+	// background; requests keep using it until it expires. Defaults to
+	// DefaultRefreshBefore.
+	RefreshBefore time.Duration
+
+	mu      sync.Mutex
+	hot     map[string]hotToken
+	flights map[string]*refreshFlight
+}
+
+// hotToken is the in-memory copy of a user's latest tokens, so requests do
+// not hit the store.
+type hotToken struct {
 	tokens    OAuthTokens
-	creds     OAuthCredentials
+	creds     *OAuthCredentials
 	expiresAt time.Time
+
+	// version is the store entry's version when loaded from the store.
+	version int64
+}
+
+// refreshExpiresAt derives the refresh token's expiry from the access
+// token's, as both lifetimes count from the same response. It is zero when
+// the provider did not report one.
+func (h hotToken) refreshExpiresAt() time.Time {
+	if h.tokens.RefreshExpiresIn <= 0 {
+		return time.Time{}
+	}
+	issued := h.expiresAt.Add(-time.Duration(h.tokens.ExpiresIn) * time.Second)
+	return issued.Add(time.Duration(h.tokens.RefreshExpiresIn) * time.Second)
+}
+
+// refreshFlight is a refresh in progress; concurrent callers for the same
+// user wait on it instead of spending the refresh token again.
+type refreshFlight struct {
+	done chan struct{}
+	tok  *OAuthTokens
+	err  error
+}
+
+func (c *OAuthClient) refreshBefore() time.Duration {
+	if c.RefreshBefore > 0 {
+		return c.RefreshBefore
+	}
+	return DefaultRefreshBefore
+}
+
+func (c *OAuthClient) authorizeURL() string {
+	if c.Endpoints.AuthorizeURL != "" {
+		return c.Endpoints.AuthorizeURL
+	}
+	return MangaDexEndpoints.AuthorizeURL
+}
+
+func (c *OAuthClient) tokenURL() string {
+	if c.Endpoints.TokenURL != "" {
+		return c.Endpoints.TokenURL
+	}
+	return MangaDexEndpoints.TokenURL
+}
+
+func (c *OAuthClient) revocationURL() string {
+	if c.Endpoints.RevocationURL != "" {
+		return c.Endpoints.RevocationURL
+	}
+	return MangaDexEndpoints.RevocationURL
+}
+
+// isAPIHost reports whether u points at one of the configured API hosts.
+func (c *OAuthClient) isAPIHost(u *url.URL) bool {
+	hosts := c.Endpoints.APIHosts
+	if len(hosts) == 0 {
+		hosts = MangaDexEndpoints.APIHosts
+	}
+	for _, h := range hosts {
+		if _, _, err := net.SplitHostPort(h); err == nil {
+			if strings.EqualFold(u.Host, h) {
+				return true
+			}
+			continue
+		}
+		if strings.EqualFold(u.Hostname(), h) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *OAuthClient) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
 }
 
 func (c *OAuthClient) Login(ctx context.Context, username string, password string, creds *OAuthCredentials) error {
-	if creds != nil || creds.UserKey == "" {
-		return fmt.Errorf("missing user key")
+	if creds == nil || creds.UserKey == "" {
+		return ErrMissingUserKey
 	}
+	resolved := c.resolveCreds(creds)
 	form := url.Values{}
 	form.Set("grant_type", "password")
 	form.Set("username", username)
 	form.Set("password", password)
-	form.Set("client_id", creds.ClientID)
-	form.Set("client_secret", creds.ClientSecret)
-	return c.doTokenRequest(ctx, form, creds)
+	setClient(form, resolved)
+	_, err := c.doTokenRequest(ctx, form, creds.UserKey, creds, nil)
+	return err
 }
 
 func (c *OAuthClient) ApplyAuth(userKey string) func(ctx context.Context, req *http.Request) error {
 	return func(ctx context.Context, req *http.Request) error {
-		if !strings.Contains(req.URL.Host, apiDomain) {
+		if !c.isAPIHost(req.URL) {
 			return nil
 		}
 
-		tok, cred, exp, err := c.LoadFromStore(ctx, userKey)
+		tok, err := c.Token(ctx, userKey)
 		if err != nil {
 			return err
 		}
-		expired := time.Now().After(exp)
+		req.Header.Set("Authorization", "Bearer "+tok.AccessToken)
+		return nil
+	}
+}
 
-		if expired {
-			if err := c.refresh(ctx, tok, cred); err != nil {
-				return err
-			}
+// Token returns a usable access token for userKey, served from memory once
+// loaded. Within RefreshBefore of expiry the token is refreshed in the
+// background; once it has expired callers wait for the refresh. Concurrent
+// refreshes for the same user share a single token request.
+func (c *OAuthClient) Token(ctx context.Context, userKey string) (*OAuthTokens, error) {
+	hot, ok := c.hotToken(userKey)
+	if !ok {
+		var err error
+		if hot, err = c.current(ctx, userKey); err != nil {
+			return nil, err
 		}
+		c.remember(userKey, hot)
+	}
 
-		if tok.AccessToken == "" {
-			return errors.New("no access token")
+	now := time.Now()
+	if hot.tokens.AccessToken != "" && now.Before(hot.expiresAt) {
+		if !now.Before(hot.expiresAt.Add(-c.refreshBefore())) {
+			c.startRefresh(ctx, userKey, false)
 		}
+		tok := hot.tokens
+		return &tok, nil
+	}
 
-		req.Header.Set("Authorization", "Bearer "+tok.AccessToken)
+	return c.wait(ctx, c.startRefresh(ctx, userKey, false))
+}
+
+// Refresh exchanges the user's refresh token now, even if the access token
+// is still fresh, sharing a refresh already in progress.
+func (c *OAuthClient) Refresh(ctx context.Context, userKey string) (*OAuthTokens, error) {
+	return c.wait(ctx, c.startRefresh(ctx, userKey, true))
+}
+
+func (c *OAuthClient) wait(ctx context.Context, f *refreshFlight) (*OAuthTokens, error) {
+	select {
+	case <-f.done:
+		return f.tok, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// startRefresh joins the user's refresh in progress or starts one. The
+// refresh outlives ctx's cancellation so that other waiters still get its
+// result.
+func (c *OAuthClient) startRefresh(ctx context.Context, userKey string, force bool) *refreshFlight {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if f, ok := c.flights[userKey]; ok {
+		return f
+	}
+	if c.flights == nil {
+		c.flights = map[string]*refreshFlight{}
+	}
+	f := &refreshFlight{done: make(chan struct{})}
+	c.flights[userKey] = f
+
+	go func() {
+		f.tok, f.err = c.refreshUser(context.WithoutCancel(ctx), userKey, force)
+		c.mu.Lock()
+		delete(c.flights, userKey)
+		c.mu.Unlock()
+		close(f.done)
+	}()
+	return f
+}
+
+// refreshUser refreshes from the stored tokens rather than the in-memory
+// copy, since another instance sharing the store may have rotated the
+// refresh token already; unless forced, a fresh access token it left is
+// used as is.
+func (c *OAuthClient) refreshUser(ctx context.Context, userKey string, force bool) (*OAuthTokens, error) {
+	cur, err := c.current(ctx, userKey)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !force && cur.tokens.AccessToken != "" && now.Before(cur.expiresAt.Add(-c.refreshBefore())) {
+		c.remember(userKey, cur)
+		return &cur.tokens, nil
+	}
+	if cur.tokens.RefreshToken == "" {
+		return nil, errNoRefreshToken
+	}
+	if exp := cur.refreshExpiresAt(); !exp.IsZero() && !now.Before(exp) {
+		return nil, ErrSessionExpired
+	}
+
+	tok, err := c.refresh(ctx, userKey, cur)
+	var te *TokenError
+	if errors.Is(err, ErrVersionConflict) || errors.As(err, &te) && te.Code == "invalid_grant" {
+		if latest, ok := c.awaitPeerRefresh(ctx, userKey, cur); ok {
+			return latest, nil
+		}
+	}
+	return tok, err
+}
+
+// awaitPeerRefresh looks for tokens another replica sharing the store got
+// by refreshing concurrently. With rotating refresh tokens only one refresh
+// succeeds, and the winner may not have saved yet when the loser's fails,
+// so the store is checked a few times.
+func (c *OAuthClient) awaitPeerRefresh(ctx context.Context, userKey string, prev hotToken) (*OAuthTokens, bool) {
+	if c.Store == nil {
+		return nil, false
+	}
+	for i := 1; i <= 3; i++ {
+		latest, err := c.current(ctx, userKey)
+		if err == nil && latest.tokens.RefreshToken != prev.tokens.RefreshToken &&
+			latest.tokens.AccessToken != "" && time.Now().Before(latest.expiresAt) {
+			c.remember(userKey, latest)
+			return &latest.tokens, true
+		}
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-time.After(time.Duration(i) * 50 * time.Millisecond):
+		}
+	}
+	return nil, false
+}
+
+// current loads the user's tokens from the store, or from memory when the
+// client has no store.
+func (c *OAuthClient) current(ctx context.Context, userKey string) (hotToken, error) {
+	if c.Store == nil {
+		hot, ok := c.hotToken(userKey)
+		if !ok {
+			return hotToken{}, errNoAccessToken
+		}
+		return hot, nil
+	}
+	rec, err := c.Store.LoadRecord(ctx, userKey)
+	if err != nil {
+		return hotToken{}, err
+	}
+	return hotToken{
+		tokens:    rec.Tokens,
+		creds:     c.resolveCreds(rec.Creds),
+		expiresAt: rec.ExpiresAt,
+		version:   rec.Version,
+	}, nil
+}
+
+func (c *OAuthClient) remember(userKey string, hot hotToken) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.hot == nil {
+		c.hot = map[string]hotToken{}
+	}
+	c.hot[userKey] = hot
+}
+
+func (c *OAuthClient) hotToken(userKey string) (hotToken, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	hot, ok := c.hot[userKey]
+	return hot, ok
+}
+
+// Forget drops the in-memory copy of the user's tokens, so the next request
+// reloads them from the store.
+func (c *OAuthClient) Forget(userKey string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.hot, userKey)
+}
+
+// Revoke revokes the user's refresh token, which ends the session at the
+// identity provider.
+func (c *OAuthClient) Revoke(ctx context.Context, userKey string) error {
+	cur, err := c.current(ctx, userKey)
+	if err != nil {
+		return err
+	}
+	tok, creds := cur.tokens, cur.creds
+	if tok.RefreshToken == "" {
+		return errNoRefreshToken
+	}
+	form := url.Values{}
+	form.Set("token", tok.RefreshToken)
+	form.Set("token_type_hint", "refresh_token")
+	setClient(form, creds)
+
+	resp, err := c.postForm(ctx, c.revocationURL(), form)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return tokenError(resp)
+	}
+	c.Forget(userKey)
+	return nil
+}
+
+// Logout revokes the user's session and deletes their tokens.
+func (c *OAuthClient) Logout(ctx context.Context, userKey string) error {
+	err := c.Revoke(ctx, userKey)
+	if err != nil && !errors.Is(err, ErrTokenNotFound) && !errors.Is(err, errNoAccessToken) {
+		return err
+	}
+	c.Forget(userKey)
+	if c.Store == nil {
 		return nil
 	}
+	return c.Store.Delete(ctx, userKey)
 }
 
-func (c *OAuthClient) storeTokens(tok OAuthTokens, creds *OAuthCredentials) {
-	if c.Store != nil {
-		_ = c.Store.Save(
-			context.Background(),
-			creds.UserKey,
-			tok,
-			creds,
-			time.Now().Add(time.Duration(tok.ExpiresIn-30)*time.Second),
-		)
-	} else {
+// storeTokens saves tok; refreshes (prev set) only overwrite the entry they
+// were loaded from.
+func (c *OAuthClient) storeTokens(ctx context.Context, userKey string, tok OAuthTokens, creds *OAuthCredentials, expiresAt time.Time, prev *hotToken) error {
+	if c.Store == nil {
+		return nil
+	}
+	if prev == nil {
+		return c.Store.Save(ctx, userKey, tok, creds, expiresAt)
+	}
 
+	version := prev.version
+	for attempt := 0; ; attempt++ {
+		err := c.Store.SaveIfUnchanged(ctx, userKey, version, tok, creds, expiresAt)
+		if !errors.Is(err, ErrVersionConflict) || attempt == 2 {
+			return err
+		}
+		// an entry still holding the refresh token we spent changed for
+		// another reason, such as re-encryption, and ours are the newest
+		// tokens, so write over it
+		rec, lerr := c.Store.LoadRecord(ctx, userKey)
+		if lerr != nil || rec.Tokens.RefreshToken != prev.tokens.RefreshToken {
+			return err
+		}
+		version = rec.Version
 	}
 }
 
@@ -108,44 +471,83 @@ func (c *OAuthClient) resolveCreds(userCreds *OAuthCredentials) *OAuthCredential
 	return &c.DefaultCreds
 }
 
-func (c *OAuthClient) refresh(ctx context.Context, tok *OAuthTokens, creds *OAuthCredentials) error {
+// refresh exchanges the refresh token and returns the new tokens.
+func (c *OAuthClient) refresh(ctx context.Context, userKey string, prev hotToken) (*OAuthTokens, error) {
+	creds := prev.creds
 	form := url.Values{}
 	form.Set("grant_type", "refresh_token")
-	form.Set("refresh_token", tok.RefreshToken)
-	form.Set("client_id", creds.ClientID)
-	form.Set("client_secret", creds.ClientSecret)
+	form.Set("refresh_token", prev.tokens.RefreshToken)
+	setClient(form, creds)
 
-	return c.doTokenRequest(ctx, form, creds)
-}
-
-func (c *OAuthClient) doTokenRequest(ctx context.Context, form url.Values, creds *OAuthCredentials) error {
-	req, err := http.NewRequestWithContext(
-		ctx,
-		http.MethodPost,
-		tokenURL,
-		strings.NewReader(form.Encode()),
-	)
-	if err != nil {
-		return err
+	// only persist credentials that belong to the user, never the defaults
+	var userCreds *OAuthCredentials
+	if creds != &c.DefaultCreds {
+		userCreds = creds
 	}
+	return c.doTokenRequest(ctx, form, userKey, userCreds, &prev)
+}
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-
-	resp, err := c.HTTPClient.Do(req)
+// doTokenRequest posts a grant and stores the tokens it returns. Providers
+// that do not rotate refresh tokens omit them on refresh, so prev's refresh
+// token and its expiry are kept.
+func (c *OAuthClient) doTokenRequest(ctx context.Context, form url.Values, userKey string, creds *OAuthCredentials, prev *hotToken) (*OAuthTokens, error) {
+	resp, err := c.postForm(ctx, c.tokenURL(), form)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.New("oauth request failed")
+		return nil, tokenError(resp)
 	}
 
 	var tok OAuthTokens
 	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
-		return err
+		return nil, err
+	}
+	now := time.Now()
+	if tok.RefreshToken == "" && prev != nil {
+		tok.RefreshToken = prev.tokens.RefreshToken
+		if exp := prev.refreshExpiresAt(); !exp.IsZero() {
+			tok.RefreshExpiresIn = int(exp.Sub(now) / time.Second)
+		}
 	}
 
-	c.storeTokens(tok, creds)
-	return nil
+	expiresAt := now.Add(time.Duration(tok.ExpiresIn) * time.Second)
+	if err := c.storeTokens(ctx, userKey, tok, creds, expiresAt, prev); err != nil {
+		return nil, err
+	}
+	c.remember(userKey, hotToken{tokens: tok, creds: c.resolveCreds(creds), expiresAt: expiresAt})
+	return &tok, nil
+}
+
+// setClient adds the client authentication to form. Public clients have no
+// secret and send their id alone.
+func setClient(form url.Values, creds *OAuthCredentials) {
+	form.Set("client_id", creds.ClientID)
+	if creds.ClientSecret != "" {
+		form.Set("client_secret", creds.ClientSecret)
+	}
+}
+
+func (c *OAuthClient) postForm(ctx context.Context, endpoint string, form url.Values) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		endpoint,
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return c.httpClient().Do(req)
+}
+
+func tokenError(resp *http.Response) error {
+	e := &TokenError{StatusCode: resp.StatusCode}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	_ = json.Unmarshal(body, e)
+	return e
 }
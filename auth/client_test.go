@@ -0,0 +1,304 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Seann-Moser/mangadex/auth"
+	"github.com/Seann-Moser/mangadex/auth/authtest"
+)
+
+func newTestClient(t *testing.T) (*auth.OAuthClient, *authtest.Server) {
+	t.Helper()
+	idp := authtest.NewServer()
+	t.Cleanup(idp.Close)
+	idp.AddClient("personal-client", "secret")
+	idp.AddUser("reader", "hunter2")
+
+	c := &auth.OAuthClient{
+		HTTPClient: idp.Client(),
+		Endpoints: auth.Endpoints{
+			TokenURL:      idp.TokenURL(),
+			RevocationURL: idp.RevocationURL(),
+			APIHosts:      []string{"api.example.test"},
+		},
+		DefaultCreds: auth.OAuthCredentials{ClientID: "personal-client", ClientSecret: "secret"},
+		Store:        auth.NewInMemoryTokenStore(),
+	}
+	return c, idp
+}
+
+func authorize(t *testing.T, c *auth.OAuthClient, userKey, rawURL string) (string, error) {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &http.Request{URL: u, Header: http.Header{}}
+	err = c.ApplyAuth(userKey)(context.Background(), req)
+	return strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "), err
+}
+
+func TestLogin(t *testing.T) {
+	c, idp := newTestClient(t)
+	ctx := context.Background()
+
+	if err := c.Login(ctx, "reader", "hunter2", &auth.OAuthCredentials{UserKey: "u1"}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	tok, _, exp, err := c.Store.Load(ctx, "u1")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if tok.AccessToken == "" || tok.RefreshToken == "" {
+		t.Fatalf("tokens not stored: %+v", tok)
+	}
+	if !exp.After(time.Now()) {
+		t.Errorf("expiry %v is not in the future", exp)
+	}
+
+	bearer, err := authorize(t, c, "u1", "https://api.example.test/user/follows/manga")
+	if err != nil {
+		t.Fatalf("apply auth: %v", err)
+	}
+	if user, ok := idp.Username(bearer); !ok || user != "reader" {
+		t.Errorf("bearer %q belongs to %q, want reader", bearer, user)
+	}
+
+	bearer, err = authorize(t, c, "u1", "https://uploads.example.test/covers/x.jpg")
+	if err != nil || bearer != "" {
+		t.Errorf("non-API host got bearer %q, err %v", bearer, err)
+	}
+}
+
+func TestLoginFailure(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+
+	err := c.Login(ctx, "reader", "wrong", &auth.OAuthCredentials{UserKey: "u1"})
+	var te *auth.TokenError
+	if !errors.As(err, &te) || te.Code != "invalid_grant" || te.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("got %v, want 401 invalid_grant", err)
+	}
+
+	err = c.Login(ctx, "reader", "hunter2", &auth.OAuthCredentials{})
+	if !errors.Is(err, auth.ErrMissingUserKey) {
+		t.Errorf("got %v, want ErrMissingUserKey", err)
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	c, idp := newTestClient(t)
+	ctx := context.Background()
+	// tokens expire as soon as they are issued
+	idp.AccessTokenTTL = 0
+
+	if err := c.Login(ctx, "reader", "hunter2", &auth.OAuthCredentials{UserKey: "u1"}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	before, _, _, _ := c.Store.Load(ctx, "u1")
+
+	bearer, err := authorize(t, c, "u1", "https://api.example.test/user/me")
+	if err != nil {
+		t.Fatalf("apply auth: %v", err)
+	}
+	if bearer == "" || bearer == before.AccessToken {
+		t.Errorf("bearer %q was not refreshed", bearer)
+	}
+	after, _, _, _ := c.Store.Load(ctx, "u1")
+	if after.AccessToken != bearer || after.RefreshToken == before.RefreshToken {
+		t.Errorf("refreshed tokens not stored: %+v", after)
+	}
+
+	reqs := idp.Requests()
+	if last := reqs[len(reqs)-1]; last.GrantType != "refresh_token" {
+		t.Errorf("last grant %q, want refresh_token", last.GrantType)
+	}
+}
+
+func TestRefreshWithoutRotation(t *testing.T) {
+	c, idp := newTestClient(t)
+	ctx := context.Background()
+	idp.AccessTokenTTL = 0
+	idp.RotateRefreshTokens = false
+
+	if err := c.Login(ctx, "reader", "hunter2", &auth.OAuthCredentials{UserKey: "u1"}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	before, _, _, _ := c.Store.Load(ctx, "u1")
+	if _, err := authorize(t, c, "u1", "https://api.example.test/user/me"); err != nil {
+		t.Fatalf("apply auth: %v", err)
+	}
+	after, _, _, _ := c.Store.Load(ctx, "u1")
+	if after.RefreshToken != before.RefreshToken {
+		t.Errorf("refresh token %q replaced, want %q kept", after.RefreshToken, before.RefreshToken)
+	}
+}
+
+func TestRefreshSingleFlight(t *testing.T) {
+	c, idp := newTestClient(t)
+	ctx := context.Background()
+	idp.AccessTokenTTL = 0
+
+	if err := c.Login(ctx, "reader", "hunter2", &auth.OAuthCredentials{UserKey: "u1"}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	idp.AccessTokenTTL = 15 * time.Minute
+
+	var wg sync.WaitGroup
+	bearers := make([]string, 20)
+	errs := make([]error, len(bearers))
+	for i := range bearers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bearers[i], errs[i] = authorize(t, c, "u1", "https://api.example.test/user/me")
+		}()
+	}
+	wg.Wait()
+
+	for i := range bearers {
+		if errs[i] != nil {
+			t.Fatalf("request %d: %v", i, errs[i])
+		}
+		if bearers[i] != bearers[0] {
+			t.Errorf("request %d used %q, want %q", i, bearers[i], bearers[0])
+		}
+	}
+	if n := countGrants(idp, "refresh_token"); n != 1 {
+		t.Errorf("%d refresh grants, want 1", n)
+	}
+}
+
+func TestRefreshAcrossReplicas(t *testing.T) {
+	a, idp := newTestClient(t)
+	ctx := context.Background()
+	idp.AccessTokenTTL = 0
+
+	for i := 0; i < 10; i++ {
+		if err := a.Login(ctx, "reader", "hunter2", &auth.OAuthCredentials{UserKey: "u1"}); err != nil {
+			t.Fatalf("login: %v", err)
+		}
+		idp.AccessTokenTTL = 15 * time.Minute
+
+		// a second process sharing the store, with its own hot tokens
+		b := &auth.OAuthClient{
+			HTTPClient:   a.HTTPClient,
+			Endpoints:    a.Endpoints,
+			DefaultCreds: a.DefaultCreds,
+			Store:        a.Store,
+		}
+		var wg sync.WaitGroup
+		var bearers [2]string
+		var errs [2]error
+		for j, c := range []*auth.OAuthClient{a, b} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				bearers[j], errs[j] = authorize(t, c, "u1", "https://api.example.test/user/me")
+			}()
+		}
+		wg.Wait()
+
+		if errs[0] != nil || errs[1] != nil {
+			t.Fatalf("round %d: %v, %v", i, errs[0], errs[1])
+		}
+		if bearers[0] != bearers[1] {
+			t.Fatalf("round %d: replicas use different tokens", i)
+		}
+		idp.AccessTokenTTL = 0
+	}
+}
+
+func TestProactiveRefresh(t *testing.T) {
+	c, idp := newTestClient(t)
+	ctx := context.Background()
+	// inside the client's refresh window but not yet expired
+	idp.AccessTokenTTL = 30 * time.Second
+
+	if err := c.Login(ctx, "reader", "hunter2", &auth.OAuthCredentials{UserKey: "u1"}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	before, _, _, _ := c.Store.Load(ctx, "u1")
+	idp.AccessTokenTTL = 15 * time.Minute
+
+	bearer, err := authorize(t, c, "u1", "https://api.example.test/user/me")
+	if err != nil {
+		t.Fatalf("apply auth: %v", err)
+	}
+	if bearer != before.AccessToken {
+		t.Errorf("request waited for refresh, want current token used")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for countGrants(idp, "refresh_token") == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	bearer, err = authorize(t, c, "u1", "https://api.example.test/user/me")
+	if err != nil {
+		t.Fatalf("apply auth: %v", err)
+	}
+	if bearer == before.AccessToken {
+		t.Errorf("token was not refreshed in the background")
+	}
+	if n := countGrants(idp, "refresh_token"); n != 1 {
+		t.Errorf("%d refresh grants, want 1", n)
+	}
+}
+
+func countGrants(idp *authtest.Server, grant string) int {
+	n := 0
+	for _, r := range idp.Requests() {
+		if r.GrantType == grant {
+			n++
+		}
+	}
+	return n
+}
+
+func TestRefreshFailure(t *testing.T) {
+	c, idp := newTestClient(t)
+	ctx := context.Background()
+	idp.AccessTokenTTL = 0
+
+	if err := c.Login(ctx, "reader", "hunter2", &auth.OAuthCredentials{UserKey: "u1"}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	idp.Fail(authtest.Failure{StatusCode: http.StatusServiceUnavailable, Times: 1})
+	_, err := authorize(t, c, "u1", "https://api.example.test/user/me")
+	var te *auth.TokenError
+	if !errors.As(err, &te) || te.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("got %v, want 503", err)
+	}
+
+	idp.ExpireRefreshTokens()
+	_, err = authorize(t, c, "u1", "https://api.example.test/user/me")
+	if !errors.As(err, &te) || te.Code != "invalid_grant" {
+		t.Fatalf("got %v, want invalid_grant", err)
+	}
+}
+
+func TestRevoke(t *testing.T) {
+	c, idp := newTestClient(t)
+	ctx := context.Background()
+	idp.AccessTokenTTL = 0
+
+	if err := c.Login(ctx, "reader", "hunter2", &auth.OAuthCredentials{UserKey: "u1"}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if err := c.Revoke(ctx, "u1"); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	_, err := authorize(t, c, "u1", "https://api.example.test/user/me")
+	var te *auth.TokenError
+	if !errors.As(err, &te) || te.Code != "invalid_grant" {
+		t.Errorf("refresh after revoke: got %v, want invalid_grant", err)
+	}
+}
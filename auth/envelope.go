@@ -0,0 +1,254 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// KeyWrapper protects data keys with a master key it holds, such as a key
+// in a cloud KMS. keyID names the master key, so data wrapped before a
+// rotation still unwraps.
+type KeyWrapper interface {
+	WrapKey(ctx context.Context, dataKey []byte) (keyID string, wrapped []byte, err error)
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// envelopeMagic starts every EnvelopeCipher ciphertext; the byte after it
+// is the format version.
+var envelopeMagic = []byte{'m', 'd', 'e'}
+
+const envelopeVersion = 1
+
+var _ AADCipher = (*EnvelopeCipher)(nil)
+
+// EnvelopeCipher encrypts each record with a fresh AES-256-GCM data key and
+// stores the data key wrapped by Wrapper next to it:
+//
+//	"mde" | version (1) | key id length (1) | key id |
+//	wrapped key length (2) | wrapped key | nonce | sealed data
+//
+// The header is authenticated along with the associated data.
+type EnvelopeCipher struct {
+	Wrapper KeyWrapper
+
+	// Timeout bounds each Wrapper call; zero means no limit.
+	Timeout time.Duration
+}
+
+func NewEnvelopeCipher(wrapper KeyWrapper) *EnvelopeCipher {
+	return &EnvelopeCipher{Wrapper: wrapper}
+}
+
+func (e *EnvelopeCipher) context() (context.Context, context.CancelFunc) {
+	if e.Timeout > 0 {
+		return context.WithTimeout(context.Background(), e.Timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+func (e *EnvelopeCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	return e.EncryptWithAD(plaintext, nil)
+}
+
+func (e *EnvelopeCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	return e.DecryptWithAD(ciphertext, nil)
+}
+
+func (e *EnvelopeCipher) EncryptWithAD(plaintext, ad []byte) ([]byte, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := e.context()
+	defer cancel()
+	keyID, wrapped, err := e.Wrapper.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("wrap data key: %w", err)
+	}
+	if keyID == "" || len(keyID) > 255 || len(wrapped) > 0xffff {
+		return nil, fmt.Errorf("wrapped key %q does not fit the header", keyID)
+	}
+
+	header := make([]byte, 0, len(envelopeMagic)+4+len(keyID)+len(wrapped))
+	header = append(header, envelopeMagic...)
+	header = append(header, envelopeVersion, byte(len(keyID)))
+	header = append(header, keyID...)
+	header = binary.BigEndian.AppendUint16(header, uint16(len(wrapped)))
+	header = append(header, wrapped...)
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, len(header)+len(nonce)+len(plaintext)+aead.Overhead())
+	out = append(append(out, header...), nonce...)
+	return aead.Seal(out, nonce, plaintext, keyringAD(header, ad)), nil
+}
+
+func (e *EnvelopeCipher) DecryptWithAD(ciphertext, ad []byte) ([]byte, error) {
+	keyID, wrapped, header, body, err := parseEnvelopeHeader(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := e.context()
+	defer cancel()
+	dataKey, err := e.Wrapper.UnwrapKey(ctx, keyID, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key: %w", err)
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, data := body[:aead.NonceSize()], body[aead.NonceSize():]
+	return aead.Open(nil, nonce, data, keyringAD(header, ad))
+}
+
+// EnvelopeKeyID reports which master key wrapped a ciphertext's data key.
+func EnvelopeKeyID(ciphertext []byte) (string, bool) {
+	keyID, _, _, _, err := parseEnvelopeHeader(ciphertext)
+	return keyID, err == nil
+}
+
+func parseEnvelopeHeader(b []byte) (keyID string, wrapped, header, body []byte, err error) {
+	bad := errors.New("not an envelope ciphertext")
+	n := len(envelopeMagic)
+	if len(b) < n+2 || string(b[:n]) != string(envelopeMagic) {
+		return "", nil, nil, nil, bad
+	}
+	if b[n] != envelopeVersion {
+		return "", nil, nil, nil, fmt.Errorf("unsupported envelope ciphertext version %d", b[n])
+	}
+	idEnd := n + 2 + int(b[n+1])
+	if len(b) < idEnd+2 {
+		return "", nil, nil, nil, bad
+	}
+	wrappedEnd := idEnd + 2 + int(binary.BigEndian.Uint16(b[idEnd:]))
+	if len(b) < wrappedEnd {
+		return "", nil, nil, nil, bad
+	}
+	return string(b[n+2 : idEnd]), b[idEnd+2 : wrappedEnd], b[:wrappedEnd], b[wrappedEnd:], nil
+}
+
+// localKeyFile is the LocalKeyWrapper file format.
+type localKeyFile struct {
+	Active string            `json:"active"`
+	Keys   map[string]string `json:"keys"` // base64 AES-256 keys
+}
+
+var _ KeyWrapper = (*LocalKeyWrapper)(nil)
+
+// LocalKeyWrapper wraps data keys with AES-256 master keys read from a JSON
+// file, for development and tests. Create or rotate the file with
+// AddLocalKey.
+type LocalKeyWrapper struct {
+	keys *Keyring
+}
+
+// LoadLocalKeyWrapper reads the key file at path.
+func LoadLocalKeyWrapper(path string) (*LocalKeyWrapper, error) {
+	f, err := readLocalKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Keys) == 0 {
+		return nil, fmt.Errorf("%s: no keys", path)
+	}
+	keys := make(map[string][]byte, len(f.Keys))
+	for id, enc := range f.Keys {
+		if keys[id], err = base64.StdEncoding.DecodeString(enc); err != nil {
+			return nil, fmt.Errorf("%s: key %s: %w", path, id, err)
+		}
+	}
+	ring, err := NewKeyring(f.Active, keys)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &LocalKeyWrapper{keys: ring}, nil
+}
+
+// AddLocalKey adds a random master key called keyID to the key file at
+// path and makes it active, creating the file, readable only by its owner,
+// if needed. Keys already in the file are kept so existing data still
+// unwraps.
+func AddLocalKey(path, keyID string) error {
+	f, err := readLocalKeyFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		f, err = &localKeyFile{}, nil
+	}
+	if err != nil {
+		return err
+	}
+	if f.Keys == nil {
+		f.Keys = map[string]string{}
+	}
+	if _, ok := f.Keys[keyID]; ok {
+		return fmt.Errorf("%s: key %s exists", path, keyID)
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	f.Keys[keyID] = base64.StdEncoding.EncodeToString(key)
+	f.Active = keyID
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func readLocalKeyFile(path string) (*localKeyFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f localKeyFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &f, nil
+}
+
+func (w *LocalKeyWrapper) WrapKey(_ context.Context, dataKey []byte) (string, []byte, error) {
+	id := w.keys.ActiveKeyID()
+	wrapped, err := w.keys.EncryptWithAD(dataKey, []byte(id))
+	return id, wrapped, err
+}
+
+func (w *LocalKeyWrapper) UnwrapKey(_ context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	if id, ok := w.keys.KeyID(wrapped); !ok || id != keyID {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	return w.keys.DecryptWithAD(wrapped, []byte(keyID))
+}
@@ -0,0 +1,78 @@
+package auth_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Seann-Moser/mangadex/auth"
+)
+
+func TestEnvelopeCipher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := auth.AddLocalKey(path, "dev-1"); err != nil {
+		t.Fatal(err)
+	}
+	w, err := auth.LoadLocalKeyWrapper(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := auth.NewEnvelopeCipher(w)
+
+	a, err := c.EncryptWithAD([]byte("tokens"), []byte("u1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := c.EncryptWithAD([]byte("tokens"), []byte("u1"))
+	if string(a) == string(b) {
+		t.Error("identical ciphertexts, data keys reused")
+	}
+	if plain, err := c.DecryptWithAD(a, []byte("u1")); err != nil || string(plain) != "tokens" {
+		t.Errorf("decrypt: %q, %v", plain, err)
+	}
+	if _, err := c.DecryptWithAD(a, []byte("u2")); err == nil {
+		t.Error("ciphertext decrypted for another user key")
+	}
+
+	// rotating the master key keeps old records readable
+	if err := auth.AddLocalKey(path, "dev-2"); err != nil {
+		t.Fatal(err)
+	}
+	if w, err = auth.LoadLocalKeyWrapper(path); err != nil {
+		t.Fatal(err)
+	}
+	c = auth.NewEnvelopeCipher(w)
+	fresh, _ := c.Encrypt([]byte("tokens"))
+	if id, _ := auth.EnvelopeKeyID(fresh); id != "dev-2" {
+		t.Errorf("new data key wrapped with %q, want dev-2", id)
+	}
+	if id, _ := auth.EnvelopeKeyID(a); id != "dev-1" {
+		t.Errorf("old data key wrapped with %q, want dev-1", id)
+	}
+	if plain, err := c.DecryptWithAD(a, []byte("u1")); err != nil || string(plain) != "tokens" {
+		t.Errorf("decrypt after rotation: %q, %v", plain, err)
+	}
+}
+
+func TestEnvelopeCipherWithStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := auth.AddLocalKey(path, "dev-1"); err != nil {
+		t.Fatal(err)
+	}
+	w, err := auth.LoadLocalKeyWrapper(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newSQLiteStore(t)
+	s.Cipher = auth.NewEnvelopeCipher(w)
+
+	if err := s.Save(ctx, "u1", auth.OAuthTokens{AccessToken: "secret-access"}, nil, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	tok, _, _, err := s.Load(ctx, "u1")
+	if err != nil || tok.AccessToken != "secret-access" {
+		t.Errorf("got %+v, %v", tok, err)
+	}
+}
@@ -2,7 +2,7 @@ package auth
 
 import (
 	"context"
-	"errors"
+	"strings"
 	"sync"
 	"time"
 )
@@ -11,6 +11,7 @@ type memoryEntry struct {
 	tokens    OAuthTokens
 	creds     *OAuthCredentials
 	expiresAt time.Time
+	version   int64
 }
 
 var _ TokenStore = (*InMemoryTokenStore)(nil)
@@ -37,6 +38,31 @@ func (m *InMemoryTokenStore) Save(
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	m.put(userKey, tokens, creds, expiresAt)
+	return nil
+}
+
+func (m *InMemoryTokenStore) SaveIfUnchanged(
+	_ context.Context,
+	userKey string,
+	version int64,
+	tokens OAuthTokens,
+	creds *OAuthCredentials,
+	expiresAt time.Time,
+) error {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.store[userKey].version != version {
+		return ErrVersionConflict
+	}
+	m.put(userKey, tokens, creds, expiresAt)
+	return nil
+}
+
+// put writes the entry and bumps its version; the caller holds m.mu.
+func (m *InMemoryTokenStore) put(userKey string, tokens OAuthTokens, creds *OAuthCredentials, expiresAt time.Time) {
 	// defensive copy of creds
 	var storedCreds *OAuthCredentials
 	if creds != nil {
@@ -48,36 +74,116 @@ func (m *InMemoryTokenStore) Save(
 		tokens:    tokens,
 		creds:     storedCreds,
 		expiresAt: expiresAt,
+		version:   m.store[userKey].version + 1,
 	}
-
-	return nil
 }
 
 func (m *InMemoryTokenStore) Load(
-	_ context.Context,
+	ctx context.Context,
 	userKey string,
 ) (*OAuthTokens, *OAuthCredentials, time.Time, error) {
 
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	rec, err := m.LoadRecord(ctx, userKey)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	return &rec.Tokens, rec.Creds, rec.ExpiresAt, nil
+}
 
+func (m *InMemoryTokenStore) LoadRecord(_ context.Context, userKey string) (*TokenRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// expiresAt is the access token's expiry; the entry is still needed to
+	// refresh it, so expired entries are returned rather than dropped.
 	entry, ok := m.store[userKey]
 	if !ok {
-		return nil, nil, time.Time{}, errors.New("token not found")
+		return nil, ErrTokenNotFound
 	}
+	rec := entry.record(userKey)
+	return &rec, nil
+}
 
-	if time.Now().After(entry.expiresAt) {
-		delete(m.store, userKey)
-		return nil, nil, time.Time{}, errors.New("token expired")
-	}
+func (m *InMemoryTokenStore) Delete(_ context.Context, userKey string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.store, userKey)
+	return nil
+}
 
-	tokens := entry.tokens
+func (m *InMemoryTokenStore) List(ctx context.Context, prefix string, fn func(TokenRecord) error) error {
+	// collect first so fn may call back into the store
+	m.mu.RLock()
+	var recs []TokenRecord
+	for key, entry := range m.store {
+		if strings.HasPrefix(key, prefix) {
+			recs = append(recs, entry.record(key))
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, rec := range recs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+func (e memoryEntry) record(userKey string) TokenRecord {
 	var creds *OAuthCredentials
-	if entry.creds != nil {
-		c := *entry.creds
+	if e.creds != nil {
+		c := *e.creds
 		creds = &c
 	}
+	return TokenRecord{
+		UserKey:   userKey,
+		Tokens:    e.tokens,
+		Creds:     creds,
+		ExpiresAt: e.expiresAt,
+		Version:   e.version,
+	}
+}
+
+var _ AuthStateStore = (*InMemoryAuthStateStore)(nil)
 
-	return &tokens, creds, entry.expiresAt, nil
+type InMemoryAuthStateStore struct {
+	mu      sync.Mutex
+	pending map[string]PendingAuth
+}
+
+func NewInMemoryAuthStateStore() *InMemoryAuthStateStore {
+	return &InMemoryAuthStateStore{
+		pending: make(map[string]PendingAuth),
+	}
+}
+
+func (m *InMemoryAuthStateStore) Put(_ context.Context, state string, pending PendingAuth) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// drop abandoned requests as new ones come in
+	now := time.Now()
+	for s, p := range m.pending {
+		if !now.Before(p.ExpiresAt) {
+			delete(m.pending, s)
+		}
+	}
+	m.pending[state] = pending
+	return nil
+}
+
+func (m *InMemoryAuthStateStore) Take(_ context.Context, state string) (*PendingAuth, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.pending[state]
+	delete(m.pending, state)
+	if !ok || !time.Now().Before(p.ExpiresAt) {
+		return nil, ErrAuthStateNotFound
+	}
+	return &p, nil
 }
@@ -0,0 +1,11 @@
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/Seann-Moser/mangadex/auth"
+)
+
+func TestInMemoryTokenStore(t *testing.T) {
+	testTokenStore(t, auth.NewInMemoryTokenStore())
+}
@@ -2,9 +2,28 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+var (
+	ErrTokenNotFound = errors.New("token not found")
+
+	// ErrVersionConflict is returned by SaveIfUnchanged when the entry was
+	// written since it was loaded.
+	ErrVersionConflict = errors.New("token version conflict")
+)
+
+// TokenRecord is a stored entry. Version starts at 1 and grows by one with
+// every write; a missing entry has version 0.
+type TokenRecord struct {
+	UserKey   string
+	Tokens    OAuthTokens
+	Creds     *OAuthCredentials
+	ExpiresAt time.Time
+	Version   int64
+}
+
 type TokenStore interface {
 	Load(ctx context.Context, userKey string) (
 		tokens *OAuthTokens,
@@ -20,4 +39,46 @@ type TokenStore interface {
 		creds *OAuthCredentials,
 		expiresAt time.Time,
 	) error
+
+	// LoadRecord is Load with the entry's version.
+	LoadRecord(ctx context.Context, userKey string) (*TokenRecord, error)
+
+	// SaveIfUnchanged saves only if the entry is still at version, so the
+	// new version is version+1. Version 0 creates the entry. It returns
+	// ErrVersionConflict otherwise.
+	SaveIfUnchanged(
+		ctx context.Context,
+		userKey string,
+		version int64,
+		tokens OAuthTokens,
+		creds *OAuthCredentials,
+		expiresAt time.Time,
+	) error
+
+	// Delete removes the entry; deleting a missing entry is not an error.
+	Delete(ctx context.Context, userKey string) error
+
+	// List calls fn for every entry whose user key starts with prefix, in
+	// no particular order, and stops at the first error fn returns.
+	List(ctx context.Context, prefix string, fn func(TokenRecord) error) error
+}
+
+// ErrAuthStateNotFound is returned for an authorization state that was
+// never issued, has expired or was already used.
+var ErrAuthStateNotFound = errors.New("authorization state not found")
+
+// PendingAuth is an authorization request waiting for its callback.
+type PendingAuth struct {
+	UserKey     string
+	Verifier    string
+	RedirectURI string
+	ExpiresAt   time.Time
+}
+
+// AuthStateStore keeps pending authorization requests by state. Take must
+// remove the entry, so that each state is used at most once, and return
+// ErrAuthStateNotFound once ExpiresAt has passed.
+type AuthStateStore interface {
+	Put(ctx context.Context, state string, pending PendingAuth) error
+	Take(ctx context.Context, state string) (*PendingAuth, error)
 }
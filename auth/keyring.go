@@ -0,0 +1,285 @@
+package auth
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// AADCipher is a TokenCipher that binds ciphertext to associated data.
+// Stores pass the user key, so an entry copied to another user fails to
+// decrypt.
+type AADCipher interface {
+	TokenCipher
+	EncryptWithAD(plaintext, ad []byte) ([]byte, error)
+	DecryptWithAD(ciphertext, ad []byte) ([]byte, error)
+}
+
+// keyringMagic starts every Keyring ciphertext; the byte after it is the
+// format version.
+var keyringMagic = []byte{'m', 'd', 'k'}
+
+const keyringVersion = 1
+
+var (
+	ErrUnknownKey = errors.New("unknown encryption key")
+
+	errKeyringFormat = errors.New("not a keyring ciphertext")
+)
+
+var _ AADCipher = (*Keyring)(nil)
+
+// Keyring is an AES-256-GCM cipher over several keys, for key rotation.
+// Ciphertexts are
+//
+//	"mdk" | version (1) | key id length (1) | key id | nonce | sealed data
+//
+// and the header is authenticated along with the associated data. New
+// data is encrypted with the active key; data from any key in the ring
+// decrypts. A legacy key also decrypts AESGCMCipher output, so stores can
+// switch to a Keyring and be re-encrypted with Reencrypt.
+type Keyring struct {
+	mu     sync.RWMutex
+	keys   map[string]cipher.AEAD
+	active string
+	legacy cipher.AEAD
+}
+
+// NewKeyring creates a keyring holding keys, with activeID encrypting.
+func NewKeyring(activeID string, keys map[string][]byte) (*Keyring, error) {
+	k := &Keyring{keys: map[string]cipher.AEAD{}}
+	for id, key := range keys {
+		if err := k.AddKey(id, key); err != nil {
+			return nil, err
+		}
+	}
+	if err := k.SetActive(activeID); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, errors.New("AES-256 requires 32-byte key")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// AddKey adds a key that can decrypt, and encrypt once made active.
+func (k *Keyring) AddKey(id string, key []byte) error {
+	if id == "" || len(id) > 255 {
+		return fmt.Errorf("key id must be 1 to 255 bytes")
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return fmt.Errorf("key %s: %w", id, err)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.keys == nil {
+		k.keys = map[string]cipher.AEAD{}
+	}
+	k.keys[id] = aead
+	return nil
+}
+
+// RemoveKey drops a retired key. Data still encrypted with it no longer
+// decrypts, so run Reencrypt first.
+func (k *Keyring) RemoveKey(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if id == k.active {
+		return fmt.Errorf("key %s is active", id)
+	}
+	delete(k.keys, id)
+	return nil
+}
+
+// SetActive selects the key new data is encrypted with.
+func (k *Keyring) SetActive(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.keys[id]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownKey, id)
+	}
+	k.active = id
+	return nil
+}
+
+// SetLegacyKey sets the AESGCMCipher key data written before the keyring
+// was introduced can be decrypted with.
+func (k *Keyring) SetLegacyKey(key []byte) error {
+	aead, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.legacy = aead
+	return nil
+}
+
+func (k *Keyring) ActiveKeyID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.active
+}
+
+// KeyIDs returns the ids in the ring, sorted.
+func (k *Keyring) KeyIDs() []string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// KeyID reports which key a ciphertext was encrypted with.
+func (k *Keyring) KeyID(ciphertext []byte) (string, bool) {
+	id, _, _, err := parseKeyringHeader(ciphertext)
+	return id, err == nil
+}
+
+func (k *Keyring) Encrypt(plaintext []byte) ([]byte, error) {
+	return k.EncryptWithAD(plaintext, nil)
+}
+
+func (k *Keyring) Decrypt(ciphertext []byte) ([]byte, error) {
+	return k.DecryptWithAD(ciphertext, nil)
+}
+
+func (k *Keyring) EncryptWithAD(plaintext, ad []byte) ([]byte, error) {
+	k.mu.RLock()
+	id, aead := k.active, k.keys[k.active]
+	k.mu.RUnlock()
+	if aead == nil {
+		return nil, fmt.Errorf("%w: no active key", ErrUnknownKey)
+	}
+
+	header := make([]byte, 0, len(keyringMagic)+2+len(id))
+	header = append(header, keyringMagic...)
+	header = append(header, keyringVersion, byte(len(id)))
+	header = append(header, id...)
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := append(header, nonce...)
+	return aead.Seal(out, nonce, plaintext, keyringAD(header, ad)), nil
+}
+
+func (k *Keyring) DecryptWithAD(ciphertext, ad []byte) ([]byte, error) {
+	id, header, body, err := parseKeyringHeader(ciphertext)
+	if err != nil {
+		return k.decryptLegacy(ciphertext, err)
+	}
+
+	k.mu.RLock()
+	aead := k.keys[id]
+	k.mu.RUnlock()
+	if aead == nil {
+		// a legacy nonce can start like a header by chance
+		if plain, lerr := k.decryptLegacy(ciphertext, nil); lerr == nil {
+			return plain, nil
+		}
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, id)
+	}
+
+	if len(body) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, data := body[:aead.NonceSize()], body[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, data, keyringAD(header, ad))
+	if err != nil {
+		if plain, lerr := k.decryptLegacy(ciphertext, nil); lerr == nil {
+			return plain, nil
+		}
+		return nil, err
+	}
+	return plain, nil
+}
+
+// decryptLegacy opens AESGCMCipher output, which has no header and no
+// associated data. cause is returned when there is no legacy key.
+func (k *Keyring) decryptLegacy(ciphertext []byte, cause error) ([]byte, error) {
+	k.mu.RLock()
+	aead := k.legacy
+	k.mu.RUnlock()
+	if aead == nil {
+		if cause == nil {
+			cause = errKeyringFormat
+		}
+		return nil, cause
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, data := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, data, nil)
+}
+
+func parseKeyringHeader(b []byte) (id string, header, body []byte, err error) {
+	n := len(keyringMagic)
+	if len(b) < n+2 || string(b[:n]) != string(keyringMagic) {
+		return "", nil, nil, errKeyringFormat
+	}
+	if b[n] != keyringVersion {
+		return "", nil, nil, fmt.Errorf("unsupported keyring ciphertext version %d", b[n])
+	}
+	idLen := int(b[n+1])
+	if idLen == 0 || len(b) < n+2+idLen {
+		return "", nil, nil, errKeyringFormat
+	}
+	end := n + 2 + idLen
+	return string(b[n+2 : end]), b[:end], b[end:], nil
+}
+
+func keyringAD(header, ad []byte) []byte {
+	out := make([]byte, 0, len(header)+len(ad))
+	return append(append(out, header...), ad...)
+}
+
+// Reencrypt rewrites every entry whose user key starts with prefix, so that
+// it is encrypted with the store cipher's active key. Entries written while
+// it runs are skipped, as that write used the active key already. It
+// returns how many entries were rewritten.
+func Reencrypt(ctx context.Context, store TokenStore, prefix string) (int, error) {
+	var recs []TokenRecord
+	if err := store.List(ctx, prefix, func(rec TokenRecord) error {
+		recs = append(recs, rec)
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, rec := range recs {
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+		err := store.SaveIfUnchanged(ctx, rec.UserKey, rec.Version, rec.Tokens, rec.Creds, rec.ExpiresAt)
+		switch {
+		case errors.Is(err, ErrVersionConflict):
+		case err != nil:
+			return n, fmt.Errorf("token %s: %w", rec.UserKey, err)
+		default:
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,121 @@
+package auth_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Seann-Moser/mangadex/auth"
+)
+
+func testKey(b byte) []byte { return bytes.Repeat([]byte{b}, 32) }
+
+func TestKeyringRotation(t *testing.T) {
+	k, err := auth.NewKeyring("2025-01", map[string][]byte{"2025-01": testKey(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, err := k.EncryptWithAD([]byte("tokens"), []byte("u1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := k.AddKey("2025-06", testKey(2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.SetActive("2025-06"); err != nil {
+		t.Fatal(err)
+	}
+	fresh, _ := k.EncryptWithAD([]byte("tokens"), []byte("u1"))
+	if id, _ := k.KeyID(old); id != "2025-01" {
+		t.Errorf("old ciphertext key %q", id)
+	}
+	if id, _ := k.KeyID(fresh); id != "2025-06" {
+		t.Errorf("new ciphertext key %q", id)
+	}
+
+	for _, ct := range [][]byte{old, fresh} {
+		plain, err := k.DecryptWithAD(ct, []byte("u1"))
+		if err != nil || string(plain) != "tokens" {
+			t.Errorf("decrypt: %q, %v", plain, err)
+		}
+	}
+	if _, err := k.DecryptWithAD(fresh, []byte("u2")); err == nil {
+		t.Error("ciphertext decrypted for another user key")
+	}
+
+	tampered := append([]byte(nil), fresh...)
+	copy(tampered[5:], "2025-01") // claim the old key
+	if _, err := k.DecryptWithAD(tampered, []byte("u1")); err == nil {
+		t.Error("tampered header decrypted")
+	}
+
+	if err := k.RemoveKey("2025-06"); err == nil {
+		t.Error("removed the active key")
+	}
+	_ = k.RemoveKey("2025-01")
+	if _, err := k.DecryptWithAD(old, []byte("u1")); !errors.Is(err, auth.ErrUnknownKey) {
+		t.Errorf("got %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestKeyringLegacy(t *testing.T) {
+	legacy, _ := auth.NewAESGCMCipher(testKey(9))
+	ct, _ := legacy.Encrypt([]byte("tokens"))
+
+	k, _ := auth.NewKeyring("k1", map[string][]byte{"k1": testKey(1)})
+	if _, err := k.DecryptWithAD(ct, []byte("u1")); err == nil {
+		t.Fatal("legacy ciphertext decrypted without the legacy key")
+	}
+	if err := k.SetLegacyKey(testKey(9)); err != nil {
+		t.Fatal(err)
+	}
+	plain, err := k.DecryptWithAD(ct, []byte("u1"))
+	if err != nil || string(plain) != "tokens" {
+		t.Errorf("legacy decrypt: %q, %v", plain, err)
+	}
+}
+
+func TestReencrypt(t *testing.T) {
+	ctx := context.Background()
+	s := newSQLiteStore(t)
+	legacy, _ := auth.NewAESGCMCipher(testKey(9))
+	s.Cipher = legacy
+	for _, key := range []string{"u1", "u2"} {
+		if err := s.Save(ctx, key, auth.OAuthTokens{AccessToken: "a-" + key}, nil, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	k, _ := auth.NewKeyring("k1", map[string][]byte{"k1": testKey(1)})
+	_ = k.SetLegacyKey(testKey(9))
+	s.Cipher = k
+	n, err := auth.Reencrypt(ctx, s, "")
+	if err != nil || n != 2 {
+		t.Fatalf("reencrypted %d, %v; want 2", n, err)
+	}
+
+	rows, err := s.DB.Query(`SELECT data FROM oauth_tokens`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data sql.RawBytes
+		_ = rows.Scan(&data)
+		if id, ok := k.KeyID(data); !ok || id != "k1" {
+			t.Errorf("row encrypted with %q after reencrypt", id)
+		}
+	}
+
+	// the legacy key is no longer needed
+	k2, _ := auth.NewKeyring("k1", map[string][]byte{"k1": testKey(1)})
+	s.Cipher = k2
+	tok, _, _, err := s.Load(ctx, "u2")
+	if err != nil || tok.AccessToken != "a-u2" {
+		t.Errorf("load after reencrypt: %+v, %v", tok, err)
+	}
+}
@@ -1,9 +1,15 @@
 package auth
 
+import "encoding/json"
+
 type OAuthTokens struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 	ExpiresIn    int    `json:"expires_in,omitempty"`
+
+	// RefreshExpiresIn is the refresh token's lifetime in seconds; zero when
+	// it does not expire or the provider does not say.
+	RefreshExpiresIn int `json:"refresh_expires_in,omitempty"`
 }
 
 type OAuthCredentials struct {
@@ -11,3 +17,46 @@ type OAuthCredentials struct {
 	ClientID     string `json:"client_id"`
 	ClientSecret string `json:"client_secret"`
 }
+
+// tokenPayload is the stored form of an entry's secrets, shared by the
+// stores that keep them as one blob.
+type tokenPayload struct {
+	Tokens OAuthTokens       `json:"tokens"`
+	Creds  *OAuthCredentials `json:"credentials,omitempty"`
+}
+
+// encodeTokenPayload marshals the payload, encrypting it when c is set.
+// Ciphers that support associated data bind it to userKey.
+func encodeTokenPayload(c TokenCipher, userKey string, tokens OAuthTokens, creds *OAuthCredentials) ([]byte, error) {
+	raw, err := json.Marshal(tokenPayload{Tokens: tokens, Creds: creds})
+	if err != nil {
+		return nil, err
+	}
+	switch c := c.(type) {
+	case nil:
+		return raw, nil
+	case AADCipher:
+		return c.EncryptWithAD(raw, []byte(userKey))
+	default:
+		return c.Encrypt(raw)
+	}
+}
+
+func decodeTokenPayload(c TokenCipher, userKey string, data []byte) (*tokenPayload, error) {
+	var err error
+	switch c := c.(type) {
+	case nil:
+	case AADCipher:
+		data, err = c.DecryptWithAD(data, []byte(userKey))
+	default:
+		data, err = c.Decrypt(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var p tokenPayload
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
@@ -2,7 +2,10 @@ package auth
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -12,8 +15,54 @@ import (
 
 var _ TokenStore = (*MongoTokenStore)(nil)
 
+// MongoTokenStore keeps tokens in plain documents keyed by user_key.
+// SaveIfUnchanged creates the unique user_key index it needs on first use;
+// call EnsureIndexes at startup to surface index errors early.
 type MongoTokenStore struct {
 	Collection *mongo.Collection
+
+	indexes tokenIndexes
+}
+
+type mongoTokenDoc struct {
+	UserKey          string    `bson:"user_key"`
+	AccessToken      string    `bson:"access_token"`
+	RefreshToken     string    `bson:"refresh_token"`
+	ExpiresIn        int       `bson:"expires_in,omitempty"`
+	RefreshExpiresIn int       `bson:"refresh_expires_in,omitempty"`
+	ExpiresAt        time.Time `bson:"expires_at"`
+	Version          int64     `bson:"version,omitempty"`
+
+	ClientID     string `bson:"client_id,omitempty"`
+	ClientSecret string `bson:"client_secret,omitempty"`
+}
+
+func (d *mongoTokenDoc) record() TokenRecord {
+	var creds *OAuthCredentials
+	if d.ClientID != "" && d.ClientSecret != "" {
+		creds = &OAuthCredentials{
+			ClientID:     d.ClientID,
+			ClientSecret: d.ClientSecret,
+		}
+	}
+	return TokenRecord{
+		UserKey: d.UserKey,
+		Tokens: OAuthTokens{
+			AccessToken:      d.AccessToken,
+			RefreshToken:     d.RefreshToken,
+			ExpiresIn:        d.ExpiresIn,
+			RefreshExpiresIn: d.RefreshExpiresIn,
+		},
+		Creds:     creds,
+		ExpiresAt: d.ExpiresAt,
+		Version:   d.Version,
+	}
+}
+
+// EnsureIndexes creates the unique user_key index SaveIfUnchanged relies on
+// to detect concurrent creates.
+func (m *MongoTokenStore) EnsureIndexes(ctx context.Context) error {
+	return m.indexes.ensure(ctx, m.Collection)
 }
 
 func (m *MongoTokenStore) Load(
@@ -21,70 +70,115 @@ func (m *MongoTokenStore) Load(
 	userKey string,
 ) (*OAuthTokens, *OAuthCredentials, time.Time, error) {
 
-	var doc struct {
-		AccessToken  string    `bson:"access_token"`
-		RefreshToken string    `bson:"refresh_token"`
-		ExpiresAt    time.Time `bson:"expires_at"`
-
-		ClientID     string `bson:"client_id,omitempty"`
-		ClientSecret string `bson:"client_secret,omitempty"`
+	rec, err := m.LoadRecord(ctx, userKey)
+	if err != nil {
+		return nil, nil, time.Time{}, err
 	}
+	return &rec.Tokens, rec.Creds, rec.ExpiresAt, nil
+}
 
+func (m *MongoTokenStore) LoadRecord(ctx context.Context, userKey string) (*TokenRecord, error) {
+	var doc mongoTokenDoc
 	err := m.Collection.FindOne(ctx, bson.M{"user_key": userKey}).Decode(&doc)
 	if err != nil {
-		return nil, nil, time.Time{}, err
+		return nil, notFound(err)
 	}
+	rec := doc.record()
+	return &rec, nil
+}
 
-	tokens := &OAuthTokens{
-		AccessToken:  doc.AccessToken,
-		RefreshToken: doc.RefreshToken,
-	}
+func (m *MongoTokenStore) Save(
+	ctx context.Context,
+	userKey string,
+	tokens OAuthTokens,
+	creds *OAuthCredentials,
+	expiresAt time.Time,
+) error {
 
-	var creds *OAuthCredentials
-	if doc.ClientID != "" && doc.ClientSecret != "" {
-		creds = &OAuthCredentials{
-			ClientID:     doc.ClientID,
-			ClientSecret: doc.ClientSecret,
-		}
-	}
+	_, err := m.Collection.UpdateOne(
+		ctx,
+		bson.M{"user_key": userKey},
+		m.update(userKey, tokens, creds, expiresAt),
+		options.Update().SetUpsert(true),
+	)
 
-	return tokens, creds, doc.ExpiresAt, nil
+	return err
 }
 
-func (m *MongoTokenStore) Save(
+func (m *MongoTokenStore) SaveIfUnchanged(
 	ctx context.Context,
 	userKey string,
+	version int64,
 	tokens OAuthTokens,
 	creds *OAuthCredentials,
 	expiresAt time.Time,
 ) error {
+	return saveIfUnchanged(ctx, m.Collection, &m.indexes, userKey, version, m.update(userKey, tokens, creds, expiresAt))
+}
+
+func (m *MongoTokenStore) update(
+	userKey string,
+	tokens OAuthTokens,
+	creds *OAuthCredentials,
+	expiresAt time.Time,
+) bson.M {
 
-	update := bson.M{
-		"user_key":      userKey,
-		"access_token":  tokens.AccessToken,
-		"refresh_token": tokens.RefreshToken,
-		"expires_at":    expiresAt,
+	set := bson.M{
+		"user_key":           userKey,
+		"access_token":       tokens.AccessToken,
+		"refresh_token":      tokens.RefreshToken,
+		"expires_in":         tokens.ExpiresIn,
+		"refresh_expires_in": tokens.RefreshExpiresIn,
+		"expires_at":         expiresAt,
 	}
 
 	// only store creds if provided
 	if creds != nil {
-		update["client_id"] = creds.ClientID
-		update["client_secret"] = creds.ClientSecret
+		set["client_id"] = creds.ClientID
+		set["client_secret"] = creds.ClientSecret
 	}
 
-	_, err := m.Collection.UpdateOne(
-		ctx,
-		bson.M{"user_key": userKey},
-		bson.M{"$set": update},
-		options.Update().SetUpsert(true),
-	)
+	return bson.M{"$set": set, "$inc": bson.M{"version": 1}}
+}
 
+func (m *MongoTokenStore) Delete(ctx context.Context, userKey string) error {
+	_, err := m.Collection.DeleteOne(ctx, bson.M{"user_key": userKey})
 	return err
 }
 
+func (m *MongoTokenStore) List(ctx context.Context, prefix string, fn func(TokenRecord) error) error {
+	return listTokenDocs(ctx, m.Collection, prefix, func(cur *mongo.Cursor) error {
+		var doc mongoTokenDoc
+		if err := cur.Decode(&doc); err != nil {
+			return err
+		}
+		return fn(doc.record())
+	})
+}
+
+var _ TokenStore = (*EncryptedMongoTokenStore)(nil)
+
+// EncryptedMongoTokenStore keeps each user's tokens and credentials as one
+// encrypted payload. Like MongoTokenStore it creates the unique user_key
+// index on the first SaveIfUnchanged.
 type EncryptedMongoTokenStore struct {
 	Collection *mongo.Collection
 	Cipher     TokenCipher
+
+	indexes tokenIndexes
+}
+
+type encryptedTokenDoc struct {
+	UserKey   string    `bson:"user_key"`
+	Data      []byte    `bson:"data"`
+	ExpiresAt time.Time `bson:"expires_at"`
+	Version   int64     `bson:"version,omitempty"`
+}
+
+// EnsureIndexes creates the unique user_key index SaveIfUnchanged relies on
+// to detect concurrent creates.
+func (m *EncryptedMongoTokenStore) EnsureIndexes(ctx context.Context) error {
+	return m.indexes.ensure(ctx, m.Collection)
 }
 
 func (m *EncryptedMongoTokenStore) Save(
@@ -95,20 +189,7 @@ func (m *EncryptedMongoTokenStore) Save(
 	expiresAt time.Time,
 ) error {
 
-	payload := struct {
-		Tokens OAuthTokens       `json:"tokens"`
-		Creds  *OAuthCredentials `json:"credentials,omitempty"`
-	}{
-		Tokens: tokens,
-		Creds:  creds,
-	}
-
-	raw, err := json.Marshal(payload)
-	if err != nil {
-		return err
-	}
-
-	enc, err := m.Cipher.Encrypt(raw)
+	update, err := m.update(userKey, tokens, creds, expiresAt)
 	if err != nil {
 		return err
 	}
@@ -116,47 +197,184 @@ func (m *EncryptedMongoTokenStore) Save(
 	_, err = m.Collection.UpdateOne(
 		ctx,
 		bson.M{"user_key": userKey},
-		bson.M{
-			"$set": bson.M{
-				"user_key":   userKey,
-				"data":       enc,
-				"expires_at": expiresAt,
-			},
-		},
+		update,
 		options.Update().SetUpsert(true),
 	)
 
 	return err
 }
 
+func (m *EncryptedMongoTokenStore) SaveIfUnchanged(
+	ctx context.Context,
+	userKey string,
+	version int64,
+	tokens OAuthTokens,
+	creds *OAuthCredentials,
+	expiresAt time.Time,
+) error {
+
+	update, err := m.update(userKey, tokens, creds, expiresAt)
+	if err != nil {
+		return err
+	}
+	return saveIfUnchanged(ctx, m.Collection, &m.indexes, userKey, version, update)
+}
+
+func (m *EncryptedMongoTokenStore) update(
+	userKey string,
+	tokens OAuthTokens,
+	creds *OAuthCredentials,
+	expiresAt time.Time,
+) (bson.M, error) {
+
+	enc, err := encodeTokenPayload(m.Cipher, userKey, tokens, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	return bson.M{
+		"$set": bson.M{
+			"user_key":   userKey,
+			"data":       enc,
+			"expires_at": expiresAt,
+		},
+		"$inc": bson.M{"version": 1},
+	}, nil
+}
+
 func (m *EncryptedMongoTokenStore) Load(
 	ctx context.Context,
 	userKey string,
 ) (*OAuthTokens, *OAuthCredentials, time.Time, error) {
 
-	var doc struct {
-		Data      []byte    `bson:"data"`
-		ExpiresAt time.Time `bson:"expires_at"`
+	rec, err := m.LoadRecord(ctx, userKey)
+	if err != nil {
+		return nil, nil, time.Time{}, err
 	}
+	return &rec.Tokens, rec.Creds, rec.ExpiresAt, nil
+}
 
+func (m *EncryptedMongoTokenStore) LoadRecord(ctx context.Context, userKey string) (*TokenRecord, error) {
+	var doc encryptedTokenDoc
 	err := m.Collection.FindOne(ctx, bson.M{"user_key": userKey}).Decode(&doc)
 	if err != nil {
-		return nil, nil, time.Time{}, err
+		return nil, notFound(err)
 	}
+	return m.record(&doc)
+}
 
-	raw, err := m.Cipher.Decrypt(doc.Data)
+func (m *EncryptedMongoTokenStore) record(doc *encryptedTokenDoc) (*TokenRecord, error) {
+	payload, err := decodeTokenPayload(m.Cipher, doc.UserKey, doc.Data)
 	if err != nil {
-		return nil, nil, time.Time{}, err
+		return nil, err
 	}
 
-	var payload struct {
-		Tokens OAuthTokens       `json:"tokens"`
-		Creds  *OAuthCredentials `json:"credentials,omitempty"`
+	return &TokenRecord{
+		UserKey:   doc.UserKey,
+		Tokens:    payload.Tokens,
+		Creds:     payload.Creds,
+		ExpiresAt: doc.ExpiresAt,
+		Version:   doc.Version,
+	}, nil
+}
+
+func (m *EncryptedMongoTokenStore) Delete(ctx context.Context, userKey string) error {
+	_, err := m.Collection.DeleteOne(ctx, bson.M{"user_key": userKey})
+	return err
+}
+
+func (m *EncryptedMongoTokenStore) List(ctx context.Context, prefix string, fn func(TokenRecord) error) error {
+	return listTokenDocs(ctx, m.Collection, prefix, func(cur *mongo.Cursor) error {
+		var doc encryptedTokenDoc
+		if err := cur.Decode(&doc); err != nil {
+			return err
+		}
+		rec, err := m.record(&doc)
+		if err != nil {
+			return fmt.Errorf("token %s: %w", doc.UserKey, err)
+		}
+		return fn(*rec)
+	})
+}
+
+// tokenIndexes creates a store's indexes once, retrying after a failure.
+type tokenIndexes struct {
+	mu   sync.Mutex
+	done bool
+}
+
+func (t *tokenIndexes) ensure(ctx context.Context, coll *mongo.Collection) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.done {
+		return nil
+	}
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_key", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("creating user_key index: %w", err)
 	}
+	t.done = true
+	return nil
+}
 
-	if err := json.Unmarshal(raw, &payload); err != nil {
-		return nil, nil, time.Time{}, err
+// saveIfUnchanged applies update only to the document at version. Version
+// 0 also matches documents written before versioning, and otherwise
+// creates the document; a concurrent create then fails on the unique
+// user_key index, which is created first if need be.
+func saveIfUnchanged(ctx context.Context, coll *mongo.Collection, indexes *tokenIndexes, userKey string, version int64, update bson.M) error {
+	if version == 0 {
+		// without the index two creates would both upsert a document
+		if err := indexes.ensure(ctx, coll); err != nil {
+			return err
+		}
+		_, err := coll.UpdateOne(
+			ctx,
+			bson.M{"user_key": userKey, "version": bson.M{"$exists": false}},
+			update,
+			options.Update().SetUpsert(true),
+		)
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrVersionConflict
+		}
+		return err
 	}
 
-	return &payload.Tokens, payload.Creds, doc.ExpiresAt, nil
+	res, err := coll.UpdateOne(ctx, bson.M{"user_key": userKey, "version": version}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+func listTokenDocs(ctx context.Context, coll *mongo.Collection, prefix string, fn func(*mongo.Cursor) error) error {
+	filter := bson.M{}
+	if prefix != "" {
+		filter["user_key"] = bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)}
+	}
+	cur, err := coll.Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		if err := fn(cur); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
+
+// notFound maps a missing document to ErrTokenNotFound, keeping the driver
+// error in the chain.
+func notFound(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("%w: %w", ErrTokenNotFound, err)
+	}
+	return err
 }
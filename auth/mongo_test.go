@@ -0,0 +1,132 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Seann-Moser/mangadex/auth"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// newMongoCollection returns an empty collection on the server at
+// MONGO_URI, dropped when the test ends. The test is skipped without one.
+func newMongoCollection(t *testing.T) *mongo.Collection {
+	t.Helper()
+	uri := os.Getenv("MONGO_URI")
+	if uri == "" {
+		t.Skip("MONGO_URI not set")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		t.Fatalf("ping %s: %v", uri, err)
+	}
+	coll := client.Database("mangadex_auth_test").Collection(strings.ReplaceAll(t.Name(), "/", "_"))
+	if err := coll.Drop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = coll.Drop(context.Background())
+		_ = client.Disconnect(context.Background())
+	})
+	return coll
+}
+
+func TestMongoTokenStore(t *testing.T) {
+	// the List cases include regex metacharacters, which must be quoted
+	testTokenStore(t, &auth.MongoTokenStore{Collection: newMongoCollection(t)})
+}
+
+func TestEncryptedMongoTokenStore(t *testing.T) {
+	coll := newMongoCollection(t)
+	s := &auth.EncryptedMongoTokenStore{Collection: coll, Cipher: testCipher(t)}
+	testTokenStore(t, s)
+	testEncryptedTokenStore(t, s, func(userKey string) []byte {
+		var doc struct {
+			Data []byte `bson:"data"`
+		}
+		if err := coll.FindOne(context.Background(), bson.M{"user_key": userKey}).Decode(&doc); err != nil {
+			t.Fatal(err)
+		}
+		return doc.Data
+	})
+}
+
+func TestMongoTokenStoreWithClient(t *testing.T) {
+	testTokenStoreWithClient(t, &auth.MongoTokenStore{Collection: newMongoCollection(t)})
+}
+
+func TestMongoTokenStoreLegacyDocument(t *testing.T) {
+	ctx := context.Background()
+	coll := newMongoCollection(t)
+	s := &auth.MongoTokenStore{Collection: coll}
+
+	// documents written before versioning have no version field
+	if _, err := coll.InsertOne(ctx, bson.M{"user_key": "u1", "access_token": "old", "refresh_token": "r"}); err != nil {
+		t.Fatal(err)
+	}
+	rec, err := s.LoadRecord(ctx, "u1")
+	if err != nil || rec.Version != 0 || rec.Tokens.AccessToken != "old" {
+		t.Fatalf("got %+v, %v; want old at version 0", rec, err)
+	}
+	if err := s.SaveIfUnchanged(ctx, "u1", 0, auth.OAuthTokens{AccessToken: "new"}, nil, time.Now()); err != nil {
+		t.Fatalf("save over legacy document: %v", err)
+	}
+	if n, err := coll.CountDocuments(ctx, bson.M{"user_key": "u1"}); err != nil || n != 1 {
+		t.Fatalf("%d documents, %v; want the legacy one updated in place", n, err)
+	}
+	rec, err = s.LoadRecord(ctx, "u1")
+	if err != nil || rec.Version != 1 || rec.Tokens.AccessToken != "new" {
+		t.Errorf("got %+v, %v; want new at version 1", rec, err)
+	}
+	if err := s.SaveIfUnchanged(ctx, "u1", 0, auth.OAuthTokens{AccessToken: "newer"}, nil, time.Now()); !errors.Is(err, auth.ErrVersionConflict) {
+		t.Errorf("second create: got %v, want ErrVersionConflict", err)
+	}
+}
+
+func TestMongoTokenStoreConcurrentCreate(t *testing.T) {
+	ctx := context.Background()
+	coll := newMongoCollection(t)
+	s := &auth.MongoTokenStore{Collection: coll}
+
+	// the first create builds the unique index, so racing upserts end in a
+	// duplicate key error rather than a second document
+	const n = 8
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = s.SaveIfUnchanged(ctx, "u1", 0, auth.OAuthTokens{AccessToken: "a"}, nil, time.Now())
+		}()
+	}
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, auth.ErrVersionConflict):
+			t.Errorf("create: %v", err)
+		}
+	}
+	if created != 1 {
+		t.Errorf("%d creates succeeded, want 1", created)
+	}
+	if count, err := coll.CountDocuments(ctx, bson.M{"user_key": "u1"}); err != nil || count != 1 {
+		t.Errorf("%d documents, %v; want 1", count, err)
+	}
+}
@@ -0,0 +1,211 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+)
+
+// DefaultRedisKeyPrefix namespaces the keys RedisTokenStore writes.
+const DefaultRedisKeyPrefix = "mangadex:token:"
+
+var _ TokenStore = (*RedisTokenStore)(nil)
+
+// RedisTokenStore keeps each user's tokens in a hash holding the payload in
+// the EncryptedMongoTokenStore format, the access token's expiry and a
+// version. Keys expire with the refresh token, so abandoned sessions clean
+// themselves up.
+type RedisTokenStore struct {
+	Client redis.UniversalClient
+
+	// Cipher encrypts the payload; without it the payload is plain JSON.
+	Cipher TokenCipher
+
+	// Prefix defaults to DefaultRedisKeyPrefix.
+	Prefix string
+
+	// TTL applies when the refresh token's lifetime is unknown; zero keeps
+	// such keys until they are deleted.
+	TTL time.Duration
+}
+
+// saveScript writes the hash if its version is ARGV[1], or unconditionally
+// when ARGV[1] is -1, and returns the new version or -1 on a conflict.
+//
+// ARGV: expected version, payload, access expiry, expire-at in unix ms (0
+// persists the key).
+var saveScript = redis.NewScript(`
+local v = tonumber(redis.call('HGET', KEYS[1], 'version') or '0')
+if ARGV[1] ~= '-1' and v ~= tonumber(ARGV[1]) then
+	return -1
+end
+redis.call('HSET', KEYS[1], 'data', ARGV[2], 'expires_at', ARGV[3], 'version', v + 1)
+if ARGV[4] ~= '0' then
+	redis.call('PEXPIREAT', KEYS[1], ARGV[4])
+else
+	redis.call('PERSIST', KEYS[1])
+end
+return v + 1
+`)
+
+func (r *RedisTokenStore) key(userKey string) string {
+	prefix := r.Prefix
+	if prefix == "" {
+		prefix = DefaultRedisKeyPrefix
+	}
+	return prefix + userKey
+}
+
+func (r *RedisTokenStore) Load(
+	ctx context.Context,
+	userKey string,
+) (*OAuthTokens, *OAuthCredentials, time.Time, error) {
+
+	rec, err := r.LoadRecord(ctx, userKey)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	return &rec.Tokens, rec.Creds, rec.ExpiresAt, nil
+}
+
+func (r *RedisTokenStore) LoadRecord(ctx context.Context, userKey string) (*TokenRecord, error) {
+	fields, err := r.Client.HGetAll(ctx, r.key(userKey)).Result()
+	if err != nil {
+		return nil, err
+	}
+	return r.record(userKey, fields)
+}
+
+func (r *RedisTokenStore) record(userKey string, fields map[string]string) (*TokenRecord, error) {
+	if len(fields) == 0 {
+		return nil, ErrTokenNotFound
+	}
+	payload, err := decodeTokenPayload(r.Cipher, userKey, []byte(fields["data"]))
+	if err != nil {
+		return nil, err
+	}
+	expiresAt, err := time.Parse(time.RFC3339Nano, fields["expires_at"])
+	if err != nil {
+		return nil, err
+	}
+	version, err := strconv.ParseInt(fields["version"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenRecord{
+		UserKey:   userKey,
+		Tokens:    payload.Tokens,
+		Creds:     payload.Creds,
+		ExpiresAt: expiresAt,
+		Version:   version,
+	}, nil
+}
+
+func (r *RedisTokenStore) Save(
+	ctx context.Context,
+	userKey string,
+	tokens OAuthTokens,
+	creds *OAuthCredentials,
+	expiresAt time.Time,
+) error {
+	return r.save(ctx, userKey, -1, tokens, creds, expiresAt)
+}
+
+func (r *RedisTokenStore) SaveIfUnchanged(
+	ctx context.Context,
+	userKey string,
+	version int64,
+	tokens OAuthTokens,
+	creds *OAuthCredentials,
+	expiresAt time.Time,
+) error {
+	return r.save(ctx, userKey, version, tokens, creds, expiresAt)
+}
+
+func (r *RedisTokenStore) save(
+	ctx context.Context,
+	userKey string,
+	version int64,
+	tokens OAuthTokens,
+	creds *OAuthCredentials,
+	expiresAt time.Time,
+) error {
+
+	data, err := encodeTokenPayload(r.Cipher, userKey, tokens, creds)
+	if err != nil {
+		return err
+	}
+
+	// the key lives as long as the refresh token that keeps it useful
+	var expireAt int64
+	if exp := (hotToken{tokens: tokens, expiresAt: expiresAt}).refreshExpiresAt(); !exp.IsZero() {
+		expireAt = exp.UnixMilli()
+	} else if r.TTL > 0 {
+		expireAt = time.Now().Add(r.TTL).UnixMilli()
+	}
+
+	n, err := saveScript.Run(ctx, r.Client,
+		[]string{r.key(userKey)},
+		version,
+		data,
+		expiresAt.UTC().Format(time.RFC3339Nano),
+		expireAt,
+	).Int64()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+func (r *RedisTokenStore) Delete(ctx context.Context, userKey string) error {
+	return r.Client.Del(ctx, r.key(userKey)).Err()
+}
+
+// List scans for matching keys with SCAN, so it does not block the server
+// but may miss or repeat keys written during the scan.
+//
+// On a *redis.ClusterClient SCAN runs against a single node, so List only
+// sees the keys that node holds; list each master's keys through
+// ClusterClient.ForEachMaster with a store per node instead.
+func (r *RedisTokenStore) List(ctx context.Context, prefix string, fn func(TokenRecord) error) error {
+	keyPrefix := r.key("")
+	it := r.Client.Scan(ctx, 0, escapeGlob(keyPrefix+prefix)+"*", 100).Iterator()
+	for it.Next(ctx) {
+		key := it.Val()
+		fields, err := r.Client.HGetAll(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		// the key may have expired since the scan saw it
+		if len(fields) == 0 {
+			continue
+		}
+		rec, err := r.record(strings.TrimPrefix(key, keyPrefix), fields)
+		if err != nil {
+			return err
+		}
+		if err := fn(*rec); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// escapeGlob quotes the characters SCAN MATCH treats as patterns.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
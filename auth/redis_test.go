@@ -0,0 +1,73 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Seann-Moser/mangadex/auth"
+	"github.com/alicebob/miniredis/v2"
+	redis "github.com/redis/go-redis/v9"
+)
+
+func newRedisStore(t *testing.T) (*auth.RedisTokenStore, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return &auth.RedisTokenStore{Client: client}, mr
+}
+
+func TestRedisTokenStore(t *testing.T) {
+	s, _ := newRedisStore(t)
+	testTokenStore(t, s)
+}
+
+func TestRedisTokenStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	s, mr := newRedisStore(t)
+	s.TTL = 24 * time.Hour
+	now := time.Now()
+	key := auth.DefaultRedisKeyPrefix
+
+	// the key expires with the refresh token, counted from issue time
+	_ = s.Save(ctx, "refresh", auth.OAuthTokens{AccessToken: "a", ExpiresIn: 900, RefreshExpiresIn: 3600}, nil, now.Add(15*time.Minute))
+	if ttl := mr.TTL(key + "refresh"); ttl < 59*time.Minute || ttl > time.Hour {
+		t.Errorf("refresh ttl = %v, want about an hour", ttl)
+	}
+	// offline tokens without a known lifetime fall back to TTL
+	_ = s.Save(ctx, "offline", auth.OAuthTokens{AccessToken: "b", ExpiresIn: 900}, nil, now.Add(15*time.Minute))
+	if ttl := mr.TTL(key + "offline"); ttl < 23*time.Hour || ttl > 24*time.Hour {
+		t.Errorf("offline ttl = %v, want TTL", ttl)
+	}
+
+	mr.FastForward(2 * time.Hour)
+	if _, err := s.LoadRecord(ctx, "refresh"); !errors.Is(err, auth.ErrTokenNotFound) {
+		t.Errorf("expired key kept: %v", err)
+	}
+	if _, err := s.LoadRecord(ctx, "offline"); err != nil {
+		t.Errorf("offline: %v", err)
+	}
+
+	// without TTL a later save persists the key again
+	s.TTL = 0
+	_ = s.Save(ctx, "offline", auth.OAuthTokens{AccessToken: "c"}, nil, now)
+	if ttl := mr.TTL(key + "offline"); ttl != 0 {
+		t.Errorf("ttl = %v after saving without a lifetime, want none", ttl)
+	}
+}
+
+func TestRedisTokenStoreEncrypted(t *testing.T) {
+	s, mr := newRedisStore(t)
+	s.Cipher = testCipher(t)
+	testTokenStore(t, s)
+	testEncryptedTokenStore(t, s, func(userKey string) []byte {
+		return []byte(mr.HGet(auth.DefaultRedisKeyPrefix+userKey, "data"))
+	})
+}
+
+func TestRedisTokenStoreWithClient(t *testing.T) {
+	s, _ := newRedisStore(t)
+	testTokenStoreWithClient(t, s)
+}
@@ -0,0 +1,332 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultSessionCheckInterval is how often SessionManager.Run checks
+// session deadlines.
+const DefaultSessionCheckInterval = time.Minute
+
+type SessionEventType string
+
+const (
+	SessionRefreshed     SessionEventType = "refreshed"
+	SessionRefreshFailed SessionEventType = "refresh_failed"
+
+	// SessionExpired means the refresh token expired or was rejected; the
+	// session is dropped and the user has to log in again.
+	SessionExpired SessionEventType = "expired"
+)
+
+type SessionEvent struct {
+	Type    SessionEventType
+	Session Session
+	Err     error
+}
+
+// Session describes a tracked user's tokens.
+type Session struct {
+	UserKey         string
+	AccessExpiresAt time.Time
+
+	// RefreshExpiresAt is zero when the refresh token does not expire.
+	RefreshExpiresAt time.Time
+
+	// IssuedAt is when the current tokens were issued.
+	IssuedAt time.Time
+
+	// Failures counts background refreshes that failed in a row.
+	Failures  int
+	LastError error
+
+	// fixedDeadline is set once a refresh did not move RefreshExpiresAt, so
+	// refreshing ahead of it is pointless.
+	fixedDeadline bool
+}
+
+// SessionManager keeps users' sessions alive in the background. Access
+// tokens are refreshed on demand by OAuthClient, but refresh tokens expire
+// too, so an idle user would otherwise find their session gone; Run
+// refreshes tracked sessions ahead of the refresh token's expiry.
+type SessionManager struct {
+	Client        *OAuthClient
+	CheckInterval time.Duration
+
+	// RefreshAhead is how long before the refresh token expires it is
+	// refreshed. Zero refreshes once three quarters of its lifetime have
+	// passed.
+	RefreshAhead time.Duration
+
+	// KeepAccessTokens also refreshes access tokens ahead of expiry, so
+	// requests never wait on a refresh.
+	KeepAccessTokens bool
+
+	OnEvent func(SessionEvent)
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*Session
+}
+
+func NewSessionManager(client *OAuthClient) *SessionManager {
+	return &SessionManager{
+		Client:        client,
+		CheckInterval: DefaultSessionCheckInterval,
+		sessions:      map[string]*Session{},
+	}
+}
+
+// Login logs the user in and tracks the session.
+func (m *SessionManager) Login(ctx context.Context, username, password string, creds *OAuthCredentials) error {
+	if err := m.Client.Login(ctx, username, password, creds); err != nil {
+		return err
+	}
+	return m.Track(ctx, creds.UserKey)
+}
+
+// Track starts managing an existing session, such as one restored from the
+// store after a restart.
+func (m *SessionManager) Track(ctx context.Context, userKey string) error {
+	hot, ok := m.Client.hotToken(userKey)
+	if !ok {
+		var err error
+		if hot, err = m.Client.current(ctx, userKey); err != nil {
+			return err
+		}
+	}
+
+	m.track(userKey, hot)
+	return nil
+}
+
+// Restore tracks every stored session whose user key starts with prefix,
+// typically on startup.
+func (m *SessionManager) Restore(ctx context.Context, prefix string) error {
+	if m.Client.Store == nil {
+		return nil
+	}
+	return m.Client.Store.List(ctx, prefix, func(rec TokenRecord) error {
+		m.track(rec.UserKey, hotToken{
+			tokens:    rec.Tokens,
+			creds:     m.Client.resolveCreds(rec.Creds),
+			expiresAt: rec.ExpiresAt,
+			version:   rec.Version,
+		})
+		return nil
+	})
+}
+
+func (m *SessionManager) track(userKey string, hot hotToken) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sessions == nil {
+		m.sessions = map[string]*Session{}
+	}
+	s := &Session{UserKey: userKey}
+	s.update(hot)
+	m.sessions[userKey] = s
+}
+
+// Untrack stops managing the session without revoking it.
+func (m *SessionManager) Untrack(userKey string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, userKey)
+}
+
+// Revoke ends the session at the identity provider, deletes its tokens and
+// stops tracking it.
+func (m *SessionManager) Revoke(ctx context.Context, userKey string) error {
+	if err := m.Client.Logout(ctx, userKey); err != nil {
+		return err
+	}
+	m.Untrack(userKey)
+	return nil
+}
+
+// Session returns the tracked session for userKey.
+func (m *SessionManager) Session(userKey string) (Session, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[userKey]
+	if !ok {
+		return Session{}, false
+	}
+	return *s, true
+}
+
+// Sessions returns the tracked sessions, as of the last check, sorted by
+// user key.
+func (m *SessionManager) Sessions() []Session {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]Session, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].UserKey < out[j].UserKey })
+	return out
+}
+
+// Run checks sessions immediately and then every CheckInterval until ctx is
+// done.
+func (m *SessionManager) Run(ctx context.Context) error {
+	interval := m.CheckInterval
+	if interval <= 0 {
+		interval = DefaultSessionCheckInterval
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		m.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Check refreshes sessions that are due and drops expired ones.
+func (m *SessionManager) Check(ctx context.Context) {
+	for _, s := range m.Sessions() {
+		if ctx.Err() != nil {
+			return
+		}
+		m.check(ctx, s)
+	}
+}
+
+func (m *SessionManager) check(ctx context.Context, s Session) {
+	// pick up refreshes done by requests since the last check
+	if hot, ok := m.Client.hotToken(s.UserKey); ok {
+		m.mu.Lock()
+		if cur, ok := m.sessions[s.UserKey]; ok {
+			cur.update(hot)
+			s = *cur
+		}
+		m.mu.Unlock()
+	}
+
+	now := m.now()
+	if expired(s, now) || m.due(s, now) {
+		// another replica sharing the store may have refreshed the session
+		hot, err := m.Client.current(ctx, s.UserKey)
+		switch {
+		case errors.Is(err, ErrTokenNotFound):
+			m.expire(s, err)
+			return
+		case err == nil:
+			m.mu.Lock()
+			if cur, ok := m.sessions[s.UserKey]; ok {
+				cur.update(hot)
+				s = *cur
+			}
+			m.mu.Unlock()
+		}
+	}
+	if expired(s, now) {
+		m.expire(s, ErrSessionExpired)
+		return
+	}
+	if !m.due(s, now) {
+		return
+	}
+
+	_, err := m.Client.Refresh(ctx, s.UserKey)
+	var te *TokenError
+	switch {
+	case errors.Is(err, ErrSessionExpired),
+		errors.As(err, &te) && te.Code == "invalid_grant":
+		m.expire(s, err)
+		return
+	case err != nil:
+		m.mu.Lock()
+		cur, ok := m.sessions[s.UserKey]
+		if ok {
+			cur.Failures++
+			cur.LastError = err
+			s = *cur
+		}
+		m.mu.Unlock()
+		if ok {
+			m.emit(SessionEvent{Type: SessionRefreshFailed, Session: s, Err: err})
+		}
+		return
+	}
+
+	hot, _ := m.Client.hotToken(s.UserKey)
+	m.mu.Lock()
+	cur, ok := m.sessions[s.UserKey]
+	if ok {
+		cur.fixedDeadline = !cur.RefreshExpiresAt.IsZero() && !hot.refreshExpiresAt().After(cur.RefreshExpiresAt)
+		cur.update(hot)
+		cur.Failures = 0
+		cur.LastError = nil
+		s = *cur
+	}
+	m.mu.Unlock()
+	if ok {
+		m.emit(SessionEvent{Type: SessionRefreshed, Session: s})
+	}
+}
+
+func (m *SessionManager) now() time.Time {
+	if m.Now != nil {
+		return m.Now()
+	}
+	return time.Now()
+}
+
+// expired reports whether the session's refresh token has expired.
+func expired(s Session, now time.Time) bool {
+	return !s.RefreshExpiresAt.IsZero() && !now.Before(s.RefreshExpiresAt)
+}
+
+// due reports whether the session should be refreshed now.
+func (m *SessionManager) due(s Session, now time.Time) bool {
+	if m.KeepAccessTokens && !now.Before(s.AccessExpiresAt.Add(-m.Client.refreshBefore())) {
+		return true
+	}
+	if s.RefreshExpiresAt.IsZero() || s.fixedDeadline {
+		return false
+	}
+	ahead := m.RefreshAhead
+	if ahead <= 0 {
+		ahead = s.RefreshExpiresAt.Sub(s.IssuedAt) / 4
+	}
+	return !now.Before(s.RefreshExpiresAt.Add(-ahead))
+}
+
+func (m *SessionManager) expire(s Session, err error) {
+	m.mu.Lock()
+	_, ok := m.sessions[s.UserKey]
+	delete(m.sessions, s.UserKey)
+	m.mu.Unlock()
+	if !ok {
+		return
+	}
+	m.Client.Forget(s.UserKey)
+	s.LastError = err
+	m.emit(SessionEvent{Type: SessionExpired, Session: s, Err: err})
+}
+
+func (m *SessionManager) emit(e SessionEvent) {
+	if m.OnEvent != nil {
+		m.OnEvent(e)
+	}
+}
+
+// update copies the token deadlines from hot.
+func (s *Session) update(hot hotToken) {
+	s.AccessExpiresAt = hot.expiresAt
+	s.RefreshExpiresAt = hot.refreshExpiresAt()
+	s.IssuedAt = hot.expiresAt.Add(-time.Duration(hot.tokens.ExpiresIn) * time.Second)
+}
@@ -0,0 +1,160 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Seann-Moser/mangadex/auth"
+	"github.com/Seann-Moser/mangadex/auth/authtest"
+)
+
+func newTestSessions(t *testing.T) (*auth.SessionManager, *authtest.Server, *[]auth.SessionEvent) {
+	t.Helper()
+	c, idp := newTestClient(t)
+	m := auth.NewSessionManager(c)
+	var events []auth.SessionEvent
+	m.OnEvent = func(e auth.SessionEvent) { events = append(events, e) }
+
+	for _, key := range []string{"u1", "u2"} {
+		if err := m.Login(context.Background(), "reader", "hunter2", &auth.OAuthCredentials{UserKey: key}); err != nil {
+			t.Fatalf("login %s: %v", key, err)
+		}
+	}
+	return m, idp, &events
+}
+
+func TestSessionRefreshAhead(t *testing.T) {
+	m, idp, events := newTestSessions(t)
+	before, _ := m.Session("u1")
+	if before.RefreshExpiresAt.IsZero() {
+		t.Fatal("refresh token expiry not tracked")
+	}
+
+	m.Check(context.Background())
+	if n := countGrants(idp, "refresh_token"); n != 0 {
+		t.Fatalf("%d refreshes for fresh sessions", n)
+	}
+
+	m.RefreshAhead = 31 * 24 * time.Hour
+	m.Check(context.Background())
+	if len(*events) != 2 || (*events)[0].Type != auth.SessionRefreshed {
+		t.Fatalf("events %+v, want two refreshed", *events)
+	}
+	after, _ := m.Session("u1")
+	if !after.RefreshExpiresAt.After(before.RefreshExpiresAt) {
+		t.Errorf("refresh expiry %v not extended past %v", after.RefreshExpiresAt, before.RefreshExpiresAt)
+	}
+}
+
+func TestSessionRefreshFailure(t *testing.T) {
+	m, idp, events := newTestSessions(t)
+	m.RefreshAhead = 31 * 24 * time.Hour
+
+	idp.Fail(authtest.Failure{StatusCode: http.StatusBadGateway})
+	m.Check(context.Background())
+	idp.ClearFailures()
+
+	if len(*events) != 2 || (*events)[0].Type != auth.SessionRefreshFailed {
+		t.Fatalf("events %+v, want two refresh_failed", *events)
+	}
+	s, ok := m.Session("u1")
+	if !ok || s.Failures != 1 || s.LastError == nil {
+		t.Errorf("session %+v, want one failure recorded", s)
+	}
+}
+
+func TestSessionExpired(t *testing.T) {
+	m, idp, events := newTestSessions(t)
+	m.RefreshAhead = 31 * 24 * time.Hour
+
+	idp.ExpireRefreshTokens()
+	m.Check(context.Background())
+	if len(*events) != 2 || (*events)[0].Type != auth.SessionExpired {
+		t.Fatalf("events %+v, want two expired", *events)
+	}
+	if n := len(m.Sessions()); n != 0 {
+		t.Errorf("%d sessions left, want 0", n)
+	}
+}
+
+func TestSessionExpiresLocally(t *testing.T) {
+	c, idp := newTestClient(t)
+	idp.RefreshTokenTTL = time.Hour
+	m := auth.NewSessionManager(c)
+	now := time.Now()
+	m.Now = func() time.Time { return now }
+	var events []auth.SessionEvent
+	m.OnEvent = func(e auth.SessionEvent) { events = append(events, e) }
+	if err := m.Login(context.Background(), "reader", "hunter2", &auth.OAuthCredentials{UserKey: "u1"}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	now = now.Add(2 * time.Hour)
+	m.Check(context.Background())
+	if len(events) != 1 || events[0].Type != auth.SessionExpired || !errors.Is(events[0].Err, auth.ErrSessionExpired) {
+		t.Fatalf("events %+v, want expired", events)
+	}
+	if n := countGrants(idp, "refresh_token"); n != 0 {
+		t.Errorf("%d refresh grants for an expired session", n)
+	}
+}
+
+func TestSessionRefreshedByAnotherReplica(t *testing.T) {
+	ctx := context.Background()
+	other, idp := newTestClient(t)
+	idp.RefreshTokenTTL = time.Hour
+	if err := other.Login(ctx, "reader", "hunter2", &auth.OAuthCredentials{UserKey: "u1"}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	// this replica only knows the session from the shared store
+	c := &auth.OAuthClient{HTTPClient: other.HTTPClient, Endpoints: other.Endpoints, DefaultCreds: other.DefaultCreds, Store: other.Store}
+	m := auth.NewSessionManager(c)
+	now := time.Now()
+	m.Now = func() time.Time { return now }
+	var events []auth.SessionEvent
+	m.OnEvent = func(e auth.SessionEvent) { events = append(events, e) }
+	if err := m.Restore(ctx, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	idp.RefreshTokenTTL = 3 * time.Hour
+	if _, err := other.Refresh(ctx, "u1"); err != nil {
+		t.Fatalf("refresh elsewhere: %v", err)
+	}
+	now = now.Add(2 * time.Hour)
+	m.Check(ctx)
+	if len(events) != 0 {
+		t.Fatalf("events %+v for a session kept alive elsewhere", events)
+	}
+	s, ok := m.Session("u1")
+	if !ok || !s.RefreshExpiresAt.After(now) {
+		t.Fatalf("session %+v, %v; want the refreshed deadline", s, ok)
+	}
+	if n := countGrants(idp, "refresh_token"); n != 1 {
+		t.Errorf("%d refresh grants, want only the other replica's", n)
+	}
+
+	// a session logged out elsewhere is dropped
+	if err := other.Logout(ctx, "u1"); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(2 * time.Hour)
+	m.Check(ctx)
+	if len(events) != 1 || events[0].Type != auth.SessionExpired || !errors.Is(events[0].Err, auth.ErrTokenNotFound) {
+		t.Fatalf("events %+v, want expired", events)
+	}
+}
+
+func TestSessionRevoke(t *testing.T) {
+	m, _, _ := newTestSessions(t)
+	if err := m.Revoke(context.Background(), "u1"); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	sessions := m.Sessions()
+	if len(sessions) != 1 || sessions[0].UserKey != "u2" {
+		t.Errorf("sessions %+v, want only u2", sessions)
+	}
+}
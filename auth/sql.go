@@ -0,0 +1,383 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SQLDialect selects the SQL flavour SQLTokenStore writes.
+type SQLDialect int
+
+const (
+	DialectPostgres SQLDialect = iota
+	DialectSQLite
+)
+
+const (
+	// DefaultSQLTable is the table SQLTokenStore uses unless Table is set.
+	DefaultSQLTable = "oauth_tokens"
+
+	// DefaultSweepInterval is how often RunSweeper deletes expired rows.
+	DefaultSweepInterval = time.Hour
+)
+
+var sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// sqlMigrations are applied in order and recorded in <table>_migrations.
+// Only ever append to this list. Times are unix milliseconds so both
+// dialects compare them the same way.
+var sqlMigrations = []func(d SQLDialect, table string) string{
+	func(d SQLDialect, table string) string {
+		blob := "BYTEA"
+		if d == DialectSQLite {
+			blob = "BLOB"
+		}
+		return `CREATE TABLE IF NOT EXISTS ` + table + ` (
+	user_key           TEXT PRIMARY KEY,
+	data               ` + blob + ` NOT NULL,
+	expires_at         BIGINT NOT NULL,
+	refresh_expires_at BIGINT,
+	version            BIGINT NOT NULL,
+	updated_at         BIGINT NOT NULL
+)`
+	},
+	func(d SQLDialect, table string) string {
+		return `CREATE INDEX IF NOT EXISTS ` + table + `_refresh_expires_at ON ` + table + ` (refresh_expires_at)`
+	},
+}
+
+var _ TokenStore = (*SQLTokenStore)(nil)
+
+// SQLTokenStore keeps tokens in a relational database through database/sql.
+// Rows hold the payload in the EncryptedMongoTokenStore format, encrypted
+// when Cipher is set. Run Migrate before first use.
+type SQLTokenStore struct {
+	DB      *sql.DB
+	Dialect SQLDialect
+
+	// Table defaults to DefaultSQLTable.
+	Table string
+
+	Cipher TokenCipher
+
+	// OnSweepError is called when a scheduled sweep fails.
+	OnSweepError func(error)
+}
+
+func NewSQLTokenStore(db *sql.DB, dialect SQLDialect) *SQLTokenStore {
+	return &SQLTokenStore{DB: db, Dialect: dialect}
+}
+
+func (s *SQLTokenStore) table() (string, error) {
+	table := s.Table
+	if table == "" {
+		table = DefaultSQLTable
+	}
+	if !sqlIdentifier.MatchString(table) {
+		return "", fmt.Errorf("invalid table name %q", table)
+	}
+	return table, nil
+}
+
+// query formats a statement written with ? placeholders and %[1]s for the
+// table name into the store's dialect.
+func (s *SQLTokenStore) query(format string) (string, error) {
+	table, err := s.table()
+	if err != nil {
+		return "", err
+	}
+	q := fmt.Sprintf(format, table)
+	if s.Dialect != DialectPostgres {
+		return q, nil
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range q {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String(), nil
+}
+
+// Migrate creates or upgrades the schema. It is safe to run on every
+// start. Pending migrations are applied in one transaction; on Postgres it
+// first takes a transaction-level advisory lock, so processes starting
+// together apply each migration once. SQLite serializes the writes itself
+// but reports SQLITE_BUSY to the loser, so run Migrate from a single
+// process there.
+func (s *SQLTokenStore) Migrate(ctx context.Context) error {
+	table, err := s.table()
+	if err != nil {
+		return err
+	}
+	record, err := s.query(`INSERT INTO %[1]s_migrations (version, applied_at) VALUES (?, ?)`)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if s.Dialect == DialectPostgres {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockKey(table)); err != nil {
+			return fmt.Errorf("locking migrations: %w", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS `+table+`_migrations (version INTEGER PRIMARY KEY, applied_at BIGINT NOT NULL)`,
+	); err != nil {
+		return err
+	}
+
+	var applied int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(version), 0) FROM `+table+`_migrations`,
+	).Scan(&applied); err != nil {
+		return err
+	}
+	for i := applied; i < len(sqlMigrations); i++ {
+		if _, err := tx.ExecContext(ctx, sqlMigrations[i](s.Dialect, table)); err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if _, err := tx.ExecContext(ctx, record, i+1, time.Now().UnixMilli()); err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// migrationLockKey is the advisory lock id guarding a table's migrations.
+func migrationLockKey(table string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte("mangadex/auth migrations:" + table))
+	return int64(h.Sum64())
+}
+
+func (s *SQLTokenStore) Load(
+	ctx context.Context,
+	userKey string,
+) (*OAuthTokens, *OAuthCredentials, time.Time, error) {
+
+	rec, err := s.LoadRecord(ctx, userKey)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	return &rec.Tokens, rec.Creds, rec.ExpiresAt, nil
+}
+
+func (s *SQLTokenStore) LoadRecord(ctx context.Context, userKey string) (*TokenRecord, error) {
+	q, err := s.query(`SELECT user_key, data, expires_at, version FROM %[1]s WHERE user_key = ?`)
+	if err != nil {
+		return nil, err
+	}
+	rec, err := s.scan(s.DB.QueryRowContext(ctx, q, userKey))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %w", ErrTokenNotFound, err)
+	}
+	return rec, err
+}
+
+func (s *SQLTokenStore) scan(row interface{ Scan(...any) error }) (*TokenRecord, error) {
+	var (
+		rec       TokenRecord
+		data      []byte
+		expiresAt int64
+	)
+	if err := row.Scan(&rec.UserKey, &data, &expiresAt, &rec.Version); err != nil {
+		return nil, err
+	}
+	payload, err := decodeTokenPayload(s.Cipher, rec.UserKey, data)
+	if err != nil {
+		return nil, fmt.Errorf("token %s: %w", rec.UserKey, err)
+	}
+	rec.Tokens = payload.Tokens
+	rec.Creds = payload.Creds
+	rec.ExpiresAt = time.UnixMilli(expiresAt)
+	return &rec, nil
+}
+
+// row holds the column values for a write.
+func (s *SQLTokenStore) row(userKey string, tokens OAuthTokens, creds *OAuthCredentials, expiresAt time.Time) (data []byte, refreshExpiresAt sql.NullInt64, err error) {
+	data, err = encodeTokenPayload(s.Cipher, userKey, tokens, creds)
+	if err != nil {
+		return nil, refreshExpiresAt, err
+	}
+	if exp := (hotToken{tokens: tokens, expiresAt: expiresAt}).refreshExpiresAt(); !exp.IsZero() {
+		refreshExpiresAt = sql.NullInt64{Int64: exp.UnixMilli(), Valid: true}
+	}
+	return data, refreshExpiresAt, nil
+}
+
+func (s *SQLTokenStore) Save(
+	ctx context.Context,
+	userKey string,
+	tokens OAuthTokens,
+	creds *OAuthCredentials,
+	expiresAt time.Time,
+) error {
+
+	data, refreshExpiresAt, err := s.row(userKey, tokens, creds, expiresAt)
+	if err != nil {
+		return err
+	}
+	// both Postgres and SQLite (3.24+) accept this upsert
+	q, err := s.query(`INSERT INTO %[1]s (user_key, data, expires_at, refresh_expires_at, version, updated_at)
+VALUES (?, ?, ?, ?, 1, ?)
+ON CONFLICT (user_key) DO UPDATE SET
+	data = excluded.data,
+	expires_at = excluded.expires_at,
+	refresh_expires_at = excluded.refresh_expires_at,
+	version = %[1]s.version + 1,
+	updated_at = excluded.updated_at`)
+	if err != nil {
+		return err
+	}
+	_, err = s.DB.ExecContext(ctx, q, userKey, data, expiresAt.UnixMilli(), refreshExpiresAt, time.Now().UnixMilli())
+	return err
+}
+
+func (s *SQLTokenStore) SaveIfUnchanged(
+	ctx context.Context,
+	userKey string,
+	version int64,
+	tokens OAuthTokens,
+	creds *OAuthCredentials,
+	expiresAt time.Time,
+) error {
+
+	data, refreshExpiresAt, err := s.row(userKey, tokens, creds, expiresAt)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UnixMilli()
+
+	var res sql.Result
+	if version == 0 {
+		q, err := s.query(`INSERT INTO %[1]s (user_key, data, expires_at, refresh_expires_at, version, updated_at)
+VALUES (?, ?, ?, ?, 1, ?)
+ON CONFLICT (user_key) DO NOTHING`)
+		if err != nil {
+			return err
+		}
+		res, err = s.DB.ExecContext(ctx, q, userKey, data, expiresAt.UnixMilli(), refreshExpiresAt, now)
+		if err != nil {
+			return err
+		}
+	} else {
+		q, err := s.query(`UPDATE %[1]s SET
+	data = ?, expires_at = ?, refresh_expires_at = ?, version = version + 1, updated_at = ?
+WHERE user_key = ? AND version = ?`)
+		if err != nil {
+			return err
+		}
+		res, err = s.DB.ExecContext(ctx, q, data, expiresAt.UnixMilli(), refreshExpiresAt, now, userKey, version)
+		if err != nil {
+			return err
+		}
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+func (s *SQLTokenStore) Delete(ctx context.Context, userKey string) error {
+	q, err := s.query(`DELETE FROM %[1]s WHERE user_key = ?`)
+	if err != nil {
+		return err
+	}
+	_, err = s.DB.ExecContext(ctx, q, userKey)
+	return err
+}
+
+func (s *SQLTokenStore) List(ctx context.Context, prefix string, fn func(TokenRecord) error) error {
+	q, err := s.query(`SELECT user_key, data, expires_at, version FROM %[1]s WHERE user_key LIKE ? ESCAPE '\'`)
+	if err != nil {
+		return err
+	}
+	rows, err := s.DB.QueryContext(ctx, q, escapeLike(prefix)+"%")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	// read all rows before calling fn, so that fn can use the store without
+	// waiting for the connection this cursor holds
+	var recs []TokenRecord
+	for rows.Next() {
+		rec, err := s.scan(rows)
+		if err != nil {
+			return err
+		}
+		recs = append(recs, *rec)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	for _, rec := range recs {
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sweep deletes rows whose refresh token expired before now and returns how
+// many were removed. Rows without a known refresh expiry are kept.
+func (s *SQLTokenStore) Sweep(ctx context.Context, now time.Time) (int64, error) {
+	q, err := s.query(`DELETE FROM %[1]s WHERE refresh_expires_at IS NOT NULL AND refresh_expires_at < ?`)
+	if err != nil {
+		return 0, err
+	}
+	res, err := s.DB.ExecContext(ctx, q, now.UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// RunSweeper sweeps every interval (DefaultSweepInterval when zero) until
+// ctx is done.
+func (s *SQLTokenStore) RunSweeper(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultSweepInterval
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		if _, err := s.Sweep(ctx, time.Now()); err != nil && s.OnSweepError != nil {
+			s.OnSweepError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func escapeLike(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(s)
+}
@@ -0,0 +1,77 @@
+package auth_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Seann-Moser/mangadex/auth"
+	_ "modernc.org/sqlite"
+)
+
+func newSQLiteStore(t *testing.T) *auth.SQLTokenStore {
+	t.Helper()
+	db, err := sql.Open("sqlite", "file:"+t.Name()+"?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	db.SetMaxOpenConns(1)
+
+	s := auth.NewSQLTokenStore(db, auth.DialectSQLite)
+	if err := s.Migrate(context.Background()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	// a second run finds nothing to do
+	if err := s.Migrate(context.Background()); err != nil {
+		t.Fatalf("migrate again: %v", err)
+	}
+	return s
+}
+
+func TestSQLTokenStore(t *testing.T) {
+	testTokenStore(t, newSQLiteStore(t))
+}
+
+func TestSQLTokenStoreEncrypted(t *testing.T) {
+	s := newSQLiteStore(t)
+	s.Cipher = testCipher(t)
+	testTokenStore(t, s)
+	testEncryptedTokenStore(t, s, func(userKey string) []byte {
+		var data []byte
+		if err := s.DB.QueryRow(`SELECT data FROM oauth_tokens WHERE user_key = ?`, userKey).Scan(&data); err != nil {
+			t.Fatal(err)
+		}
+		return data
+	})
+}
+
+func TestSQLTokenStoreSweep(t *testing.T) {
+	ctx := context.Background()
+	s := newSQLiteStore(t)
+	now := time.Now()
+
+	// refresh token lifetimes count from when the access token was issued
+	_ = s.Save(ctx, "expired", auth.OAuthTokens{AccessToken: "a", ExpiresIn: 60, RefreshExpiresIn: 60}, nil, now.Add(-time.Hour))
+	_ = s.Save(ctx, "live", auth.OAuthTokens{AccessToken: "b", ExpiresIn: 60, RefreshExpiresIn: 3600}, nil, now.Add(-time.Minute))
+	_ = s.Save(ctx, "offline", auth.OAuthTokens{AccessToken: "c", ExpiresIn: 60}, nil, now.Add(-time.Hour))
+
+	n, err := s.Sweep(ctx, now)
+	if err != nil || n != 1 {
+		t.Fatalf("swept %d, %v; want 1", n, err)
+	}
+	if _, err := s.LoadRecord(ctx, "expired"); !errors.Is(err, auth.ErrTokenNotFound) {
+		t.Errorf("expired row kept: %v", err)
+	}
+	for _, key := range []string{"live", "offline"} {
+		if _, err := s.LoadRecord(ctx, key); err != nil {
+			t.Errorf("%s: %v", key, err)
+		}
+	}
+}
+
+func TestSQLTokenStoreWithClient(t *testing.T) {
+	testTokenStoreWithClient(t, newSQLiteStore(t))
+}
@@ -0,0 +1,137 @@
+package auth_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Seann-Moser/mangadex/auth"
+)
+
+// testTokenStore runs the TokenStore contract against an empty store.
+func testTokenStore(t *testing.T, s auth.TokenStore) {
+	t.Helper()
+	ctx := context.Background()
+	exp := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+	creds := &auth.OAuthCredentials{UserKey: "discord:1", ClientID: "personal-client", ClientSecret: "secret"}
+
+	if _, err := s.LoadRecord(ctx, "discord:1"); !errors.Is(err, auth.ErrTokenNotFound) {
+		t.Fatalf("load missing: got %v, want ErrTokenNotFound", err)
+	}
+	if err := s.SaveIfUnchanged(ctx, "discord:1", 0, auth.OAuthTokens{AccessToken: "a1"}, creds, exp); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := s.SaveIfUnchanged(ctx, "discord:1", 0, auth.OAuthTokens{AccessToken: "a2"}, creds, exp); !errors.Is(err, auth.ErrVersionConflict) {
+		t.Fatalf("second create: got %v, want ErrVersionConflict", err)
+	}
+
+	tok, gotCreds, gotExp, err := s.Load(ctx, "discord:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.AccessToken != "a1" || gotCreds == nil || gotCreds.ClientID != creds.ClientID || gotCreds.ClientSecret != creds.ClientSecret || !gotExp.Equal(exp) {
+		t.Errorf("loaded %+v %+v %v", tok, gotCreds, gotExp)
+	}
+
+	if err := s.Save(ctx, "discord:1", auth.OAuthTokens{AccessToken: "a3"}, creds, exp); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if err := s.SaveIfUnchanged(ctx, "discord:1", 1, auth.OAuthTokens{AccessToken: "a4"}, creds, exp); !errors.Is(err, auth.ErrVersionConflict) {
+		t.Fatalf("stale save: got %v, want ErrVersionConflict", err)
+	}
+	if err := s.SaveIfUnchanged(ctx, "discord:1", 2, auth.OAuthTokens{AccessToken: "a4"}, creds, exp); err != nil {
+		t.Fatalf("save at current version: %v", err)
+	}
+	rec, err := s.LoadRecord(ctx, "discord:1")
+	if err != nil || rec.Version != 3 || rec.Tokens.AccessToken != "a4" {
+		t.Fatalf("got %+v, %v; want a4 at version 3", rec, err)
+	}
+
+	// wildcards of the backend's pattern syntax are matched literally
+	for _, key := range []string{"discord:2", "discord_3", "disc*rd:4", "discord%5", "web:1"} {
+		if err := s.Save(ctx, key, auth.OAuthTokens{AccessToken: key}, nil, exp); err != nil {
+			t.Fatalf("save %s: %v", key, err)
+		}
+	}
+	for prefix, want := range map[string][]string{
+		"discord:":  {"discord:1", "discord:2"},
+		"discord_":  {"discord_3"},
+		"disc*":     {"disc*rd:4"},
+		"discord?3": nil,
+		"disc.rd":   nil,
+	} {
+		var keys []string
+		err := s.List(ctx, prefix, func(rec auth.TokenRecord) error {
+			keys = append(keys, rec.UserKey)
+			return nil
+		})
+		sort.Strings(keys)
+		if err != nil || !slices.Equal(keys, want) {
+			t.Errorf("list %q: got %v, %v; want %v", prefix, keys, err, want)
+		}
+	}
+
+	if err := s.Delete(ctx, "discord:1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, "discord:1"); err != nil {
+		t.Errorf("delete missing: %v", err)
+	}
+	if _, _, _, err := s.Load(ctx, "discord:1"); !errors.Is(err, auth.ErrTokenNotFound) {
+		t.Errorf("load deleted: got %v, want ErrTokenNotFound", err)
+	}
+}
+
+func testCipher(t *testing.T) auth.TokenCipher {
+	t.Helper()
+	cipher, err := auth.NewAESGCMCipher(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cipher
+}
+
+// testEncryptedTokenStore checks that a store with a cipher keeps nothing in
+// the clear. raw returns the stored payload of a user key.
+func testEncryptedTokenStore(t *testing.T, s auth.TokenStore, raw func(userKey string) []byte) {
+	t.Helper()
+	ctx := context.Background()
+
+	if err := s.Save(ctx, "u1", auth.OAuthTokens{AccessToken: "secret-access"}, nil, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if data := raw("u1"); len(data) == 0 || bytes.Contains(data, []byte("secret-access")) {
+		t.Errorf("payload stored in the clear: %q", data)
+	}
+	tok, _, _, err := s.Load(ctx, "u1")
+	if err != nil || tok.AccessToken != "secret-access" {
+		t.Errorf("got %+v, %v", tok, err)
+	}
+}
+
+// testTokenStoreWithClient logs in through an OAuthClient backed by s and
+// checks that the refresh is written back as the next version.
+func testTokenStoreWithClient(t *testing.T, s auth.TokenStore) {
+	t.Helper()
+	c, idp := newTestClient(t)
+	c.Store = s
+	ctx := context.Background()
+	idp.AccessTokenTTL = 0
+
+	if err := c.Login(ctx, "reader", "hunter2", &auth.OAuthCredentials{UserKey: "u1"}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	idp.AccessTokenTTL = 15 * time.Minute
+	bearer, err := authorize(t, c, "u1", "https://api.example.test/user/me")
+	if err != nil || bearer == "" {
+		t.Fatalf("apply auth: %q, %v", bearer, err)
+	}
+	rec, err := s.LoadRecord(ctx, "u1")
+	if err != nil || rec.Version != 2 || rec.Tokens.AccessToken != bearer {
+		t.Errorf("stored %+v, %v; want refreshed token at version 2", rec, err)
+	}
+}
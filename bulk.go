@@ -0,0 +1,475 @@
+package mangadex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Seann-Moser/mangadex/internal/chapternum"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// DefaultManifestName is the file BulkDownloader keeps its progress in.
+const DefaultManifestName = "manifest.json"
+
+// feedPageSize is the largest page the feed endpoints accept.
+const feedPageSize = 500
+
+// Manifest records which chapters of a manga have been downloaded.
+type Manifest struct {
+	MangaID   openapi_types.UUID          `json:"mangaId"`
+	Languages []string                    `json:"languages,omitempty"`
+	UpdatedAt time.Time                   `json:"updatedAt"`
+	Chapters  map[string]*ManifestChapter `json:"chapters"`
+
+	// Skipped maps chapter ids to the reason they were not downloaded.
+	Skipped map[string]string `json:"skipped,omitempty"`
+}
+
+// ManifestChapter is a downloaded chapter.
+type ManifestChapter struct {
+	Id          openapi_types.UUID `json:"id"`
+	Volume      string             `json:"volume,omitempty"`
+	Chapter     string             `json:"chapter,omitempty"`
+	Language    string             `json:"language,omitempty"`
+	Groups      []string           `json:"groups,omitempty"`
+	Dir         string             `json:"dir"`
+	Complete    bool               `json:"complete"`
+	CompletedAt time.Time          `json:"completedAt,omitempty"`
+	Pages       []ManifestPage     `json:"pages,omitempty"`
+}
+
+// ManifestPage is a page written to disk.
+type ManifestPage struct {
+	File    string      `json:"file"`
+	Source  string      `json:"source"`
+	Quality PageQuality `json:"quality"`
+	Bytes   int64       `json:"bytes"`
+	SHA256  string      `json:"sha256"`
+}
+
+// LoadManifest reads a manifest, returning an empty one if the file does not
+// exist.
+func LoadManifest(file string) (*Manifest, error) {
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Manifest{Chapters: map[string]*ManifestChapter{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("reading manifest %s: %w", file, err)
+	}
+	if m.Chapters == nil {
+		m.Chapters = map[string]*ManifestChapter{}
+	}
+	return m, nil
+}
+
+// Save atomically writes the manifest to file.
+func (m *Manifest) Save(file string) error {
+	m.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(file, data)
+}
+
+// BulkProgress is passed to BulkDownloader.OnChapter after every chapter.
+type BulkProgress struct {
+	Chapter Chapter
+	Index   int
+	Total   int
+	Resumed bool
+	Err     error
+}
+
+// BulkDownloader downloads every chapter of a manga into one directory per
+// chapter and can resume an interrupted run from its manifest.
+type BulkDownloader struct {
+	Client     ClientWithResponsesInterface
+	Downloader *ChapterDownloader
+
+	// Languages are the translated languages to fetch, most preferred first.
+	Languages []string
+
+	// GroupPreference ranks scanlation group ids when several releases of
+	// the same chapter exist. Unlisted groups rank after listed ones.
+	GroupPreference []openapi_types.UUID
+
+	// ManifestName defaults to DefaultManifestName.
+	ManifestName string
+
+	// ContinueOnError keeps going when a chapter fails; the first error is
+	// still returned at the end.
+	ContinueOnError bool
+
+	OnChapter func(BulkProgress)
+}
+
+// NewBulkDownloader creates a BulkDownloader using client.
+func NewBulkDownloader(client ClientWithResponsesInterface, languages ...string) *BulkDownloader {
+	d := NewChapterDownloader(client)
+	d.SkipExisting = true
+	return &BulkDownloader{
+		Client:     client,
+		Downloader: d,
+		Languages:  languages,
+	}
+}
+
+// Download fetches all chapters of mangaID into dir, skipping chapters the
+// manifest in dir already records as complete.
+func (b *BulkDownloader) Download(ctx context.Context, mangaID openapi_types.UUID, dir string) (*Manifest, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	manifestPath := filepath.Join(dir, b.manifestName())
+	m, err := LoadManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	m.MangaID = mangaID
+	m.Languages = b.Languages
+
+	feed, err := b.Feed(ctx, mangaID)
+	if err != nil {
+		return m, err
+	}
+
+	var available []Chapter
+	for _, c := range feed {
+		if reason := unavailableReason(c); reason != "" {
+			if c.Id != nil {
+				if m.Skipped == nil {
+					m.Skipped = map[string]string{}
+				}
+				m.Skipped[c.Id.String()] = reason
+			}
+			continue
+		}
+		available = append(available, c)
+	}
+	chapters := b.Dedupe(available)
+
+	var firstErr error
+	for i, c := range chapters {
+		if err := ctx.Err(); err != nil {
+			return m, err
+		}
+		p := BulkProgress{Chapter: c, Index: i, Total: len(chapters)}
+
+		key := c.Id.String()
+		if done, ok := m.Chapters[key]; ok && done.Complete && pagesExist(dir, done) {
+			p.Resumed = true
+			b.report(p)
+			continue
+		}
+
+		entry := newManifestChapter(c)
+		dl, err := b.Downloader.Download(ctx, *c.Id, filepath.Join(dir, entry.Dir))
+		if dl != nil {
+			for _, page := range dl.Pages {
+				if page.Path == "" {
+					continue
+				}
+				entry.Pages = append(entry.Pages, ManifestPage{
+					File:    filepath.Base(page.Path),
+					Source:  page.FileName,
+					Quality: page.Quality,
+					Bytes:   page.Bytes,
+					SHA256:  page.SHA256,
+				})
+			}
+		}
+		if err == nil {
+			entry.Complete = true
+			entry.CompletedAt = time.Now().UTC()
+		}
+		m.Chapters[key] = entry
+		if serr := m.Save(manifestPath); serr != nil && err == nil {
+			err = serr
+		}
+
+		p.Err = err
+		b.report(p)
+		if err != nil {
+			err = fmt.Errorf("chapter %s: %w", key, err)
+			if !b.ContinueOnError {
+				return m, err
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	if err := m.Save(manifestPath); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return m, firstErr
+}
+
+// Feed walks the manga feed for the configured languages, following
+// pagination until every chapter has been listed.
+func (b *BulkDownloader) Feed(ctx context.Context, mangaID openapi_types.UUID) ([]Chapter, error) {
+	asc := GetMangaIdFeedParamsOrderChapterAsc
+	vasc := GetMangaIdFeedParamsOrderVolumeAsc
+	params := &GetMangaIdFeedParams{
+		Limit: Int(feedPageSize),
+		Order: &struct {
+			Chapter    *GetMangaIdFeedParamsOrderChapter    `json:"chapter,omitempty" bson:"chapter"`
+			CreatedAt  *GetMangaIdFeedParamsOrderCreatedAt  `json:"createdAt,omitempty" bson:"createdAt"`
+			PublishAt  *GetMangaIdFeedParamsOrderPublishAt  `json:"publishAt,omitempty" bson:"publishAt"`
+			ReadableAt *GetMangaIdFeedParamsOrderReadableAt `json:"readableAt,omitempty" bson:"readableAt"`
+			UpdatedAt  *GetMangaIdFeedParamsOrderUpdatedAt  `json:"updatedAt,omitempty" bson:"updatedAt"`
+			Volume     *GetMangaIdFeedParamsOrderVolume     `json:"volume,omitempty" bson:"volume"`
+		}{Volume: &vasc, Chapter: &asc},
+	}
+	if len(b.Languages) > 0 {
+		params.TranslatedLanguage = &b.Languages
+	}
+
+	var out []Chapter
+	for offset := 0; ; {
+		params.Offset = &offset
+		resp, err := b.Client.GetMangaIdFeedWithResponse(ctx, mangaID, params)
+		if err != nil {
+			return nil, err
+		}
+		if resp.JSON200 == nil {
+			return nil, newAPIError(resp.HTTPResponse, resp.JSON400)
+		}
+		list := resp.JSON200
+		if list.Data == nil || len(*list.Data) == 0 {
+			break
+		}
+		out = append(out, *list.Data...)
+		offset += len(*list.Data)
+		if list.Total == nil || offset >= *list.Total {
+			break
+		}
+	}
+	return out, nil
+}
+
+// Dedupe keeps one release per chapter number, preferring earlier entries
+// of Languages, then earlier entries of GroupPreference. Releases of the
+// same number are only told apart by volume when both have one, so series
+// that restart numbering every volume keep each volume's chapters while a
+// release without a volume merges with the tagged one. Chapters without a
+// number (oneshots) are always kept.
+func (b *BulkDownloader) Dedupe(chapters []Chapter) []Chapter {
+	var order []*release
+	byNumber := map[string][]*release{}
+	for i, c := range chapters {
+		number, volume := releaseKey(c)
+		r := findRelease(byNumber[number], volume)
+		if r == nil {
+			r = &release{volume: volume, best: i}
+			byNumber[number] = append(byNumber[number], r)
+			order = append(order, r)
+			continue
+		}
+		if r.volume == "" {
+			r.volume = volume
+		}
+		if b.preferred(c, chapters[r.best]) {
+			r.best = i
+		}
+	}
+	out := make([]Chapter, 0, len(order))
+	for _, r := range order {
+		out = append(out, chapters[r.best])
+	}
+	return out
+}
+
+// preferred reports whether a should be chosen over b.
+func (b *BulkDownloader) preferred(x, y Chapter) bool {
+	if lx, ly := b.languageRank(x), b.languageRank(y); lx != ly {
+		return lx < ly
+	}
+	if gx, gy := b.groupRank(x), b.groupRank(y); gx != gy {
+		return gx < gy
+	}
+	return chapterPages(x) > chapterPages(y)
+}
+
+func (b *BulkDownloader) languageRank(c Chapter) int {
+	if c.Attributes == nil || c.Attributes.TranslatedLanguage == nil {
+		return len(b.Languages)
+	}
+	for i, l := range b.Languages {
+		if NormalizeLanguage(l) == NormalizeLanguage(*c.Attributes.TranslatedLanguage) {
+			return i
+		}
+	}
+	return len(b.Languages)
+}
+
+func (b *BulkDownloader) groupRank(c Chapter) int {
+	rank := len(b.GroupPreference)
+	if c.Relationships == nil {
+		return rank
+	}
+	for _, rel := range *c.Relationships {
+		if relationshipType(rel) != RelationshipScanlationGroup || rel.Id == nil {
+			continue
+		}
+		for i, g := range b.GroupPreference {
+			if g == *rel.Id && i < rank {
+				rank = i
+			}
+		}
+	}
+	return rank
+}
+
+func (b *BulkDownloader) report(p BulkProgress) {
+	if b.OnChapter != nil {
+		b.OnChapter(p)
+	}
+}
+
+func (b *BulkDownloader) manifestName() string {
+	if b.ManifestName == "" {
+		return DefaultManifestName
+	}
+	return b.ManifestName
+}
+
+// releaseKey returns the canonical chapter number and volume of c; volume is
+// empty when unset. Chapters without a number (oneshots) are keyed by id.
+func releaseKey(c Chapter) (number, volume string) {
+	if c.Attributes == nil || c.Attributes.Chapter == nil || strings.TrimSpace(*c.Attributes.Chapter) == "" {
+		if c.Id != nil {
+			return "id:" + c.Id.String(), ""
+		}
+		return "", ""
+	}
+	if v := c.Attributes.Volume; v != nil {
+		if p := chapternum.Split(*v); !p.None {
+			volume = p.Key()
+		}
+	}
+	return chapternum.Key(*c.Attributes.Chapter), volume
+}
+
+// release is a chapter number within a volume and the index of its best
+// chapter so far.
+type release struct {
+	volume string
+	best   int
+}
+
+// findRelease returns the release among rs that a chapter in volume belongs
+// to: the one with the same volume, else one without a volume. A chapter
+// without a volume joins the first release.
+func findRelease(rs []*release, volume string) *release {
+	if len(rs) == 0 {
+		return nil
+	}
+	if volume == "" {
+		return rs[0]
+	}
+	var unset *release
+	for _, r := range rs {
+		if r.volume == volume {
+			return r
+		}
+		if r.volume == "" && unset == nil {
+			unset = r
+		}
+	}
+	return unset
+}
+
+func unavailableReason(c Chapter) string {
+	if c.Id == nil {
+		return "missing id"
+	}
+	if c.Attributes == nil {
+		return ""
+	}
+	if c.Attributes.ExternalUrl != nil && *c.Attributes.ExternalUrl != "" {
+		return "external"
+	}
+	if c.Attributes.IsUnavailable != nil && *c.Attributes.IsUnavailable {
+		return "unavailable"
+	}
+	return ""
+}
+
+func chapterPages(c Chapter) int {
+	if c.Attributes == nil || c.Attributes.Pages == nil {
+		return 0
+	}
+	return *c.Attributes.Pages
+}
+
+func newManifestChapter(c Chapter) *ManifestChapter {
+	e := &ManifestChapter{Id: *c.Id}
+	if a := c.Attributes; a != nil {
+		if a.Volume != nil {
+			e.Volume = *a.Volume
+		}
+		if a.Chapter != nil {
+			e.Chapter = *a.Chapter
+		}
+		if a.TranslatedLanguage != nil {
+			e.Language = *a.TranslatedLanguage
+		}
+	}
+	if c.Relationships != nil {
+		for _, rel := range *c.Relationships {
+			if relationshipType(rel) == RelationshipScanlationGroup && rel.Id != nil {
+				e.Groups = append(e.Groups, rel.Id.String())
+			}
+		}
+	}
+	e.Dir = chapterDirName(e)
+	return e
+}
+
+// chapterDirName is "Vol.3 Ch.12.5", "Ch.7" or "Oneshot 1a2b3c4d".
+func chapterDirName(e *ManifestChapter) string {
+	var parts []string
+	if e.Volume != "" {
+		parts = append(parts, "Vol."+e.Volume)
+	}
+	if e.Chapter != "" {
+		parts = append(parts, "Ch."+e.Chapter)
+	} else {
+		parts = append(parts, "Oneshot "+e.Id.String()[:8])
+	}
+	name := strings.Join(parts, " ")
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// pagesExist reports whether every page recorded for a chapter is still on disk.
+func pagesExist(dir string, c *ManifestChapter) bool {
+	if len(c.Pages) == 0 {
+		return false
+	}
+	for _, p := range c.Pages {
+		info, err := os.Stat(filepath.Join(dir, c.Dir, p.File))
+		if err != nil || info.Size() != p.Bytes {
+			return false
+		}
+	}
+	return true
+}
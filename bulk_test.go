@@ -0,0 +1,199 @@
+package mangadex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func testChapter(id, volume, chapter, language string, pages int, groups ...uuid.UUID) Chapter {
+	uid := uuid.MustParse(id)
+	c := Chapter{Id: &uid, Attributes: &ChapterAttributes{
+		TranslatedLanguage: String(language),
+		Pages:              Int(pages),
+	}}
+	if volume != "" {
+		c.Attributes.Volume = String(volume)
+	}
+	if chapter != "" {
+		c.Attributes.Chapter = String(chapter)
+	}
+	rels := []Relationship{}
+	for _, g := range groups {
+		t := RelationshipScanlationGroup
+		rels = append(rels, Relationship{Id: &g, Type: &t})
+	}
+	c.Relationships = &rels
+	return c
+}
+
+func chapterIDs(chapters []Chapter) []string {
+	out := make([]string, len(chapters))
+	for i, c := range chapters {
+		out[i] = c.Id.String()[len(c.Id.String())-2:]
+	}
+	return out
+}
+
+func TestDedupe(t *testing.T) {
+	const p = "00000000-0000-0000-0000-0000000000"
+	good, bad := uuid.New(), uuid.New()
+	tests := []struct {
+		name     string
+		chapters []Chapter
+		want     []string
+	}{
+		{
+			name: "language preference",
+			chapters: []Chapter{
+				testChapter(p+"01", "1", "1", "fr", 20),
+				testChapter(p+"02", "1", "1", "en", 20),
+			},
+			want: []string{"02"},
+		},
+		{
+			name: "group preference",
+			chapters: []Chapter{
+				testChapter(p+"01", "1", "1", "en", 20, bad),
+				testChapter(p+"02", "1", "1", "en", 20, good),
+			},
+			want: []string{"02"},
+		},
+		{
+			name: "more pages",
+			chapters: []Chapter{
+				testChapter(p+"01", "1", "1", "en", 30),
+				testChapter(p+"02", "1", "1", "en", 20),
+			},
+			want: []string{"01"},
+		},
+		{
+			name: "numbers compare numerically",
+			chapters: []Chapter{
+				testChapter(p+"01", "1", "10", "en", 20),
+				testChapter(p+"02", "1", "10.0", "en", 20),
+				testChapter(p+"03", "01", "010", "en", 20),
+				testChapter(p+"04", "1", "10.5", "en", 20),
+			},
+			want: []string{"01", "04"},
+		},
+		{
+			name: "suffixes are distinct chapters",
+			chapters: []Chapter{
+				testChapter(p+"01", "2", "7", "en", 20),
+				testChapter(p+"02", "2", "7a", "en", 20),
+				testChapter(p+"03", "2", "7A", "en", 30),
+			},
+			want: []string{"01", "03"},
+		},
+		{
+			name: "numbering restarts per volume",
+			chapters: []Chapter{
+				testChapter(p+"01", "1", "1", "en", 20),
+				testChapter(p+"02", "2", "1", "en", 20),
+				testChapter(p+"03", "02", "1", "en", 20),
+			},
+			want: []string{"01", "02"},
+		},
+		{
+			name: "missing volume merges with the tagged release",
+			chapters: []Chapter{
+				testChapter(p+"01", "2", "12", "en", 20),
+				testChapter(p+"02", "", "12", "en", 30),
+				testChapter(p+"03", "none", "12", "en", 20),
+			},
+			want: []string{"02"},
+		},
+		{
+			name: "missing volume first",
+			chapters: []Chapter{
+				testChapter(p+"01", "", "1", "en", 20),
+				testChapter(p+"02", "1", "1", "en", 30),
+				testChapter(p+"03", "2", "1", "en", 20),
+				testChapter(p+"04", "", "1", "en", 20),
+			},
+			want: []string{"02", "03"},
+		},
+		{
+			name: "oneshots are kept",
+			chapters: []Chapter{
+				testChapter(p+"01", "", "", "en", 20),
+				testChapter(p+"02", "", "", "en", 20),
+			},
+			want: []string{"01", "02"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &BulkDownloader{Languages: []string{"en", "fr"}, GroupPreference: []uuid.UUID{good}}
+			if got := chapterIDs(b.Dedupe(tt.chapters)); !slices.Equal(got, tt.want) {
+				t.Fatalf("Dedupe = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBulkDownloadResumes(t *testing.T) {
+	f := newFakeAtHome(t)
+	f.Data["1-aa.png"] = []byte("page one")
+	f.Data["2-bb.png"] = []byte("page two")
+	const p = "00000000-0000-0000-0000-0000000000"
+	feed := []Chapter{
+		testChapter(p+"01", "1", "1", "en", 2),
+		testChapter(p+"02", "1", "2", "en", 2),
+	}
+	f.Mux.HandleFunc("GET /manga/{id}/feed", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ChapterList{Data: &feed, Total: Int(len(feed))})
+	})
+
+	r := f.resolver(t, false)
+	b := NewBulkDownloader(r.Client, "en")
+	b.Downloader.Resolver = r
+	var resumed []bool
+	b.OnChapter = func(p BulkProgress) { resumed = append(resumed, p.Resumed) }
+	ctx := context.Background()
+	dir := t.TempDir()
+	manga := uuid.New()
+
+	run := func(want ...bool) *Manifest {
+		t.Helper()
+		resumed = nil
+		m, err := b.Download(ctx, manga, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(resumed, want) {
+			t.Fatalf("resumed = %v, want %v", resumed, want)
+		}
+		return m
+	}
+
+	run(false, false)
+	m := run(true, true)
+	if len(m.Chapters) != 2 || !m.Chapters[p+"01"].Complete {
+		t.Fatalf("manifest = %+v", m.Chapters)
+	}
+
+	// a page missing on disk downloads its chapter again
+	entry := m.Chapters[p+"01"]
+	if err := os.Remove(filepath.Join(dir, entry.Dir, entry.Pages[0].File)); err != nil {
+		t.Fatal(err)
+	}
+	run(false, true)
+
+	// the manifest is picked up by a new downloader
+	loaded, err := LoadManifest(filepath.Join(dir, DefaultManifestName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.MangaID != manga || len(loaded.Chapters[p+"02"].Pages) != 2 {
+		t.Fatalf("saved manifest = %+v", loaded)
+	}
+}
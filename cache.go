@@ -15,6 +15,22 @@ type Cache interface {
 	Set(key string, value interface{})
 }
 
+type noCacheKey struct{}
+
+// NoCache returns a context under which the cached client skips stored
+// responses and goes to the API, storing what it gets for later calls with
+// the same arguments under any context. Use it when the cached response is
+// known to be stale.
+func NoCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+// cacheSkipped reports whether ctx came from NoCache.
+func cacheSkipped(ctx context.Context) bool {
+	skip, _ := ctx.Value(noCacheKey{}).(bool)
+	return skip
+}
+
 // MemCache is an in-memory implementation of Cache.
 type MemCache struct{ sync.Map }
 
@@ -29,37 +29,40 @@ type Cached{{.IfaceName}} struct {
 	cache  Cache
 }
 
-/*
-*/
+// NewCached{{.IfaceName}} constructs a new Cached{{.IfaceName}}.
 func NewCached{{.IfaceName}}(client {{.IfaceName}}, cache Cache) *Cached{{.IfaceName}} {
 	return &Cached{{.IfaceName}}{client: client, cache: cache}
 }
 
 // generateKey creates a cache key from the method name and argument values.
-func generateKey(method string, args ...interface{}) string {
+// It reports false when an argument cannot be encoded, such as a request
+// editor, which may change the request in ways the key cannot capture; such
+// calls must bypass the cache.
+func generateKey(method string, args ...interface{}) (string, bool) {
 	b, err := json.Marshal(args)
 	if err != nil {
-		// Handle error: perhaps log it and return a non-cacheable key or panic
-		return fmt.Sprintf("%s:json_marshal_error:%s", method, err.Error())
+		return "", false
 	}
 
 	hasher := sha256.New()
 	hasher.Write(b)
 	hash := hex.EncodeToString(hasher.Sum(nil))
 
-	return fmt.Sprintf("%s:%s", method, hash)
+	return fmt.Sprintf("%s:%s", method, hash), true
 }
 
 {{range .Methods}}
 // {{.Name}} applies caching before delegating to the underlying client.
 func (c *Cached{{$.IfaceName}}) {{.Name}}({{.ParamDecls}}) ({{.ReturnType}}, error) {
 	// Build cache key
-	key := generateKey("{{.Name}}"{{- range .ArgNames}}, {{.}}{{- end }})
-	if v, ok := c.cache.Get(key); ok {
-		output := {{.VarType}}{}
-		err := json.Unmarshal(v, &output)
-		if err == nil{
-			return {{if .IsPointer}}&{{end}}output, nil
+	key, cacheable := generateKey("{{.Name}}"{{- range .ArgNames}}, {{.}}{{- end }})
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := {{.VarType}}{}
+			err := json.Unmarshal(v, &output)
+			if err == nil{
+				return {{if .IsPointer}}&{{end}}output, nil
+			}
 		}
 	}
 
@@ -68,7 +71,9 @@ func (c *Cached{{$.IfaceName}}) {{.Name}}({{.ParamDecls}}) ({{.ReturnType}}, err
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 {{end}}
@@ -166,7 +171,11 @@ func main() {
 					}
 					for _, n := range param.Names {
 						decls = append(decls, fmt.Sprintf("%s %s", n.Name, typ))
-						argNames = append(argNames, n.Name)
+						// the context carries no request data, only
+						// deadlines and values, so it stays out of the key
+						if typ != "context.Context" {
+							argNames = append(argNames, n.Name)
+						}
 						if isVar {
 							callArgs = append(callArgs, n.Name+"...")
 						} else {
@@ -0,0 +1,80 @@
+// Package chapters parses MangaDex volume and chapter numbers and builds
+// reading orders from aggregate and feed responses.
+package chapters
+
+import (
+	"strings"
+
+	"github.com/Seann-Moser/mangadex/internal/chapternum"
+)
+
+// Number is a parsed volume or chapter number such as "10", "10.5", "7a"
+// or "Extra". MangaDex stores both as free-form strings and uses null (or
+// "none" in aggregates) for oneshots and chapters without a volume.
+type Number struct {
+	Raw string
+
+	// Value is the leading numeric part, valid when Numeric is true.
+	Value   float64
+	Numeric bool
+
+	// Suffix is whatever follows the numeric part, lower-cased ("a" in "7a").
+	Suffix string
+
+	// None is set for null, empty and "none" numbers.
+	None bool
+}
+
+// Parse parses a volume or chapter number.
+func Parse(s string) Number {
+	p := chapternum.Split(s)
+	return Number{Raw: s, Value: p.Value, Numeric: p.Numeric, Suffix: p.Suffix, None: p.None}
+}
+
+// ParsePtr parses a number from an optional API field.
+func ParsePtr(s *string) Number {
+	if s == nil {
+		return Number{None: true}
+	}
+	return Parse(*s)
+}
+
+// Compare orders numbers ascending: numeric values first (by value, then
+// suffix), then non-numeric values alphabetically, then None.
+func Compare(a, b Number) int {
+	switch {
+	case a.None && b.None:
+		return 0
+	case a.None:
+		return 1
+	case b.None:
+		return -1
+	case a.Numeric && !b.Numeric:
+		return -1
+	case !a.Numeric && b.Numeric:
+		return 1
+	case a.Numeric && a.Value != b.Value:
+		if a.Value < b.Value {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a.Suffix, b.Suffix)
+}
+
+// Key is a canonical form of n, so that "10", "10.0" and "010" share a key.
+func (n Number) Key() string {
+	return chapternum.Parts{Value: n.Value, Numeric: n.Numeric, Suffix: n.Suffix, None: n.None}.Key()
+}
+
+// Whole is the integer part of a numeric value, e.g. 10 for "10.5".
+func (n Number) Whole() int {
+	return int(n.Value)
+}
+
+func (n Number) String() string {
+	if n.None {
+		return "none"
+	}
+	return strings.TrimSpace(n.Raw)
+}
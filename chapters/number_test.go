@@ -0,0 +1,70 @@
+package chapters
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Number
+		key  string
+	}{
+		{"10", Number{Raw: "10", Value: 10, Numeric: true}, "10"},
+		{"010", Number{Raw: "010", Value: 10, Numeric: true}, "10"},
+		{"10.0", Number{Raw: "10.0", Value: 10, Numeric: true}, "10"},
+		{"10.5", Number{Raw: "10.5", Value: 10.5, Numeric: true}, "10.5"},
+		{" 7A ", Number{Raw: " 7A ", Value: 7, Numeric: true, Suffix: "a"}, "7a"},
+		{"7.a", Number{Raw: "7.a", Value: 7, Numeric: true, Suffix: "a"}, "7a"},
+		{"Extra", Number{Raw: "Extra", Suffix: "extra"}, "extra"},
+		{".5", Number{Raw: ".5", Suffix: ".5"}, ".5"},
+		{"", Number{None: true}, "none"},
+		{"none", Number{Raw: "none", None: true}, "none"},
+		{"NULL", Number{Raw: "NULL", None: true}, "none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := Parse(tt.in)
+			if tt.in == "" {
+				tt.want.Raw = ""
+			}
+			if got != tt.want {
+				t.Fatalf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+			if got.Key() != tt.key {
+				t.Fatalf("Key = %q, want %q", got.Key(), tt.key)
+			}
+		})
+	}
+
+	if n := ParsePtr(nil); !n.None {
+		t.Fatalf("ParsePtr(nil) = %+v", n)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1", "2", -1},
+		{"10", "9", 1},
+		{"10", "10.0", 0},
+		{"10", "10.5", -1},
+		{"7", "7a", -1},
+		{"7a", "7b", -1},
+		{"100", "Extra", -1},
+		{"Extra", "Omake", -1},
+		{"Extra", "none", -1},
+		{"1", "", -1},
+		{"none", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			if got := Compare(Parse(tt.a), Parse(tt.b)); got != tt.want {
+				t.Fatalf("Compare = %d, want %d", got, tt.want)
+			}
+			if got := Compare(Parse(tt.b), Parse(tt.a)); got != -tt.want {
+				t.Fatalf("reversed Compare = %d, want %d", got, -tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package chapters
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/Seann-Moser/mangadex"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+var ErrNotFound = errors.New("chapter not in reading order")
+
+// Entry is a chapter release within a ReadingOrder.
+type Entry struct {
+	Id       openapi_types.UUID
+	Volume   Number
+	Chapter  Number
+	Language string
+
+	// Others holds further releases of the same chapter listed by the
+	// aggregate endpoint.
+	Others []openapi_types.UUID
+
+	PublishAt time.Time
+}
+
+// Gap is a run of missing whole chapter numbers between two entries.
+type Gap struct {
+	After  Entry
+	Before Entry
+
+	// Missing is the number of whole chapters absent between After and Before.
+	Missing int
+}
+
+// ReadingOrder is a sorted list of chapter releases.
+type ReadingOrder struct {
+	Entries []Entry
+
+	// ResetOnNewVolume mirrors MangaAttributes.ChapterNumbersResetOnNewVolume;
+	// when set, chapters are ordered within their volume first.
+	ResetOnNewVolume bool
+}
+
+// FromAggregate builds a reading order from a manga aggregate response.
+// Aggregates are already filtered by language, so entries carry none.
+func FromAggregate(resp *mangadex.GetMangaAggregateResponse, resetOnNewVolume bool) (*ReadingOrder, error) {
+	if resp == nil || resp.JSON200 == nil {
+		return nil, errors.New("empty aggregate response")
+	}
+	o := &ReadingOrder{ResetOnNewVolume: resetOnNewVolume}
+	if resp.JSON200.Volumes == nil {
+		return o, nil
+	}
+	for volKey, vol := range *resp.JSON200.Volumes {
+		if vol.Chapters == nil {
+			continue
+		}
+		for chKey, ch := range *vol.Chapters {
+			if ch.Id == nil {
+				continue
+			}
+			e := Entry{
+				Id:      *ch.Id,
+				Volume:  Parse(volKey),
+				Chapter: Parse(chKey),
+			}
+			if ch.Others != nil {
+				e.Others = *ch.Others
+			}
+			o.Entries = append(o.Entries, e)
+		}
+	}
+	o.Sort()
+	return o, nil
+}
+
+// FromChapters builds a reading order from feed or chapter list results.
+// Every release is kept; use Duplicates or Next to pick between them.
+func FromChapters(chapters []mangadex.Chapter, resetOnNewVolume bool) *ReadingOrder {
+	o := &ReadingOrder{ResetOnNewVolume: resetOnNewVolume}
+	for _, c := range chapters {
+		if c.Id == nil {
+			continue
+		}
+		e := Entry{Id: *c.Id, Volume: Number{None: true}, Chapter: Number{None: true}}
+		if a := c.Attributes; a != nil {
+			e.Volume = ParsePtr(a.Volume)
+			e.Chapter = ParsePtr(a.Chapter)
+			if a.TranslatedLanguage != nil {
+				e.Language = *a.TranslatedLanguage
+			}
+			if a.PublishAt != nil {
+				e.PublishAt, _ = time.Parse(time.RFC3339, *a.PublishAt)
+			}
+		}
+		o.Entries = append(o.Entries, e)
+	}
+	o.Sort()
+	return o
+}
+
+// Sort orders the entries. Chapters are ordered by number, with the volume
+// breaking ties; when ResetOnNewVolume is set the volume is compared first.
+// Non-numeric chapters such as "Extra" follow numeric ones, oneshots come
+// last, and releases of the same chapter are ordered by language then
+// publish time.
+func (o *ReadingOrder) Sort() {
+	sort.SliceStable(o.Entries, func(i, j int) bool {
+		return o.compare(o.Entries[i], o.Entries[j]) < 0
+	})
+}
+
+func (o *ReadingOrder) compare(a, b Entry) int {
+	if c := o.comparePosition(a, b); c != 0 {
+		return c
+	}
+	if a.Language != b.Language {
+		if a.Language < b.Language {
+			return -1
+		}
+		return 1
+	}
+	return a.PublishAt.Compare(b.PublishAt)
+}
+
+// comparePosition compares where two entries sit in the story, ignoring
+// which release they are.
+func (o *ReadingOrder) comparePosition(a, b Entry) int {
+	if o.ResetOnNewVolume {
+		if c := Compare(a.Volume, b.Volume); c != 0 {
+			return c
+		}
+		return Compare(a.Chapter, b.Chapter)
+	}
+	if c := Compare(a.Chapter, b.Chapter); c != 0 {
+		return c
+	}
+	return Compare(a.Volume, b.Volume)
+}
+
+// key identifies a story position, shared by all releases of a chapter.
+func (o *ReadingOrder) key(e Entry) string {
+	if e.Chapter.None {
+		return "id:" + e.Id.String()
+	}
+	if o.ResetOnNewVolume {
+		return e.Volume.Key() + "/" + e.Chapter.Key()
+	}
+	return e.Chapter.Key()
+}
+
+// Duplicates returns groups of entries that are releases of the same
+// chapter, e.g. by different groups or in different languages.
+func (o *ReadingOrder) Duplicates() [][]Entry {
+	groups := map[string][]Entry{}
+	var keys []string
+	for _, e := range o.Entries {
+		k := o.key(e)
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], e)
+	}
+	var out [][]Entry
+	for _, k := range keys {
+		if len(groups[k]) > 1 {
+			out = append(out, groups[k])
+		}
+	}
+	return out
+}
+
+// Gaps returns places where whole chapter numbers are skipped, e.g. 4 -> 7.
+// Only numeric chapters are considered and, when ResetOnNewVolume is set,
+// only within a volume.
+func (o *ReadingOrder) Gaps() []Gap {
+	var (
+		out  []Gap
+		prev *Entry
+	)
+	for i := range o.Entries {
+		e := o.Entries[i]
+		if !e.Chapter.Numeric {
+			continue
+		}
+		if prev != nil && (!o.ResetOnNewVolume || Compare(prev.Volume, e.Volume) == 0) {
+			if missing := e.Chapter.Whole() - prev.Chapter.Whole() - 1; missing > 0 {
+				out = append(out, Gap{After: *prev, Before: e, Missing: missing})
+			}
+		}
+		prev = &o.Entries[i]
+	}
+	return out
+}
+
+// Find returns the entry for a chapter id, including ids listed in Others.
+func (o *ReadingOrder) Find(id openapi_types.UUID) (int, bool) {
+	for i, e := range o.Entries {
+		if e.Id == id {
+			return i, true
+		}
+		for _, other := range e.Others {
+			if other == id {
+				return i, true
+			}
+		}
+	}
+	return -1, false
+}
+
+// Next returns the first release after id, in story order, that is in
+// language. An empty language, or entries without one, match any language.
+func (o *ReadingOrder) Next(id openapi_types.UUID, language string) (*Entry, error) {
+	i, ok := o.Find(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	cur := o.Entries[i]
+	for j := i + 1; j < len(o.Entries); j++ {
+		e := o.Entries[j]
+		if o.comparePosition(cur, e) < 0 && matchesLanguage(e, language) {
+			return &e, nil
+		}
+	}
+	return nil, nil
+}
+
+// Previous returns the last release before id, in story order, that is in
+// language.
+func (o *ReadingOrder) Previous(id openapi_types.UUID, language string) (*Entry, error) {
+	i, ok := o.Find(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	cur := o.Entries[i]
+	for j := i - 1; j >= 0; j-- {
+		e := o.Entries[j]
+		if o.comparePosition(e, cur) < 0 && matchesLanguage(e, language) {
+			// prefer the earliest release of that position in the language
+			for j > 0 && o.comparePosition(o.Entries[j-1], e) == 0 {
+				if matchesLanguage(o.Entries[j-1], language) {
+					e = o.Entries[j-1]
+				}
+				j--
+			}
+			return &e, nil
+		}
+	}
+	return nil, nil
+}
+
+// Filter returns a new reading order holding only entries in language.
+func (o *ReadingOrder) Filter(language string) *ReadingOrder {
+	out := &ReadingOrder{ResetOnNewVolume: o.ResetOnNewVolume}
+	for _, e := range o.Entries {
+		if matchesLanguage(e, language) {
+			out.Entries = append(out.Entries, e)
+		}
+	}
+	return out
+}
+
+func matchesLanguage(e Entry, language string) bool {
+	return language == "" || e.Language == "" ||
+		mangadex.NormalizeLanguage(e.Language) == mangadex.NormalizeLanguage(language)
+}
@@ -0,0 +1,201 @@
+package chapters
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Seann-Moser/mangadex"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// release describes a feed chapter; an empty volume or chapter is left out
+// of the attributes, and publishAt is in hours after a fixed base time.
+type release struct {
+	name      string
+	volume    string
+	chapter   string
+	language  string
+	publishAt int
+}
+
+// releases builds feed chapters and returns them with their ids by name.
+func releases(rs ...release) ([]mangadex.Chapter, map[string]openapi_types.UUID, map[openapi_types.UUID]string) {
+	var out []mangadex.Chapter
+	ids := map[string]openapi_types.UUID{}
+	names := map[openapi_types.UUID]string{}
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, r := range rs {
+		id := uuid.New()
+		ids[r.name], names[id] = id, r.name
+		a := &mangadex.ChapterAttributes{
+			TranslatedLanguage: mangadex.String(r.language),
+			PublishAt:          mangadex.String(base.Add(time.Duration(r.publishAt) * time.Hour).Format(time.RFC3339)),
+		}
+		if r.volume != "" {
+			a.Volume = mangadex.String(r.volume)
+		}
+		if r.chapter != "" {
+			a.Chapter = mangadex.String(r.chapter)
+		}
+		out = append(out, mangadex.Chapter{Id: &id, Attributes: a})
+	}
+	return out, ids, names
+}
+
+func entryNames(entries []Entry, names map[openapi_types.UUID]string) []string {
+	out := make([]string, len(entries))
+	for i, e := range entries {
+		out[i] = names[e.Id]
+	}
+	return out
+}
+
+func TestReadingOrderSort(t *testing.T) {
+	feed, _, names := releases(
+		release{"oneshot", "", "", "en", 0},
+		release{"extra", "2", "Extra", "en", 0},
+		release{"v2c1", "2", "1", "en", 0},
+		release{"v1c2-fr", "1", "2", "fr", 0},
+		release{"v1c2-en-late", "1", "2", "en", 5},
+		release{"v1c2-en", "1", "2", "en", 1},
+		release{"v1c1", "1", "1", "en", 0},
+		release{"v2c10", "2", "10", "en", 0},
+	)
+
+	tests := []struct {
+		reset bool
+		want  []string
+	}{
+		{false, []string{"v1c1", "v2c1", "v1c2-en", "v1c2-en-late", "v1c2-fr", "v2c10", "extra", "oneshot"}},
+		{true, []string{"v1c1", "v1c2-en", "v1c2-en-late", "v1c2-fr", "v2c1", "v2c10", "extra", "oneshot"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("reset=%v", tt.reset), func(t *testing.T) {
+			o := FromChapters(feed, tt.reset)
+			if got := entryNames(o.Entries, names); !slices.Equal(got, tt.want) {
+				t.Fatalf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadingOrderGaps(t *testing.T) {
+	feed, _, names := releases(
+		release{"v1c1", "1", "1", "en", 0},
+		release{"v1c2", "1", "2", "en", 0},
+		release{"v1c2.5", "1", "2.5", "en", 0},
+		release{"v1c5", "1", "5", "en", 0},
+		release{"v2c1", "2", "1", "en", 0},
+		release{"v2c3", "2", "3", "en", 0},
+		release{"extra", "2", "Extra", "en", 0},
+	)
+
+	tests := []struct {
+		reset bool
+		want  []string
+	}{
+		// sorted by number alone the volumes interleave, so 5 -> 1 is not a gap
+		{false, []string{"v2c3-v1c5:1"}},
+		{true, []string{"v1c2.5-v1c5:2", "v2c1-v2c3:1"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("reset=%v", tt.reset), func(t *testing.T) {
+			var got []string
+			for _, g := range FromChapters(feed, tt.reset).Gaps() {
+				got = append(got, fmt.Sprintf("%s-%s:%d", names[g.After.Id], names[g.Before.Id], g.Missing))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("gaps = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadingOrderNavigation(t *testing.T) {
+	feed, ids, names := releases(
+		release{"c1-en", "1", "1", "en", 0},
+		release{"c1-fr", "1", "1", "fr", 0},
+		release{"c2-fr", "1", "2", "fr", 0},
+		release{"c3-en", "1", "3", "en", 0},
+		release{"c3-en-b", "1", "3", "en", 1},
+		release{"c4-fr", "1", "4", "fr", 0},
+	)
+	o := FromChapters(feed, false)
+
+	tests := []struct {
+		name     string
+		from     string
+		language string
+		next     string
+		previous string
+	}{
+		{"same language skips missing", "c1-en", "en", "c3-en", ""},
+		{"any language", "c1-en", "", "c2-fr", ""},
+		{"other release of same chapter", "c1-fr", "fr", "c2-fr", ""},
+		{"earliest previous release", "c4-fr", "en", "", "c3-en"},
+		{"previous in language", "c3-en-b", "fr", "c4-fr", "c2-fr"},
+		{"last chapter", "c4-fr", "fr", "", "c2-fr"},
+		{"region subtag", "c2-fr", "FR", "c4-fr", "c1-fr"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, err := o.Next(ids[tt.from], tt.language)
+			if err != nil {
+				t.Fatal(err)
+			}
+			previous, err := o.Previous(ids[tt.from], tt.language)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := nameOf(next, names); got != tt.next {
+				t.Errorf("Next = %q, want %q", got, tt.next)
+			}
+			if got := nameOf(previous, names); got != tt.previous {
+				t.Errorf("Previous = %q, want %q", got, tt.previous)
+			}
+		})
+	}
+
+	if _, err := o.Next(uuid.New(), "en"); err != ErrNotFound {
+		t.Fatalf("unknown id: err = %v", err)
+	}
+}
+
+func TestReadingOrderDuplicates(t *testing.T) {
+	feed, _, names := releases(
+		release{"v1c1-en", "1", "1", "en", 0},
+		release{"v1c1-fr", "1", "1.0", "fr", 0},
+		release{"v2c1", "2", "1", "en", 0},
+		release{"oneshot-a", "", "", "en", 0},
+		release{"oneshot-b", "", "", "en", 0},
+	)
+
+	tests := []struct {
+		reset bool
+		want  [][]string
+	}{
+		{false, [][]string{{"v1c1-en", "v1c1-fr", "v2c1"}}},
+		{true, [][]string{{"v1c1-en", "v1c1-fr"}}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("reset=%v", tt.reset), func(t *testing.T) {
+			var got [][]string
+			for _, group := range FromChapters(feed, tt.reset).Duplicates() {
+				got = append(got, entryNames(group, names))
+			}
+			if !slices.EqualFunc(got, tt.want, slices.Equal[[]string]) {
+				t.Fatalf("duplicates = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func nameOf(e *Entry, names map[openapi_types.UUID]string) string {
+	if e == nil {
+		return ""
+	}
+	return names[e.Id]
+}
@@ -22,29 +22,33 @@ func NewCachedClientWithResponsesInterface(client ClientWithResponsesInterface,
 }
 
 // generateKey creates a cache key from the method name and argument values.
-func generateKey(method string, args ...interface{}) string {
+// It reports false when an argument cannot be encoded, such as a request
+// editor, which may change the request in ways the key cannot capture; such
+// calls must bypass the cache.
+func generateKey(method string, args ...interface{}) (string, bool) {
 	b, err := json.Marshal(args)
 	if err != nil {
-		// Handle error: perhaps log it and return a non-cacheable key or panic
-		return fmt.Sprintf("%s:json_marshal_error:%s", method, err.Error())
+		return "", false
 	}
 
 	hasher := sha256.New()
 	hasher.Write(b)
 	hash := hex.EncodeToString(hasher.Sum(nil))
 
-	return fmt.Sprintf("%s:%s", method, hash)
+	return fmt.Sprintf("%s:%s", method, hash), true
 }
 
 // GetAtHomeServerChapterIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetAtHomeServerChapterIdWithResponse(ctx context.Context, chapterId openapi_types.UUID, params *GetAtHomeServerChapterIdParams, reqEditors ...RequestEditorFn) (*GetAtHomeServerChapterIdResponse, error) {
 	// Build cache key
-	key := generateKey("GetAtHomeServerChapterIdWithResponse", ctx, chapterId, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetAtHomeServerChapterIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetAtHomeServerChapterIdWithResponse", chapterId, params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetAtHomeServerChapterIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -53,19 +57,23 @@ func (c *CachedClientWithResponsesInterface) GetAtHomeServerChapterIdWithRespons
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetAuthCheckWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetAuthCheckWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetAuthCheckResponse, error) {
 	// Build cache key
-	key := generateKey("GetAuthCheckWithResponse", ctx, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetAuthCheckResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetAuthCheckWithResponse", reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetAuthCheckResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -74,19 +82,23 @@ func (c *CachedClientWithResponsesInterface) GetAuthCheckWithResponse(ctx contex
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostAuthLoginWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostAuthLoginWithBodyWithResponse(ctx context.Context, params *PostAuthLoginParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostAuthLoginResponse, error) {
 	// Build cache key
-	key := generateKey("PostAuthLoginWithBodyWithResponse", ctx, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostAuthLoginResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostAuthLoginWithBodyWithResponse", params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostAuthLoginResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -95,19 +107,23 @@ func (c *CachedClientWithResponsesInterface) PostAuthLoginWithBodyWithResponse(c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostAuthLoginWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostAuthLoginWithResponse(ctx context.Context, params *PostAuthLoginParams, body PostAuthLoginJSONRequestBody, reqEditors ...RequestEditorFn) (*PostAuthLoginResponse, error) {
 	// Build cache key
-	key := generateKey("PostAuthLoginWithResponse", ctx, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostAuthLoginResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostAuthLoginWithResponse", params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostAuthLoginResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -116,19 +132,23 @@ func (c *CachedClientWithResponsesInterface) PostAuthLoginWithResponse(ctx conte
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostAuthLogoutWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostAuthLogoutWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*PostAuthLogoutResponse, error) {
 	// Build cache key
-	key := generateKey("PostAuthLogoutWithResponse", ctx, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostAuthLogoutResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostAuthLogoutWithResponse", reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostAuthLogoutResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -137,19 +157,23 @@ func (c *CachedClientWithResponsesInterface) PostAuthLogoutWithResponse(ctx cont
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostAuthRefreshWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostAuthRefreshWithBodyWithResponse(ctx context.Context, params *PostAuthRefreshParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostAuthRefreshResponse, error) {
 	// Build cache key
-	key := generateKey("PostAuthRefreshWithBodyWithResponse", ctx, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostAuthRefreshResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostAuthRefreshWithBodyWithResponse", params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostAuthRefreshResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -158,19 +182,23 @@ func (c *CachedClientWithResponsesInterface) PostAuthRefreshWithBodyWithResponse
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostAuthRefreshWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostAuthRefreshWithResponse(ctx context.Context, params *PostAuthRefreshParams, body PostAuthRefreshJSONRequestBody, reqEditors ...RequestEditorFn) (*PostAuthRefreshResponse, error) {
 	// Build cache key
-	key := generateKey("PostAuthRefreshWithResponse", ctx, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostAuthRefreshResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostAuthRefreshWithResponse", params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostAuthRefreshResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -179,19 +207,23 @@ func (c *CachedClientWithResponsesInterface) PostAuthRefreshWithResponse(ctx con
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetAuthorWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetAuthorWithResponse(ctx context.Context, params *GetAuthorParams, reqEditors ...RequestEditorFn) (*GetAuthorResponse, error) {
 	// Build cache key
-	key := generateKey("GetAuthorWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetAuthorResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetAuthorWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetAuthorResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -200,19 +232,23 @@ func (c *CachedClientWithResponsesInterface) GetAuthorWithResponse(ctx context.C
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostAuthorWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostAuthorWithBodyWithResponse(ctx context.Context, params *PostAuthorParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostAuthorResponse, error) {
 	// Build cache key
-	key := generateKey("PostAuthorWithBodyWithResponse", ctx, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostAuthorResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostAuthorWithBodyWithResponse", params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostAuthorResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -221,19 +257,23 @@ func (c *CachedClientWithResponsesInterface) PostAuthorWithBodyWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostAuthorWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostAuthorWithResponse(ctx context.Context, params *PostAuthorParams, body PostAuthorJSONRequestBody, reqEditors ...RequestEditorFn) (*PostAuthorResponse, error) {
 	// Build cache key
-	key := generateKey("PostAuthorWithResponse", ctx, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostAuthorResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostAuthorWithResponse", params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostAuthorResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -242,19 +282,23 @@ func (c *CachedClientWithResponsesInterface) PostAuthorWithResponse(ctx context.
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // DeleteAuthorIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) DeleteAuthorIdWithResponse(ctx context.Context, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteAuthorIdResponse, error) {
 	// Build cache key
-	key := generateKey("DeleteAuthorIdWithResponse", ctx, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := DeleteAuthorIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("DeleteAuthorIdWithResponse", id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := DeleteAuthorIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -263,19 +307,23 @@ func (c *CachedClientWithResponsesInterface) DeleteAuthorIdWithResponse(ctx cont
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetAuthorIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetAuthorIdWithResponse(ctx context.Context, id openapi_types.UUID, params *GetAuthorIdParams, reqEditors ...RequestEditorFn) (*GetAuthorIdResponse, error) {
 	// Build cache key
-	key := generateKey("GetAuthorIdWithResponse", ctx, id, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetAuthorIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetAuthorIdWithResponse", id, params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetAuthorIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -284,19 +332,23 @@ func (c *CachedClientWithResponsesInterface) GetAuthorIdWithResponse(ctx context
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PutAuthorIdWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PutAuthorIdWithBodyWithResponse(ctx context.Context, id openapi_types.UUID, params *PutAuthorIdParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutAuthorIdResponse, error) {
 	// Build cache key
-	key := generateKey("PutAuthorIdWithBodyWithResponse", ctx, id, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PutAuthorIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PutAuthorIdWithBodyWithResponse", id, params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PutAuthorIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -305,19 +357,23 @@ func (c *CachedClientWithResponsesInterface) PutAuthorIdWithBodyWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PutAuthorIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PutAuthorIdWithResponse(ctx context.Context, id openapi_types.UUID, params *PutAuthorIdParams, body PutAuthorIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PutAuthorIdResponse, error) {
 	// Build cache key
-	key := generateKey("PutAuthorIdWithResponse", ctx, id, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PutAuthorIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PutAuthorIdWithResponse", id, params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PutAuthorIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -326,19 +382,23 @@ func (c *CachedClientWithResponsesInterface) PutAuthorIdWithResponse(ctx context
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostCaptchaSolveWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostCaptchaSolveWithBodyWithResponse(ctx context.Context, params *PostCaptchaSolveParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostCaptchaSolveResponse, error) {
 	// Build cache key
-	key := generateKey("PostCaptchaSolveWithBodyWithResponse", ctx, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostCaptchaSolveResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostCaptchaSolveWithBodyWithResponse", params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostCaptchaSolveResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -347,19 +407,23 @@ func (c *CachedClientWithResponsesInterface) PostCaptchaSolveWithBodyWithRespons
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostCaptchaSolveWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostCaptchaSolveWithResponse(ctx context.Context, params *PostCaptchaSolveParams, body PostCaptchaSolveJSONRequestBody, reqEditors ...RequestEditorFn) (*PostCaptchaSolveResponse, error) {
 	// Build cache key
-	key := generateKey("PostCaptchaSolveWithResponse", ctx, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostCaptchaSolveResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostCaptchaSolveWithResponse", params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostCaptchaSolveResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -368,19 +432,23 @@ func (c *CachedClientWithResponsesInterface) PostCaptchaSolveWithResponse(ctx co
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetChapterWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetChapterWithResponse(ctx context.Context, params *GetChapterParams, reqEditors ...RequestEditorFn) (*GetChapterResponse, error) {
 	// Build cache key
-	key := generateKey("GetChapterWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetChapterResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetChapterWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetChapterResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -389,19 +457,23 @@ func (c *CachedClientWithResponsesInterface) GetChapterWithResponse(ctx context.
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // DeleteChapterIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) DeleteChapterIdWithResponse(ctx context.Context, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteChapterIdResponse, error) {
 	// Build cache key
-	key := generateKey("DeleteChapterIdWithResponse", ctx, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := DeleteChapterIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("DeleteChapterIdWithResponse", id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := DeleteChapterIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -410,19 +482,23 @@ func (c *CachedClientWithResponsesInterface) DeleteChapterIdWithResponse(ctx con
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetChapterIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetChapterIdWithResponse(ctx context.Context, id openapi_types.UUID, params *GetChapterIdParams, reqEditors ...RequestEditorFn) (*GetChapterIdResponse, error) {
 	// Build cache key
-	key := generateKey("GetChapterIdWithResponse", ctx, id, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetChapterIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetChapterIdWithResponse", id, params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetChapterIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -431,19 +507,23 @@ func (c *CachedClientWithResponsesInterface) GetChapterIdWithResponse(ctx contex
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PutChapterIdWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PutChapterIdWithBodyWithResponse(ctx context.Context, id openapi_types.UUID, params *PutChapterIdParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutChapterIdResponse, error) {
 	// Build cache key
-	key := generateKey("PutChapterIdWithBodyWithResponse", ctx, id, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PutChapterIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PutChapterIdWithBodyWithResponse", id, params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PutChapterIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -452,19 +532,23 @@ func (c *CachedClientWithResponsesInterface) PutChapterIdWithBodyWithResponse(ct
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PutChapterIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PutChapterIdWithResponse(ctx context.Context, id openapi_types.UUID, params *PutChapterIdParams, body PutChapterIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PutChapterIdResponse, error) {
 	// Build cache key
-	key := generateKey("PutChapterIdWithResponse", ctx, id, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PutChapterIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PutChapterIdWithResponse", id, params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PutChapterIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -473,19 +557,23 @@ func (c *CachedClientWithResponsesInterface) PutChapterIdWithResponse(ctx contex
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetListApiclientsWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetListApiclientsWithResponse(ctx context.Context, params *GetListApiclientsParams, reqEditors ...RequestEditorFn) (*GetListApiclientsResponse, error) {
 	// Build cache key
-	key := generateKey("GetListApiclientsWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetListApiclientsResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetListApiclientsWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetListApiclientsResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -494,19 +582,23 @@ func (c *CachedClientWithResponsesInterface) GetListApiclientsWithResponse(ctx c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostCreateApiclientWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostCreateApiclientWithBodyWithResponse(ctx context.Context, params *PostCreateApiclientParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostCreateApiclientResponse, error) {
 	// Build cache key
-	key := generateKey("PostCreateApiclientWithBodyWithResponse", ctx, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostCreateApiclientResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostCreateApiclientWithBodyWithResponse", params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostCreateApiclientResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -515,19 +607,23 @@ func (c *CachedClientWithResponsesInterface) PostCreateApiclientWithBodyWithResp
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostCreateApiclientWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostCreateApiclientWithResponse(ctx context.Context, params *PostCreateApiclientParams, body PostCreateApiclientJSONRequestBody, reqEditors ...RequestEditorFn) (*PostCreateApiclientResponse, error) {
 	// Build cache key
-	key := generateKey("PostCreateApiclientWithResponse", ctx, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostCreateApiclientResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostCreateApiclientWithResponse", params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostCreateApiclientResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -536,19 +632,23 @@ func (c *CachedClientWithResponsesInterface) PostCreateApiclientWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // DeleteApiclientWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) DeleteApiclientWithResponse(ctx context.Context, id openapi_types.UUID, params *DeleteApiclientParams, reqEditors ...RequestEditorFn) (*DeleteApiclientResponse, error) {
 	// Build cache key
-	key := generateKey("DeleteApiclientWithResponse", ctx, id, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := DeleteApiclientResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("DeleteApiclientWithResponse", id, params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := DeleteApiclientResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -557,19 +657,23 @@ func (c *CachedClientWithResponsesInterface) DeleteApiclientWithResponse(ctx con
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetApiclientWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetApiclientWithResponse(ctx context.Context, id openapi_types.UUID, params *GetApiclientParams, reqEditors ...RequestEditorFn) (*GetApiclientResponse, error) {
 	// Build cache key
-	key := generateKey("GetApiclientWithResponse", ctx, id, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetApiclientResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetApiclientWithResponse", id, params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetApiclientResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -578,19 +682,23 @@ func (c *CachedClientWithResponsesInterface) GetApiclientWithResponse(ctx contex
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostEditApiclientWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostEditApiclientWithBodyWithResponse(ctx context.Context, id openapi_types.UUID, params *PostEditApiclientParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostEditApiclientResponse, error) {
 	// Build cache key
-	key := generateKey("PostEditApiclientWithBodyWithResponse", ctx, id, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostEditApiclientResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostEditApiclientWithBodyWithResponse", id, params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostEditApiclientResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -599,19 +707,23 @@ func (c *CachedClientWithResponsesInterface) PostEditApiclientWithBodyWithRespon
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostEditApiclientWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostEditApiclientWithResponse(ctx context.Context, id openapi_types.UUID, params *PostEditApiclientParams, body PostEditApiclientJSONRequestBody, reqEditors ...RequestEditorFn) (*PostEditApiclientResponse, error) {
 	// Build cache key
-	key := generateKey("PostEditApiclientWithResponse", ctx, id, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostEditApiclientResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostEditApiclientWithResponse", id, params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostEditApiclientResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -620,19 +732,23 @@ func (c *CachedClientWithResponsesInterface) PostEditApiclientWithResponse(ctx c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetApiclientSecretWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetApiclientSecretWithResponse(ctx context.Context, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetApiclientSecretResponse, error) {
 	// Build cache key
-	key := generateKey("GetApiclientSecretWithResponse", ctx, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetApiclientSecretResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetApiclientSecretWithResponse", id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetApiclientSecretResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -641,19 +757,23 @@ func (c *CachedClientWithResponsesInterface) GetApiclientSecretWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostRegenerateApiclientSecretWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostRegenerateApiclientSecretWithBodyWithResponse(ctx context.Context, id openapi_types.UUID, params *PostRegenerateApiclientSecretParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostRegenerateApiclientSecretResponse, error) {
 	// Build cache key
-	key := generateKey("PostRegenerateApiclientSecretWithBodyWithResponse", ctx, id, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostRegenerateApiclientSecretResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostRegenerateApiclientSecretWithBodyWithResponse", id, params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostRegenerateApiclientSecretResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -662,19 +782,23 @@ func (c *CachedClientWithResponsesInterface) PostRegenerateApiclientSecretWithBo
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostRegenerateApiclientSecretWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostRegenerateApiclientSecretWithResponse(ctx context.Context, id openapi_types.UUID, params *PostRegenerateApiclientSecretParams, body PostRegenerateApiclientSecretJSONRequestBody, reqEditors ...RequestEditorFn) (*PostRegenerateApiclientSecretResponse, error) {
 	// Build cache key
-	key := generateKey("PostRegenerateApiclientSecretWithResponse", ctx, id, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostRegenerateApiclientSecretResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostRegenerateApiclientSecretWithResponse", id, params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostRegenerateApiclientSecretResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -683,19 +807,23 @@ func (c *CachedClientWithResponsesInterface) PostRegenerateApiclientSecretWithRe
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetCoverWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetCoverWithResponse(ctx context.Context, params *GetCoverParams, reqEditors ...RequestEditorFn) (*GetCoverResponse, error) {
 	// Build cache key
-	key := generateKey("GetCoverWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetCoverResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetCoverWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetCoverResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -704,19 +832,23 @@ func (c *CachedClientWithResponsesInterface) GetCoverWithResponse(ctx context.Co
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // DeleteCoverWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) DeleteCoverWithResponse(ctx context.Context, mangaOrCoverId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteCoverResponse, error) {
 	// Build cache key
-	key := generateKey("DeleteCoverWithResponse", ctx, mangaOrCoverId, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := DeleteCoverResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("DeleteCoverWithResponse", mangaOrCoverId, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := DeleteCoverResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -725,19 +857,23 @@ func (c *CachedClientWithResponsesInterface) DeleteCoverWithResponse(ctx context
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetCoverIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetCoverIdWithResponse(ctx context.Context, mangaOrCoverId openapi_types.UUID, params *GetCoverIdParams, reqEditors ...RequestEditorFn) (*GetCoverIdResponse, error) {
 	// Build cache key
-	key := generateKey("GetCoverIdWithResponse", ctx, mangaOrCoverId, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetCoverIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetCoverIdWithResponse", mangaOrCoverId, params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetCoverIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -746,19 +882,23 @@ func (c *CachedClientWithResponsesInterface) GetCoverIdWithResponse(ctx context.
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // UploadCoverWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) UploadCoverWithBodyWithResponse(ctx context.Context, mangaOrCoverId openapi_types.UUID, params *UploadCoverParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UploadCoverResponse, error) {
 	// Build cache key
-	key := generateKey("UploadCoverWithBodyWithResponse", ctx, mangaOrCoverId, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := UploadCoverResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("UploadCoverWithBodyWithResponse", mangaOrCoverId, params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := UploadCoverResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -767,19 +907,23 @@ func (c *CachedClientWithResponsesInterface) UploadCoverWithBodyWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // EditCoverWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) EditCoverWithBodyWithResponse(ctx context.Context, mangaOrCoverId openapi_types.UUID, params *EditCoverParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*EditCoverResponse, error) {
 	// Build cache key
-	key := generateKey("EditCoverWithBodyWithResponse", ctx, mangaOrCoverId, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := EditCoverResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("EditCoverWithBodyWithResponse", mangaOrCoverId, params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := EditCoverResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -788,19 +932,23 @@ func (c *CachedClientWithResponsesInterface) EditCoverWithBodyWithResponse(ctx c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // EditCoverWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) EditCoverWithResponse(ctx context.Context, mangaOrCoverId openapi_types.UUID, params *EditCoverParams, body EditCoverJSONRequestBody, reqEditors ...RequestEditorFn) (*EditCoverResponse, error) {
 	// Build cache key
-	key := generateKey("EditCoverWithResponse", ctx, mangaOrCoverId, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := EditCoverResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("EditCoverWithResponse", mangaOrCoverId, params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := EditCoverResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -809,19 +957,23 @@ func (c *CachedClientWithResponsesInterface) EditCoverWithResponse(ctx context.C
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // ForumsThreadCreateWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) ForumsThreadCreateWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*ForumsThreadCreateResponse, error) {
 	// Build cache key
-	key := generateKey("ForumsThreadCreateWithBodyWithResponse", ctx, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := ForumsThreadCreateResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("ForumsThreadCreateWithBodyWithResponse", contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := ForumsThreadCreateResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -830,19 +982,23 @@ func (c *CachedClientWithResponsesInterface) ForumsThreadCreateWithBodyWithRespo
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // ForumsThreadCreateWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) ForumsThreadCreateWithResponse(ctx context.Context, body ForumsThreadCreateJSONRequestBody, reqEditors ...RequestEditorFn) (*ForumsThreadCreateResponse, error) {
 	// Build cache key
-	key := generateKey("ForumsThreadCreateWithResponse", ctx, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := ForumsThreadCreateResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("ForumsThreadCreateWithResponse", body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := ForumsThreadCreateResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -851,19 +1007,23 @@ func (c *CachedClientWithResponsesInterface) ForumsThreadCreateWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetSearchGroupWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetSearchGroupWithResponse(ctx context.Context, params *GetSearchGroupParams, reqEditors ...RequestEditorFn) (*GetSearchGroupResponse, error) {
 	// Build cache key
-	key := generateKey("GetSearchGroupWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetSearchGroupResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetSearchGroupWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetSearchGroupResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -872,19 +1032,23 @@ func (c *CachedClientWithResponsesInterface) GetSearchGroupWithResponse(ctx cont
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostGroupWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostGroupWithBodyWithResponse(ctx context.Context, params *PostGroupParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostGroupResponse, error) {
 	// Build cache key
-	key := generateKey("PostGroupWithBodyWithResponse", ctx, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostGroupResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostGroupWithBodyWithResponse", params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostGroupResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -893,19 +1057,23 @@ func (c *CachedClientWithResponsesInterface) PostGroupWithBodyWithResponse(ctx c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostGroupWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostGroupWithResponse(ctx context.Context, params *PostGroupParams, body PostGroupJSONRequestBody, reqEditors ...RequestEditorFn) (*PostGroupResponse, error) {
 	// Build cache key
-	key := generateKey("PostGroupWithResponse", ctx, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostGroupResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostGroupWithResponse", params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostGroupResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -914,19 +1082,23 @@ func (c *CachedClientWithResponsesInterface) PostGroupWithResponse(ctx context.C
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // DeleteGroupIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) DeleteGroupIdWithResponse(ctx context.Context, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteGroupIdResponse, error) {
 	// Build cache key
-	key := generateKey("DeleteGroupIdWithResponse", ctx, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := DeleteGroupIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("DeleteGroupIdWithResponse", id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := DeleteGroupIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -935,19 +1107,23 @@ func (c *CachedClientWithResponsesInterface) DeleteGroupIdWithResponse(ctx conte
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetGroupIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetGroupIdWithResponse(ctx context.Context, id openapi_types.UUID, params *GetGroupIdParams, reqEditors ...RequestEditorFn) (*GetGroupIdResponse, error) {
 	// Build cache key
-	key := generateKey("GetGroupIdWithResponse", ctx, id, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetGroupIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetGroupIdWithResponse", id, params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetGroupIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -956,19 +1132,23 @@ func (c *CachedClientWithResponsesInterface) GetGroupIdWithResponse(ctx context.
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PutGroupIdWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PutGroupIdWithBodyWithResponse(ctx context.Context, id openapi_types.UUID, params *PutGroupIdParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutGroupIdResponse, error) {
 	// Build cache key
-	key := generateKey("PutGroupIdWithBodyWithResponse", ctx, id, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PutGroupIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PutGroupIdWithBodyWithResponse", id, params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PutGroupIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -977,19 +1157,23 @@ func (c *CachedClientWithResponsesInterface) PutGroupIdWithBodyWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PutGroupIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PutGroupIdWithResponse(ctx context.Context, id openapi_types.UUID, params *PutGroupIdParams, body PutGroupIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PutGroupIdResponse, error) {
 	// Build cache key
-	key := generateKey("PutGroupIdWithResponse", ctx, id, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PutGroupIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PutGroupIdWithResponse", id, params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PutGroupIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -998,19 +1182,23 @@ func (c *CachedClientWithResponsesInterface) PutGroupIdWithResponse(ctx context.
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // DeleteGroupIdFollowWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) DeleteGroupIdFollowWithResponse(ctx context.Context, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteGroupIdFollowResponse, error) {
 	// Build cache key
-	key := generateKey("DeleteGroupIdFollowWithResponse", ctx, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := DeleteGroupIdFollowResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("DeleteGroupIdFollowWithResponse", id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := DeleteGroupIdFollowResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1019,19 +1207,23 @@ func (c *CachedClientWithResponsesInterface) DeleteGroupIdFollowWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostGroupIdFollowWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostGroupIdFollowWithResponse(ctx context.Context, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*PostGroupIdFollowResponse, error) {
 	// Build cache key
-	key := generateKey("PostGroupIdFollowWithResponse", ctx, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostGroupIdFollowResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostGroupIdFollowWithResponse", id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostGroupIdFollowResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1040,19 +1232,23 @@ func (c *CachedClientWithResponsesInterface) PostGroupIdFollowWithResponse(ctx c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostLegacyMappingWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostLegacyMappingWithBodyWithResponse(ctx context.Context, params *PostLegacyMappingParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostLegacyMappingResponse, error) {
 	// Build cache key
-	key := generateKey("PostLegacyMappingWithBodyWithResponse", ctx, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostLegacyMappingResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostLegacyMappingWithBodyWithResponse", params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostLegacyMappingResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1061,19 +1257,23 @@ func (c *CachedClientWithResponsesInterface) PostLegacyMappingWithBodyWithRespon
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostLegacyMappingWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostLegacyMappingWithResponse(ctx context.Context, params *PostLegacyMappingParams, body PostLegacyMappingJSONRequestBody, reqEditors ...RequestEditorFn) (*PostLegacyMappingResponse, error) {
 	// Build cache key
-	key := generateKey("PostLegacyMappingWithResponse", ctx, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostLegacyMappingResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostLegacyMappingWithResponse", params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostLegacyMappingResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1082,19 +1282,23 @@ func (c *CachedClientWithResponsesInterface) PostLegacyMappingWithResponse(ctx c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostListWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostListWithBodyWithResponse(ctx context.Context, params *PostListParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostListResponse, error) {
 	// Build cache key
-	key := generateKey("PostListWithBodyWithResponse", ctx, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostListResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostListWithBodyWithResponse", params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostListResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1103,19 +1307,23 @@ func (c *CachedClientWithResponsesInterface) PostListWithBodyWithResponse(ctx co
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostListWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostListWithResponse(ctx context.Context, params *PostListParams, body PostListJSONRequestBody, reqEditors ...RequestEditorFn) (*PostListResponse, error) {
 	// Build cache key
-	key := generateKey("PostListWithResponse", ctx, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostListResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostListWithResponse", params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostListResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1124,19 +1332,23 @@ func (c *CachedClientWithResponsesInterface) PostListWithResponse(ctx context.Co
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // DeleteListIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) DeleteListIdWithResponse(ctx context.Context, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteListIdResponse, error) {
 	// Build cache key
-	key := generateKey("DeleteListIdWithResponse", ctx, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := DeleteListIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("DeleteListIdWithResponse", id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := DeleteListIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1145,19 +1357,23 @@ func (c *CachedClientWithResponsesInterface) DeleteListIdWithResponse(ctx contex
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetListIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetListIdWithResponse(ctx context.Context, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetListIdResponse, error) {
 	// Build cache key
-	key := generateKey("GetListIdWithResponse", ctx, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetListIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetListIdWithResponse", id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetListIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1166,19 +1382,23 @@ func (c *CachedClientWithResponsesInterface) GetListIdWithResponse(ctx context.C
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PutListIdWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PutListIdWithBodyWithResponse(ctx context.Context, id openapi_types.UUID, params *PutListIdParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutListIdResponse, error) {
 	// Build cache key
-	key := generateKey("PutListIdWithBodyWithResponse", ctx, id, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PutListIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PutListIdWithBodyWithResponse", id, params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PutListIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1187,19 +1407,23 @@ func (c *CachedClientWithResponsesInterface) PutListIdWithBodyWithResponse(ctx c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PutListIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PutListIdWithResponse(ctx context.Context, id openapi_types.UUID, params *PutListIdParams, body PutListIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PutListIdResponse, error) {
 	// Build cache key
-	key := generateKey("PutListIdWithResponse", ctx, id, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PutListIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PutListIdWithResponse", id, params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PutListIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1208,19 +1432,23 @@ func (c *CachedClientWithResponsesInterface) PutListIdWithResponse(ctx context.C
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetListIdFeedWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetListIdFeedWithResponse(ctx context.Context, id openapi_types.UUID, params *GetListIdFeedParams, reqEditors ...RequestEditorFn) (*GetListIdFeedResponse, error) {
 	// Build cache key
-	key := generateKey("GetListIdFeedWithResponse", ctx, id, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetListIdFeedResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetListIdFeedWithResponse", id, params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetListIdFeedResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1229,19 +1457,23 @@ func (c *CachedClientWithResponsesInterface) GetListIdFeedWithResponse(ctx conte
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // UnfollowListIdWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) UnfollowListIdWithBodyWithResponse(ctx context.Context, id openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UnfollowListIdResponse, error) {
 	// Build cache key
-	key := generateKey("UnfollowListIdWithBodyWithResponse", ctx, id, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := UnfollowListIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("UnfollowListIdWithBodyWithResponse", id, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := UnfollowListIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1250,19 +1482,23 @@ func (c *CachedClientWithResponsesInterface) UnfollowListIdWithBodyWithResponse(
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // UnfollowListIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) UnfollowListIdWithResponse(ctx context.Context, id openapi_types.UUID, body UnfollowListIdJSONRequestBody, reqEditors ...RequestEditorFn) (*UnfollowListIdResponse, error) {
 	// Build cache key
-	key := generateKey("UnfollowListIdWithResponse", ctx, id, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := UnfollowListIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("UnfollowListIdWithResponse", id, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := UnfollowListIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1271,19 +1507,23 @@ func (c *CachedClientWithResponsesInterface) UnfollowListIdWithResponse(ctx cont
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // FollowListIdWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) FollowListIdWithBodyWithResponse(ctx context.Context, id openapi_types.UUID, params *FollowListIdParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*FollowListIdResponse, error) {
 	// Build cache key
-	key := generateKey("FollowListIdWithBodyWithResponse", ctx, id, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := FollowListIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("FollowListIdWithBodyWithResponse", id, params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := FollowListIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1292,19 +1532,23 @@ func (c *CachedClientWithResponsesInterface) FollowListIdWithBodyWithResponse(ct
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // FollowListIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) FollowListIdWithResponse(ctx context.Context, id openapi_types.UUID, params *FollowListIdParams, body FollowListIdJSONRequestBody, reqEditors ...RequestEditorFn) (*FollowListIdResponse, error) {
 	// Build cache key
-	key := generateKey("FollowListIdWithResponse", ctx, id, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := FollowListIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("FollowListIdWithResponse", id, params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := FollowListIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1313,19 +1557,23 @@ func (c *CachedClientWithResponsesInterface) FollowListIdWithResponse(ctx contex
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetSearchMangaWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetSearchMangaWithResponse(ctx context.Context, params *GetSearchMangaParams, reqEditors ...RequestEditorFn) (*GetSearchMangaResponse, error) {
 	// Build cache key
-	key := generateKey("GetSearchMangaWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetSearchMangaResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetSearchMangaWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetSearchMangaResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1334,19 +1582,23 @@ func (c *CachedClientWithResponsesInterface) GetSearchMangaWithResponse(ctx cont
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostMangaWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostMangaWithBodyWithResponse(ctx context.Context, params *PostMangaParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostMangaResponse, error) {
 	// Build cache key
-	key := generateKey("PostMangaWithBodyWithResponse", ctx, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostMangaResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostMangaWithBodyWithResponse", params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostMangaResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1355,19 +1607,23 @@ func (c *CachedClientWithResponsesInterface) PostMangaWithBodyWithResponse(ctx c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostMangaWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostMangaWithResponse(ctx context.Context, params *PostMangaParams, body PostMangaJSONRequestBody, reqEditors ...RequestEditorFn) (*PostMangaResponse, error) {
 	// Build cache key
-	key := generateKey("PostMangaWithResponse", ctx, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostMangaResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostMangaWithResponse", params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostMangaResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1376,19 +1632,23 @@ func (c *CachedClientWithResponsesInterface) PostMangaWithResponse(ctx context.C
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetMangaDraftsWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetMangaDraftsWithResponse(ctx context.Context, params *GetMangaDraftsParams, reqEditors ...RequestEditorFn) (*GetMangaDraftsResponse, error) {
 	// Build cache key
-	key := generateKey("GetMangaDraftsWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetMangaDraftsResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetMangaDraftsWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetMangaDraftsResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1397,19 +1657,23 @@ func (c *CachedClientWithResponsesInterface) GetMangaDraftsWithResponse(ctx cont
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetMangaIdDraftWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetMangaIdDraftWithResponse(ctx context.Context, id openapi_types.UUID, params *GetMangaIdDraftParams, reqEditors ...RequestEditorFn) (*GetMangaIdDraftResponse, error) {
 	// Build cache key
-	key := generateKey("GetMangaIdDraftWithResponse", ctx, id, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetMangaIdDraftResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetMangaIdDraftWithResponse", id, params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetMangaIdDraftResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1418,19 +1682,23 @@ func (c *CachedClientWithResponsesInterface) GetMangaIdDraftWithResponse(ctx con
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // CommitMangaDraftWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) CommitMangaDraftWithBodyWithResponse(ctx context.Context, id openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CommitMangaDraftResponse, error) {
 	// Build cache key
-	key := generateKey("CommitMangaDraftWithBodyWithResponse", ctx, id, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := CommitMangaDraftResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("CommitMangaDraftWithBodyWithResponse", id, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := CommitMangaDraftResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1439,19 +1707,23 @@ func (c *CachedClientWithResponsesInterface) CommitMangaDraftWithBodyWithRespons
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // CommitMangaDraftWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) CommitMangaDraftWithResponse(ctx context.Context, id openapi_types.UUID, body CommitMangaDraftJSONRequestBody, reqEditors ...RequestEditorFn) (*CommitMangaDraftResponse, error) {
 	// Build cache key
-	key := generateKey("CommitMangaDraftWithResponse", ctx, id, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := CommitMangaDraftResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("CommitMangaDraftWithResponse", id, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := CommitMangaDraftResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1460,19 +1732,23 @@ func (c *CachedClientWithResponsesInterface) CommitMangaDraftWithResponse(ctx co
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetMangaRandomWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetMangaRandomWithResponse(ctx context.Context, params *GetMangaRandomParams, reqEditors ...RequestEditorFn) (*GetMangaRandomResponse, error) {
 	// Build cache key
-	key := generateKey("GetMangaRandomWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetMangaRandomResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetMangaRandomWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetMangaRandomResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1481,19 +1757,23 @@ func (c *CachedClientWithResponsesInterface) GetMangaRandomWithResponse(ctx cont
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetMangaChapterReadmarkers2WithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetMangaChapterReadmarkers2WithResponse(ctx context.Context, params *GetMangaChapterReadmarkers2Params, reqEditors ...RequestEditorFn) (*GetMangaChapterReadmarkers2Response, error) {
 	// Build cache key
-	key := generateKey("GetMangaChapterReadmarkers2WithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetMangaChapterReadmarkers2Response{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetMangaChapterReadmarkers2WithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetMangaChapterReadmarkers2Response{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1502,19 +1782,23 @@ func (c *CachedClientWithResponsesInterface) GetMangaChapterReadmarkers2WithResp
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetMangaStatusWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetMangaStatusWithResponse(ctx context.Context, params *GetMangaStatusParams, reqEditors ...RequestEditorFn) (*GetMangaStatusResponse, error) {
 	// Build cache key
-	key := generateKey("GetMangaStatusWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetMangaStatusResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetMangaStatusWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetMangaStatusResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1523,19 +1807,23 @@ func (c *CachedClientWithResponsesInterface) GetMangaStatusWithResponse(ctx cont
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetMangaTagWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetMangaTagWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetMangaTagResponse, error) {
 	// Build cache key
-	key := generateKey("GetMangaTagWithResponse", ctx, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetMangaTagResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetMangaTagWithResponse", reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetMangaTagResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1544,19 +1832,23 @@ func (c *CachedClientWithResponsesInterface) GetMangaTagWithResponse(ctx context
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // DeleteMangaIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) DeleteMangaIdWithResponse(ctx context.Context, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteMangaIdResponse, error) {
 	// Build cache key
-	key := generateKey("DeleteMangaIdWithResponse", ctx, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := DeleteMangaIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("DeleteMangaIdWithResponse", id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := DeleteMangaIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1565,19 +1857,23 @@ func (c *CachedClientWithResponsesInterface) DeleteMangaIdWithResponse(ctx conte
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetMangaIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetMangaIdWithResponse(ctx context.Context, id openapi_types.UUID, params *GetMangaIdParams, reqEditors ...RequestEditorFn) (*GetMangaIdResponse, error) {
 	// Build cache key
-	key := generateKey("GetMangaIdWithResponse", ctx, id, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetMangaIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetMangaIdWithResponse", id, params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetMangaIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1586,19 +1882,23 @@ func (c *CachedClientWithResponsesInterface) GetMangaIdWithResponse(ctx context.
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PutMangaIdWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PutMangaIdWithBodyWithResponse(ctx context.Context, id openapi_types.UUID, params *PutMangaIdParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutMangaIdResponse, error) {
 	// Build cache key
-	key := generateKey("PutMangaIdWithBodyWithResponse", ctx, id, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PutMangaIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PutMangaIdWithBodyWithResponse", id, params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PutMangaIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1607,19 +1907,23 @@ func (c *CachedClientWithResponsesInterface) PutMangaIdWithBodyWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PutMangaIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PutMangaIdWithResponse(ctx context.Context, id openapi_types.UUID, params *PutMangaIdParams, body PutMangaIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PutMangaIdResponse, error) {
 	// Build cache key
-	key := generateKey("PutMangaIdWithResponse", ctx, id, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PutMangaIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PutMangaIdWithResponse", id, params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PutMangaIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1628,19 +1932,23 @@ func (c *CachedClientWithResponsesInterface) PutMangaIdWithResponse(ctx context.
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetMangaAggregateWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetMangaAggregateWithResponse(ctx context.Context, id openapi_types.UUID, params *GetMangaAggregateParams, reqEditors ...RequestEditorFn) (*GetMangaAggregateResponse, error) {
 	// Build cache key
-	key := generateKey("GetMangaAggregateWithResponse", ctx, id, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetMangaAggregateResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetMangaAggregateWithResponse", id, params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetMangaAggregateResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1649,19 +1957,23 @@ func (c *CachedClientWithResponsesInterface) GetMangaAggregateWithResponse(ctx c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetMangaIdFeedWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetMangaIdFeedWithResponse(ctx context.Context, id openapi_types.UUID, params *GetMangaIdFeedParams, reqEditors ...RequestEditorFn) (*GetMangaIdFeedResponse, error) {
 	// Build cache key
-	key := generateKey("GetMangaIdFeedWithResponse", ctx, id, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetMangaIdFeedResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetMangaIdFeedWithResponse", id, params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetMangaIdFeedResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1670,19 +1982,23 @@ func (c *CachedClientWithResponsesInterface) GetMangaIdFeedWithResponse(ctx cont
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // DeleteMangaIdFollowWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) DeleteMangaIdFollowWithResponse(ctx context.Context, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteMangaIdFollowResponse, error) {
 	// Build cache key
-	key := generateKey("DeleteMangaIdFollowWithResponse", ctx, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := DeleteMangaIdFollowResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("DeleteMangaIdFollowWithResponse", id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := DeleteMangaIdFollowResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1691,19 +2007,23 @@ func (c *CachedClientWithResponsesInterface) DeleteMangaIdFollowWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostMangaIdFollowWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostMangaIdFollowWithResponse(ctx context.Context, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*PostMangaIdFollowResponse, error) {
 	// Build cache key
-	key := generateKey("PostMangaIdFollowWithResponse", ctx, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostMangaIdFollowResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostMangaIdFollowWithResponse", id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostMangaIdFollowResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1712,19 +2032,23 @@ func (c *CachedClientWithResponsesInterface) PostMangaIdFollowWithResponse(ctx c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // DeleteMangaIdListListIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) DeleteMangaIdListListIdWithResponse(ctx context.Context, id openapi_types.UUID, listId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteMangaIdListListIdResponse, error) {
 	// Build cache key
-	key := generateKey("DeleteMangaIdListListIdWithResponse", ctx, id, listId, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := DeleteMangaIdListListIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("DeleteMangaIdListListIdWithResponse", id, listId, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := DeleteMangaIdListListIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1733,19 +2057,23 @@ func (c *CachedClientWithResponsesInterface) DeleteMangaIdListListIdWithResponse
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostMangaIdListListIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostMangaIdListListIdWithResponse(ctx context.Context, id openapi_types.UUID, listId openapi_types.UUID, reqEditors ...RequestEditorFn) (*PostMangaIdListListIdResponse, error) {
 	// Build cache key
-	key := generateKey("PostMangaIdListListIdWithResponse", ctx, id, listId, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostMangaIdListListIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostMangaIdListListIdWithResponse", id, listId, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostMangaIdListListIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1754,19 +2082,23 @@ func (c *CachedClientWithResponsesInterface) PostMangaIdListListIdWithResponse(c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetMangaChapterReadmarkersWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetMangaChapterReadmarkersWithResponse(ctx context.Context, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetMangaChapterReadmarkersResponse, error) {
 	// Build cache key
-	key := generateKey("GetMangaChapterReadmarkersWithResponse", ctx, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetMangaChapterReadmarkersResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetMangaChapterReadmarkersWithResponse", id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetMangaChapterReadmarkersResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1775,19 +2107,23 @@ func (c *CachedClientWithResponsesInterface) GetMangaChapterReadmarkersWithRespo
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostMangaChapterReadmarkersWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostMangaChapterReadmarkersWithBodyWithResponse(ctx context.Context, id openapi_types.UUID, params *PostMangaChapterReadmarkersParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostMangaChapterReadmarkersResponse, error) {
 	// Build cache key
-	key := generateKey("PostMangaChapterReadmarkersWithBodyWithResponse", ctx, id, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostMangaChapterReadmarkersResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostMangaChapterReadmarkersWithBodyWithResponse", id, params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostMangaChapterReadmarkersResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1796,19 +2132,23 @@ func (c *CachedClientWithResponsesInterface) PostMangaChapterReadmarkersWithBody
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostMangaChapterReadmarkersWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostMangaChapterReadmarkersWithResponse(ctx context.Context, id openapi_types.UUID, params *PostMangaChapterReadmarkersParams, body PostMangaChapterReadmarkersJSONRequestBody, reqEditors ...RequestEditorFn) (*PostMangaChapterReadmarkersResponse, error) {
 	// Build cache key
-	key := generateKey("PostMangaChapterReadmarkersWithResponse", ctx, id, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostMangaChapterReadmarkersResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostMangaChapterReadmarkersWithResponse", id, params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostMangaChapterReadmarkersResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1817,19 +2157,23 @@ func (c *CachedClientWithResponsesInterface) PostMangaChapterReadmarkersWithResp
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetMangaIdStatusWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetMangaIdStatusWithResponse(ctx context.Context, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetMangaIdStatusResponse, error) {
 	// Build cache key
-	key := generateKey("GetMangaIdStatusWithResponse", ctx, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetMangaIdStatusResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetMangaIdStatusWithResponse", id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetMangaIdStatusResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1838,19 +2182,23 @@ func (c *CachedClientWithResponsesInterface) GetMangaIdStatusWithResponse(ctx co
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostMangaIdStatusWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostMangaIdStatusWithBodyWithResponse(ctx context.Context, id openapi_types.UUID, params *PostMangaIdStatusParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostMangaIdStatusResponse, error) {
 	// Build cache key
-	key := generateKey("PostMangaIdStatusWithBodyWithResponse", ctx, id, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostMangaIdStatusResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostMangaIdStatusWithBodyWithResponse", id, params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostMangaIdStatusResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1859,19 +2207,23 @@ func (c *CachedClientWithResponsesInterface) PostMangaIdStatusWithBodyWithRespon
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostMangaIdStatusWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostMangaIdStatusWithResponse(ctx context.Context, id openapi_types.UUID, params *PostMangaIdStatusParams, body PostMangaIdStatusJSONRequestBody, reqEditors ...RequestEditorFn) (*PostMangaIdStatusResponse, error) {
 	// Build cache key
-	key := generateKey("PostMangaIdStatusWithResponse", ctx, id, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostMangaIdStatusResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostMangaIdStatusWithResponse", id, params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostMangaIdStatusResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1880,19 +2232,23 @@ func (c *CachedClientWithResponsesInterface) PostMangaIdStatusWithResponse(ctx c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetMangaRelationWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetMangaRelationWithResponse(ctx context.Context, mangaId openapi_types.UUID, params *GetMangaRelationParams, reqEditors ...RequestEditorFn) (*GetMangaRelationResponse, error) {
 	// Build cache key
-	key := generateKey("GetMangaRelationWithResponse", ctx, mangaId, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetMangaRelationResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetMangaRelationWithResponse", mangaId, params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetMangaRelationResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1901,19 +2257,23 @@ func (c *CachedClientWithResponsesInterface) GetMangaRelationWithResponse(ctx co
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostMangaRelationWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostMangaRelationWithBodyWithResponse(ctx context.Context, mangaId openapi_types.UUID, params *PostMangaRelationParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostMangaRelationResponse, error) {
 	// Build cache key
-	key := generateKey("PostMangaRelationWithBodyWithResponse", ctx, mangaId, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostMangaRelationResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostMangaRelationWithBodyWithResponse", mangaId, params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostMangaRelationResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1922,19 +2282,23 @@ func (c *CachedClientWithResponsesInterface) PostMangaRelationWithBodyWithRespon
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostMangaRelationWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostMangaRelationWithResponse(ctx context.Context, mangaId openapi_types.UUID, params *PostMangaRelationParams, body PostMangaRelationJSONRequestBody, reqEditors ...RequestEditorFn) (*PostMangaRelationResponse, error) {
 	// Build cache key
-	key := generateKey("PostMangaRelationWithResponse", ctx, mangaId, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostMangaRelationResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostMangaRelationWithResponse", mangaId, params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostMangaRelationResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1943,19 +2307,23 @@ func (c *CachedClientWithResponsesInterface) PostMangaRelationWithResponse(ctx c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // DeleteMangaRelationIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) DeleteMangaRelationIdWithResponse(ctx context.Context, mangaId openapi_types.UUID, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteMangaRelationIdResponse, error) {
 	// Build cache key
-	key := generateKey("DeleteMangaRelationIdWithResponse", ctx, mangaId, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := DeleteMangaRelationIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("DeleteMangaRelationIdWithResponse", mangaId, id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := DeleteMangaRelationIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1964,19 +2332,23 @@ func (c *CachedClientWithResponsesInterface) DeleteMangaRelationIdWithResponse(c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetPingWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetPingWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetPingResponse, error) {
 	// Build cache key
-	key := generateKey("GetPingWithResponse", ctx, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetPingResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetPingWithResponse", reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetPingResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -1985,19 +2357,23 @@ func (c *CachedClientWithResponsesInterface) GetPingWithResponse(ctx context.Con
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetRatingWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetRatingWithResponse(ctx context.Context, params *GetRatingParams, reqEditors ...RequestEditorFn) (*GetRatingResponse, error) {
 	// Build cache key
-	key := generateKey("GetRatingWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetRatingResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetRatingWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetRatingResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2006,19 +2382,23 @@ func (c *CachedClientWithResponsesInterface) GetRatingWithResponse(ctx context.C
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // DeleteRatingMangaIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) DeleteRatingMangaIdWithResponse(ctx context.Context, mangaId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteRatingMangaIdResponse, error) {
 	// Build cache key
-	key := generateKey("DeleteRatingMangaIdWithResponse", ctx, mangaId, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := DeleteRatingMangaIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("DeleteRatingMangaIdWithResponse", mangaId, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := DeleteRatingMangaIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2027,19 +2407,23 @@ func (c *CachedClientWithResponsesInterface) DeleteRatingMangaIdWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostRatingMangaIdWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostRatingMangaIdWithBodyWithResponse(ctx context.Context, mangaId openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostRatingMangaIdResponse, error) {
 	// Build cache key
-	key := generateKey("PostRatingMangaIdWithBodyWithResponse", ctx, mangaId, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostRatingMangaIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostRatingMangaIdWithBodyWithResponse", mangaId, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostRatingMangaIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2048,19 +2432,23 @@ func (c *CachedClientWithResponsesInterface) PostRatingMangaIdWithBodyWithRespon
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostRatingMangaIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostRatingMangaIdWithResponse(ctx context.Context, mangaId openapi_types.UUID, body PostRatingMangaIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PostRatingMangaIdResponse, error) {
 	// Build cache key
-	key := generateKey("PostRatingMangaIdWithResponse", ctx, mangaId, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostRatingMangaIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostRatingMangaIdWithResponse", mangaId, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostRatingMangaIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2069,19 +2457,23 @@ func (c *CachedClientWithResponsesInterface) PostRatingMangaIdWithResponse(ctx c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetReportsWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetReportsWithResponse(ctx context.Context, params *GetReportsParams, reqEditors ...RequestEditorFn) (*GetReportsResponse, error) {
 	// Build cache key
-	key := generateKey("GetReportsWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetReportsResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetReportsWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetReportsResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2090,19 +2482,23 @@ func (c *CachedClientWithResponsesInterface) GetReportsWithResponse(ctx context.
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostReportWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostReportWithBodyWithResponse(ctx context.Context, params *PostReportParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostReportResponse, error) {
 	// Build cache key
-	key := generateKey("PostReportWithBodyWithResponse", ctx, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostReportResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostReportWithBodyWithResponse", params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostReportResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2111,19 +2507,23 @@ func (c *CachedClientWithResponsesInterface) PostReportWithBodyWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostReportWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostReportWithResponse(ctx context.Context, params *PostReportParams, body PostReportJSONRequestBody, reqEditors ...RequestEditorFn) (*PostReportResponse, error) {
 	// Build cache key
-	key := generateKey("PostReportWithResponse", ctx, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostReportResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostReportWithResponse", params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostReportResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2132,19 +2532,23 @@ func (c *CachedClientWithResponsesInterface) PostReportWithResponse(ctx context.
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetReportReasonsByCategoryWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetReportReasonsByCategoryWithResponse(ctx context.Context, category string, reqEditors ...RequestEditorFn) (*GetReportReasonsByCategoryResponse, error) {
 	// Build cache key
-	key := generateKey("GetReportReasonsByCategoryWithResponse", ctx, category, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetReportReasonsByCategoryResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetReportReasonsByCategoryWithResponse", category, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetReportReasonsByCategoryResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2153,19 +2557,23 @@ func (c *CachedClientWithResponsesInterface) GetReportReasonsByCategoryWithRespo
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetSettingsWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetSettingsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetSettingsResponse, error) {
 	// Build cache key
-	key := generateKey("GetSettingsWithResponse", ctx, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetSettingsResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetSettingsWithResponse", reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetSettingsResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2174,19 +2582,23 @@ func (c *CachedClientWithResponsesInterface) GetSettingsWithResponse(ctx context
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostSettingsWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostSettingsWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostSettingsResponse, error) {
 	// Build cache key
-	key := generateKey("PostSettingsWithBodyWithResponse", ctx, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostSettingsResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostSettingsWithBodyWithResponse", contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostSettingsResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2195,19 +2607,23 @@ func (c *CachedClientWithResponsesInterface) PostSettingsWithBodyWithResponse(ct
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostSettingsWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostSettingsWithResponse(ctx context.Context, body PostSettingsJSONRequestBody, reqEditors ...RequestEditorFn) (*PostSettingsResponse, error) {
 	// Build cache key
-	key := generateKey("PostSettingsWithResponse", ctx, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostSettingsResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostSettingsWithResponse", body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostSettingsResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2216,19 +2632,23 @@ func (c *CachedClientWithResponsesInterface) PostSettingsWithResponse(ctx contex
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetSettingsTemplateWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetSettingsTemplateWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetSettingsTemplateResponse, error) {
 	// Build cache key
-	key := generateKey("GetSettingsTemplateWithResponse", ctx, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetSettingsTemplateResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetSettingsTemplateWithResponse", reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetSettingsTemplateResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2237,19 +2657,23 @@ func (c *CachedClientWithResponsesInterface) GetSettingsTemplateWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostSettingsTemplateWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostSettingsTemplateWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostSettingsTemplateResponse, error) {
 	// Build cache key
-	key := generateKey("PostSettingsTemplateWithBodyWithResponse", ctx, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostSettingsTemplateResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostSettingsTemplateWithBodyWithResponse", contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostSettingsTemplateResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2258,19 +2682,23 @@ func (c *CachedClientWithResponsesInterface) PostSettingsTemplateWithBodyWithRes
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostSettingsTemplateWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostSettingsTemplateWithResponse(ctx context.Context, body PostSettingsTemplateJSONRequestBody, reqEditors ...RequestEditorFn) (*PostSettingsTemplateResponse, error) {
 	// Build cache key
-	key := generateKey("PostSettingsTemplateWithResponse", ctx, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostSettingsTemplateResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostSettingsTemplateWithResponse", body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostSettingsTemplateResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2279,19 +2707,23 @@ func (c *CachedClientWithResponsesInterface) PostSettingsTemplateWithResponse(ct
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetSettingsTemplateVersionWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetSettingsTemplateVersionWithResponse(ctx context.Context, version openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetSettingsTemplateVersionResponse, error) {
 	// Build cache key
-	key := generateKey("GetSettingsTemplateVersionWithResponse", ctx, version, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetSettingsTemplateVersionResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetSettingsTemplateVersionWithResponse", version, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetSettingsTemplateVersionResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2300,19 +2732,23 @@ func (c *CachedClientWithResponsesInterface) GetSettingsTemplateVersionWithRespo
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetStatisticsChaptersWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetStatisticsChaptersWithResponse(ctx context.Context, params *GetStatisticsChaptersParams, reqEditors ...RequestEditorFn) (*GetStatisticsChaptersResponse, error) {
 	// Build cache key
-	key := generateKey("GetStatisticsChaptersWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetStatisticsChaptersResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetStatisticsChaptersWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetStatisticsChaptersResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2321,19 +2757,23 @@ func (c *CachedClientWithResponsesInterface) GetStatisticsChaptersWithResponse(c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetStatisticsChapterUuidWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetStatisticsChapterUuidWithResponse(ctx context.Context, uuid openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetStatisticsChapterUuidResponse, error) {
 	// Build cache key
-	key := generateKey("GetStatisticsChapterUuidWithResponse", ctx, uuid, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetStatisticsChapterUuidResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetStatisticsChapterUuidWithResponse", uuid, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetStatisticsChapterUuidResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2342,19 +2782,23 @@ func (c *CachedClientWithResponsesInterface) GetStatisticsChapterUuidWithRespons
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetStatisticsGroupsWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetStatisticsGroupsWithResponse(ctx context.Context, params *GetStatisticsGroupsParams, reqEditors ...RequestEditorFn) (*GetStatisticsGroupsResponse, error) {
 	// Build cache key
-	key := generateKey("GetStatisticsGroupsWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetStatisticsGroupsResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetStatisticsGroupsWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetStatisticsGroupsResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2363,19 +2807,23 @@ func (c *CachedClientWithResponsesInterface) GetStatisticsGroupsWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetStatisticsGroupUuidWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetStatisticsGroupUuidWithResponse(ctx context.Context, uuid openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetStatisticsGroupUuidResponse, error) {
 	// Build cache key
-	key := generateKey("GetStatisticsGroupUuidWithResponse", ctx, uuid, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetStatisticsGroupUuidResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetStatisticsGroupUuidWithResponse", uuid, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetStatisticsGroupUuidResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2384,19 +2832,23 @@ func (c *CachedClientWithResponsesInterface) GetStatisticsGroupUuidWithResponse(
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetStatisticsMangaWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetStatisticsMangaWithResponse(ctx context.Context, params *GetStatisticsMangaParams, reqEditors ...RequestEditorFn) (*GetStatisticsMangaResponse, error) {
 	// Build cache key
-	key := generateKey("GetStatisticsMangaWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetStatisticsMangaResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetStatisticsMangaWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetStatisticsMangaResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2405,19 +2857,23 @@ func (c *CachedClientWithResponsesInterface) GetStatisticsMangaWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetStatisticsMangaUuidWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetStatisticsMangaUuidWithResponse(ctx context.Context, uuid openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetStatisticsMangaUuidResponse, error) {
 	// Build cache key
-	key := generateKey("GetStatisticsMangaUuidWithResponse", ctx, uuid, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetStatisticsMangaUuidResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetStatisticsMangaUuidWithResponse", uuid, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetStatisticsMangaUuidResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2426,19 +2882,23 @@ func (c *CachedClientWithResponsesInterface) GetStatisticsMangaUuidWithResponse(
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetUploadSessionWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetUploadSessionWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetUploadSessionResponse, error) {
 	// Build cache key
-	key := generateKey("GetUploadSessionWithResponse", ctx, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetUploadSessionResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetUploadSessionWithResponse", reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetUploadSessionResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2447,19 +2907,23 @@ func (c *CachedClientWithResponsesInterface) GetUploadSessionWithResponse(ctx co
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // BeginUploadSessionWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) BeginUploadSessionWithBodyWithResponse(ctx context.Context, params *BeginUploadSessionParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*BeginUploadSessionResponse, error) {
 	// Build cache key
-	key := generateKey("BeginUploadSessionWithBodyWithResponse", ctx, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := BeginUploadSessionResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("BeginUploadSessionWithBodyWithResponse", params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := BeginUploadSessionResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2468,19 +2932,23 @@ func (c *CachedClientWithResponsesInterface) BeginUploadSessionWithBodyWithRespo
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // BeginUploadSessionWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) BeginUploadSessionWithResponse(ctx context.Context, params *BeginUploadSessionParams, body BeginUploadSessionJSONRequestBody, reqEditors ...RequestEditorFn) (*BeginUploadSessionResponse, error) {
 	// Build cache key
-	key := generateKey("BeginUploadSessionWithResponse", ctx, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := BeginUploadSessionResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("BeginUploadSessionWithResponse", params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := BeginUploadSessionResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2489,19 +2957,23 @@ func (c *CachedClientWithResponsesInterface) BeginUploadSessionWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // BeginEditSessionWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) BeginEditSessionWithBodyWithResponse(ctx context.Context, chapterId openapi_types.UUID, params *BeginEditSessionParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*BeginEditSessionResponse, error) {
 	// Build cache key
-	key := generateKey("BeginEditSessionWithBodyWithResponse", ctx, chapterId, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := BeginEditSessionResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("BeginEditSessionWithBodyWithResponse", chapterId, params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := BeginEditSessionResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2510,19 +2982,23 @@ func (c *CachedClientWithResponsesInterface) BeginEditSessionWithBodyWithRespons
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // BeginEditSessionWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) BeginEditSessionWithResponse(ctx context.Context, chapterId openapi_types.UUID, params *BeginEditSessionParams, body BeginEditSessionJSONRequestBody, reqEditors ...RequestEditorFn) (*BeginEditSessionResponse, error) {
 	// Build cache key
-	key := generateKey("BeginEditSessionWithResponse", ctx, chapterId, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := BeginEditSessionResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("BeginEditSessionWithResponse", chapterId, params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := BeginEditSessionResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2531,19 +3007,23 @@ func (c *CachedClientWithResponsesInterface) BeginEditSessionWithResponse(ctx co
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // UploadCheckApprovalRequiredWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) UploadCheckApprovalRequiredWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UploadCheckApprovalRequiredResponse, error) {
 	// Build cache key
-	key := generateKey("UploadCheckApprovalRequiredWithBodyWithResponse", ctx, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := UploadCheckApprovalRequiredResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("UploadCheckApprovalRequiredWithBodyWithResponse", contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := UploadCheckApprovalRequiredResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2552,19 +3032,23 @@ func (c *CachedClientWithResponsesInterface) UploadCheckApprovalRequiredWithBody
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // UploadCheckApprovalRequiredWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) UploadCheckApprovalRequiredWithResponse(ctx context.Context, body UploadCheckApprovalRequiredJSONRequestBody, reqEditors ...RequestEditorFn) (*UploadCheckApprovalRequiredResponse, error) {
 	// Build cache key
-	key := generateKey("UploadCheckApprovalRequiredWithResponse", ctx, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := UploadCheckApprovalRequiredResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("UploadCheckApprovalRequiredWithResponse", body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := UploadCheckApprovalRequiredResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2573,19 +3057,23 @@ func (c *CachedClientWithResponsesInterface) UploadCheckApprovalRequiredWithResp
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // AbandonUploadSessionWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) AbandonUploadSessionWithResponse(ctx context.Context, uploadSessionId openapi_types.UUID, reqEditors ...RequestEditorFn) (*AbandonUploadSessionResponse, error) {
 	// Build cache key
-	key := generateKey("AbandonUploadSessionWithResponse", ctx, uploadSessionId, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := AbandonUploadSessionResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("AbandonUploadSessionWithResponse", uploadSessionId, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := AbandonUploadSessionResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2594,19 +3082,23 @@ func (c *CachedClientWithResponsesInterface) AbandonUploadSessionWithResponse(ct
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PutUploadSessionFileWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PutUploadSessionFileWithBodyWithResponse(ctx context.Context, uploadSessionId openapi_types.UUID, params *PutUploadSessionFileParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutUploadSessionFileResponse, error) {
 	// Build cache key
-	key := generateKey("PutUploadSessionFileWithBodyWithResponse", ctx, uploadSessionId, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PutUploadSessionFileResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PutUploadSessionFileWithBodyWithResponse", uploadSessionId, params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PutUploadSessionFileResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2615,19 +3107,23 @@ func (c *CachedClientWithResponsesInterface) PutUploadSessionFileWithBodyWithRes
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // DeleteUploadedSessionFilesWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) DeleteUploadedSessionFilesWithBodyWithResponse(ctx context.Context, uploadSessionId openapi_types.UUID, params *DeleteUploadedSessionFilesParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*DeleteUploadedSessionFilesResponse, error) {
 	// Build cache key
-	key := generateKey("DeleteUploadedSessionFilesWithBodyWithResponse", ctx, uploadSessionId, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := DeleteUploadedSessionFilesResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("DeleteUploadedSessionFilesWithBodyWithResponse", uploadSessionId, params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := DeleteUploadedSessionFilesResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2636,19 +3132,23 @@ func (c *CachedClientWithResponsesInterface) DeleteUploadedSessionFilesWithBodyW
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // DeleteUploadedSessionFilesWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) DeleteUploadedSessionFilesWithResponse(ctx context.Context, uploadSessionId openapi_types.UUID, params *DeleteUploadedSessionFilesParams, body DeleteUploadedSessionFilesJSONRequestBody, reqEditors ...RequestEditorFn) (*DeleteUploadedSessionFilesResponse, error) {
 	// Build cache key
-	key := generateKey("DeleteUploadedSessionFilesWithResponse", ctx, uploadSessionId, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := DeleteUploadedSessionFilesResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("DeleteUploadedSessionFilesWithResponse", uploadSessionId, params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := DeleteUploadedSessionFilesResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2657,19 +3157,23 @@ func (c *CachedClientWithResponsesInterface) DeleteUploadedSessionFilesWithRespo
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // CommitUploadSessionWithBodyWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) CommitUploadSessionWithBodyWithResponse(ctx context.Context, uploadSessionId openapi_types.UUID, params *CommitUploadSessionParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CommitUploadSessionResponse, error) {
 	// Build cache key
-	key := generateKey("CommitUploadSessionWithBodyWithResponse", ctx, uploadSessionId, params, contentType, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := CommitUploadSessionResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("CommitUploadSessionWithBodyWithResponse", uploadSessionId, params, contentType, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := CommitUploadSessionResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2678,19 +3182,23 @@ func (c *CachedClientWithResponsesInterface) CommitUploadSessionWithBodyWithResp
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // CommitUploadSessionWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) CommitUploadSessionWithResponse(ctx context.Context, uploadSessionId openapi_types.UUID, params *CommitUploadSessionParams, body CommitUploadSessionJSONRequestBody, reqEditors ...RequestEditorFn) (*CommitUploadSessionResponse, error) {
 	// Build cache key
-	key := generateKey("CommitUploadSessionWithResponse", ctx, uploadSessionId, params, body, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := CommitUploadSessionResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("CommitUploadSessionWithResponse", uploadSessionId, params, body, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := CommitUploadSessionResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2699,19 +3207,23 @@ func (c *CachedClientWithResponsesInterface) CommitUploadSessionWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // DeleteUploadedSessionFileWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) DeleteUploadedSessionFileWithResponse(ctx context.Context, uploadSessionId openapi_types.UUID, uploadSessionFileId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteUploadedSessionFileResponse, error) {
 	// Build cache key
-	key := generateKey("DeleteUploadedSessionFileWithResponse", ctx, uploadSessionId, uploadSessionFileId, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := DeleteUploadedSessionFileResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("DeleteUploadedSessionFileWithResponse", uploadSessionId, uploadSessionFileId, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := DeleteUploadedSessionFileResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2720,19 +3232,23 @@ func (c *CachedClientWithResponsesInterface) DeleteUploadedSessionFileWithRespon
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetUserWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetUserWithResponse(ctx context.Context, params *GetUserParams, reqEditors ...RequestEditorFn) (*GetUserResponse, error) {
 	// Build cache key
-	key := generateKey("GetUserWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetUserResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetUserWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetUserResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2741,19 +3257,23 @@ func (c *CachedClientWithResponsesInterface) GetUserWithResponse(ctx context.Con
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // PostUserDeleteCodeWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) PostUserDeleteCodeWithResponse(ctx context.Context, code openapi_types.UUID, reqEditors ...RequestEditorFn) (*PostUserDeleteCodeResponse, error) {
 	// Build cache key
-	key := generateKey("PostUserDeleteCodeWithResponse", ctx, code, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := PostUserDeleteCodeResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("PostUserDeleteCodeWithResponse", code, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := PostUserDeleteCodeResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2762,19 +3282,23 @@ func (c *CachedClientWithResponsesInterface) PostUserDeleteCodeWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetUserFollowsGroupWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetUserFollowsGroupWithResponse(ctx context.Context, params *GetUserFollowsGroupParams, reqEditors ...RequestEditorFn) (*GetUserFollowsGroupResponse, error) {
 	// Build cache key
-	key := generateKey("GetUserFollowsGroupWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetUserFollowsGroupResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetUserFollowsGroupWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetUserFollowsGroupResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2783,19 +3307,23 @@ func (c *CachedClientWithResponsesInterface) GetUserFollowsGroupWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetUserFollowsGroupIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetUserFollowsGroupIdWithResponse(ctx context.Context, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetUserFollowsGroupIdResponse, error) {
 	// Build cache key
-	key := generateKey("GetUserFollowsGroupIdWithResponse", ctx, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetUserFollowsGroupIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetUserFollowsGroupIdWithResponse", id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetUserFollowsGroupIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2804,19 +3332,23 @@ func (c *CachedClientWithResponsesInterface) GetUserFollowsGroupIdWithResponse(c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetUserFollowsListWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetUserFollowsListWithResponse(ctx context.Context, params *GetUserFollowsListParams, reqEditors ...RequestEditorFn) (*GetUserFollowsListResponse, error) {
 	// Build cache key
-	key := generateKey("GetUserFollowsListWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetUserFollowsListResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetUserFollowsListWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetUserFollowsListResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2825,19 +3357,23 @@ func (c *CachedClientWithResponsesInterface) GetUserFollowsListWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetUserFollowsListIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetUserFollowsListIdWithResponse(ctx context.Context, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetUserFollowsListIdResponse, error) {
 	// Build cache key
-	key := generateKey("GetUserFollowsListIdWithResponse", ctx, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetUserFollowsListIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetUserFollowsListIdWithResponse", id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetUserFollowsListIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2846,19 +3382,23 @@ func (c *CachedClientWithResponsesInterface) GetUserFollowsListIdWithResponse(ct
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetUserFollowsMangaWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetUserFollowsMangaWithResponse(ctx context.Context, params *GetUserFollowsMangaParams, reqEditors ...RequestEditorFn) (*GetUserFollowsMangaResponse, error) {
 	// Build cache key
-	key := generateKey("GetUserFollowsMangaWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetUserFollowsMangaResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetUserFollowsMangaWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetUserFollowsMangaResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2867,19 +3407,23 @@ func (c *CachedClientWithResponsesInterface) GetUserFollowsMangaWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetUserFollowsMangaFeedWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetUserFollowsMangaFeedWithResponse(ctx context.Context, params *GetUserFollowsMangaFeedParams, reqEditors ...RequestEditorFn) (*GetUserFollowsMangaFeedResponse, error) {
 	// Build cache key
-	key := generateKey("GetUserFollowsMangaFeedWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetUserFollowsMangaFeedResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetUserFollowsMangaFeedWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetUserFollowsMangaFeedResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2888,19 +3432,23 @@ func (c *CachedClientWithResponsesInterface) GetUserFollowsMangaFeedWithResponse
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetUserFollowsMangaIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetUserFollowsMangaIdWithResponse(ctx context.Context, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetUserFollowsMangaIdResponse, error) {
 	// Build cache key
-	key := generateKey("GetUserFollowsMangaIdWithResponse", ctx, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetUserFollowsMangaIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetUserFollowsMangaIdWithResponse", id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetUserFollowsMangaIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2909,19 +3457,23 @@ func (c *CachedClientWithResponsesInterface) GetUserFollowsMangaIdWithResponse(c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetUserFollowsUserWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetUserFollowsUserWithResponse(ctx context.Context, params *GetUserFollowsUserParams, reqEditors ...RequestEditorFn) (*GetUserFollowsUserResponse, error) {
 	// Build cache key
-	key := generateKey("GetUserFollowsUserWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetUserFollowsUserResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetUserFollowsUserWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetUserFollowsUserResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2930,19 +3482,23 @@ func (c *CachedClientWithResponsesInterface) GetUserFollowsUserWithResponse(ctx
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetUserFollowsUserIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetUserFollowsUserIdWithResponse(ctx context.Context, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetUserFollowsUserIdResponse, error) {
 	// Build cache key
-	key := generateKey("GetUserFollowsUserIdWithResponse", ctx, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetUserFollowsUserIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetUserFollowsUserIdWithResponse", id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetUserFollowsUserIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2951,19 +3507,23 @@ func (c *CachedClientWithResponsesInterface) GetUserFollowsUserIdWithResponse(ct
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetReadingHistoryWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetReadingHistoryWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetReadingHistoryResponse, error) {
 	// Build cache key
-	key := generateKey("GetReadingHistoryWithResponse", ctx, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetReadingHistoryResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetReadingHistoryWithResponse", reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetReadingHistoryResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2972,19 +3532,23 @@ func (c *CachedClientWithResponsesInterface) GetReadingHistoryWithResponse(ctx c
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetUserListWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetUserListWithResponse(ctx context.Context, params *GetUserListParams, reqEditors ...RequestEditorFn) (*GetUserListResponse, error) {
 	// Build cache key
-	key := generateKey("GetUserListWithResponse", ctx, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetUserListResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetUserListWithResponse", params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetUserListResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -2993,19 +3557,23 @@ func (c *CachedClientWithResponsesInterface) GetUserListWithResponse(ctx context
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetUserMeWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetUserMeWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetUserMeResponse, error) {
 	// Build cache key
-	key := generateKey("GetUserMeWithResponse", ctx, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetUserMeResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetUserMeWithResponse", reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetUserMeResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -3014,19 +3582,23 @@ func (c *CachedClientWithResponsesInterface) GetUserMeWithResponse(ctx context.C
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // DeleteUserIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) DeleteUserIdWithResponse(ctx context.Context, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteUserIdResponse, error) {
 	// Build cache key
-	key := generateKey("DeleteUserIdWithResponse", ctx, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := DeleteUserIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("DeleteUserIdWithResponse", id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := DeleteUserIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -3035,19 +3607,23 @@ func (c *CachedClientWithResponsesInterface) DeleteUserIdWithResponse(ctx contex
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetUserIdWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetUserIdWithResponse(ctx context.Context, id openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetUserIdResponse, error) {
 	// Build cache key
-	key := generateKey("GetUserIdWithResponse", ctx, id, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetUserIdResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetUserIdWithResponse", id, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetUserIdResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -3056,19 +3632,23 @@ func (c *CachedClientWithResponsesInterface) GetUserIdWithResponse(ctx context.C
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
 
 // GetUserIdListWithResponse applies caching before delegating to the underlying client.
 func (c *CachedClientWithResponsesInterface) GetUserIdListWithResponse(ctx context.Context, id openapi_types.UUID, params *GetUserIdListParams, reqEditors ...RequestEditorFn) (*GetUserIdListResponse, error) {
 	// Build cache key
-	key := generateKey("GetUserIdListWithResponse", ctx, id, params, reqEditors)
-	if v, ok := c.cache.Get(key); ok {
-		output := GetUserIdListResponse{}
-		err := json.Unmarshal(v, &output)
-		if err == nil {
-			return &output, nil
+	key, cacheable := generateKey("GetUserIdListWithResponse", id, params, reqEditors)
+	if cacheable && !cacheSkipped(ctx) {
+		if v, ok := c.cache.Get(key); ok {
+			output := GetUserIdListResponse{}
+			err := json.Unmarshal(v, &output)
+			if err == nil {
+				return &output, nil
+			}
 		}
 	}
 
@@ -3077,6 +3657,8 @@ func (c *CachedClientWithResponsesInterface) GetUserIdListWithResponse(ctx conte
 	if err != nil {
 		return resp, err
 	}
-	c.cache.Set(key, resp)
+	if cacheable {
+		c.cache.Set(key, resp)
+	}
 	return resp, nil
 }
@@ -0,0 +1,85 @@
+package mangadex
+
+import (
+	"errors"
+	"net/url"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// DefaultUploadsHost serves cover art and other user uploads.
+const DefaultUploadsHost = "https://uploads.mangadex.org"
+
+// CoverSize selects the original cover image or one of its thumbnails.
+type CoverSize string
+
+const (
+	CoverOriginal CoverSize = ""
+	Cover256      CoverSize = ".256.jpg"
+	Cover512      CoverSize = ".512.jpg"
+)
+
+var (
+	ErrNoCoverArt     = errors.New("no cover art available")
+	ErrNoCoverManga   = errors.New("cover has no manga relationship")
+	ErrMissingMangaID = errors.New("missing manga id")
+)
+
+// CoverURLs builds cover art URLs against an uploads host.
+type CoverURLs struct {
+	// UploadsHost defaults to DefaultUploadsHost; set it to use a mirror.
+	UploadsHost string
+}
+
+// NewCoverURLs creates a CoverURLs for host, or DefaultUploadsHost if host is empty.
+func NewCoverURLs(host string) *CoverURLs {
+	return &CoverURLs{UploadsHost: host}
+}
+
+// URL returns the URL of fileName for mangaID at the given size.
+func (c *CoverURLs) URL(mangaID openapi_types.UUID, fileName string, size CoverSize) (string, error) {
+	if fileName == "" {
+		return "", ErrNoCoverArt
+	}
+	host := c.UploadsHost
+	if host == "" {
+		host = DefaultUploadsHost
+	}
+	return url.JoinPath(host, "covers", mangaID.String(), fileName+string(size))
+}
+
+// FromCover returns the URL of a Cover. The manga id is taken from the
+// cover's manga relationship.
+func (c *CoverURLs) FromCover(cover *Cover, size CoverSize) (string, error) {
+	if cover == nil || cover.Attributes == nil || cover.Attributes.FileName == nil {
+		return "", ErrNoCoverArt
+	}
+	m, err := cover.Manga()
+	if err != nil {
+		return "", err
+	}
+	if m == nil {
+		return "", ErrNoCoverManga
+	}
+	return c.URL(m.Id, *cover.Attributes.FileName, size)
+}
+
+// FromManga returns the cover URL of a Manga fetched with includes[]=cover_art.
+func (c *CoverURLs) FromManga(m *Manga, size CoverSize) (string, error) {
+	if m == nil || m.Id == nil {
+		return "", ErrMissingMangaID
+	}
+	art, err := m.CoverArt()
+	if err != nil {
+		return "", err
+	}
+	if art == nil || art.Attributes == nil || art.Attributes.FileName == nil {
+		return "", ErrNoCoverArt
+	}
+	return c.URL(*m.Id, *art.Attributes.FileName, size)
+}
+
+// CoverURL returns the URL of fileName for mangaID on DefaultUploadsHost.
+func CoverURL(mangaID openapi_types.UUID, fileName string, size CoverSize) (string, error) {
+	return (&CoverURLs{}).URL(mangaID, fileName, size)
+}
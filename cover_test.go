@@ -0,0 +1,70 @@
+package mangadex
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCoverURLs(t *testing.T) {
+	mangaID := uuid.MustParse("a1c7c817-4e59-43b7-9365-09675a149a6f")
+	coverFile := "b6c7ce9c-e671-4f26-90b0-e592188e9cd6.jpg"
+
+	var withCover, withoutCover Manga
+	if err := json.Unmarshal([]byte(`{"id":"`+mangaID.String()+`","relationships":[
+		{"id":"`+uuid.NewString()+`","type":"author"},
+		{"id":"`+uuid.NewString()+`","type":"cover_art","attributes":{"fileName":"`+coverFile+`","volume":"1"}}
+	]}`), &withCover); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"id":"`+mangaID.String()+`","relationships":[
+		{"id":"`+uuid.NewString()+`","type":"cover_art"}
+	]}`), &withoutCover); err != nil {
+		t.Fatal(err)
+	}
+	var cover, orphan Cover
+	if err := json.Unmarshal([]byte(`{"attributes":{"fileName":"`+coverFile+`"},"relationships":[
+		{"id":"`+mangaID.String()+`","type":"manga"}
+	]}`), &cover); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"attributes":{"fileName":"`+coverFile+`"},"relationships":[]}`), &orphan); err != nil {
+		t.Fatal(err)
+	}
+
+	base := DefaultUploadsHost + "/covers/" + mangaID.String() + "/" + coverFile
+	tests := []struct {
+		name    string
+		host    string
+		get     func(c *CoverURLs, size CoverSize) (string, error)
+		size    CoverSize
+		want    string
+		wantErr error
+	}{
+		{"file", "", func(c *CoverURLs, s CoverSize) (string, error) { return c.URL(mangaID, coverFile, s) }, CoverOriginal, base, nil},
+		{"thumbnail", "", func(c *CoverURLs, s CoverSize) (string, error) { return c.URL(mangaID, coverFile, s) }, Cover256, base + ".256.jpg", nil},
+		{"mirror", "https://mirror.example.test/md/", func(c *CoverURLs, s CoverSize) (string, error) { return c.URL(mangaID, coverFile, s) }, Cover512,
+			"https://mirror.example.test/md/covers/" + mangaID.String() + "/" + coverFile + ".512.jpg", nil},
+		{"no file", "", func(c *CoverURLs, s CoverSize) (string, error) { return c.URL(mangaID, "", s) }, CoverOriginal, "", ErrNoCoverArt},
+		{"manga", "", func(c *CoverURLs, s CoverSize) (string, error) { return c.FromManga(&withCover, s) }, Cover512, base + ".512.jpg", nil},
+		{"manga without expansion", "", func(c *CoverURLs, s CoverSize) (string, error) { return c.FromManga(&withoutCover, s) }, CoverOriginal, "", ErrNoCoverArt},
+		{"manga without id", "", func(c *CoverURLs, s CoverSize) (string, error) { return c.FromManga(&Manga{}, s) }, CoverOriginal, "", ErrMissingMangaID},
+		{"cover", "", func(c *CoverURLs, s CoverSize) (string, error) { return c.FromCover(&cover, s) }, Cover256, base + ".256.jpg", nil},
+		{"cover without manga", "", func(c *CoverURLs, s CoverSize) (string, error) { return c.FromCover(&orphan, s) }, CoverOriginal, "", ErrNoCoverManga},
+		{"nil cover", "", func(c *CoverURLs, s CoverSize) (string, error) { return c.FromCover(nil, s) }, CoverOriginal, "", ErrNoCoverArt},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get(NewCoverURLs(tt.host), tt.size)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Fatalf("got %q, %v; want %q, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	if got, _ := CoverURL(mangaID, coverFile, Cover256); got != base+".256.jpg" {
+		t.Fatalf("CoverURL = %q", got)
+	}
+}
@@ -0,0 +1,417 @@
+package mangadex
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+var ErrChecksumMismatch = errors.New("page checksum mismatch")
+
+// DownloadProgress is passed to ChapterDownloader.OnProgress after every page
+// attempt. Err is set when the attempt failed.
+type DownloadProgress struct {
+	ChapterID openapi_types.UUID
+	Page      int
+	Total     int
+	Completed int
+	FileName  string
+	Quality   PageQuality
+	Bytes     int64
+	Attempt   int
+	Err       error
+}
+
+// DownloadedPage is a page written to disk.
+type DownloadedPage struct {
+	Index    int
+	Path     string
+	FileName string
+	Quality  PageQuality
+	Bytes    int64
+	SHA256   string
+}
+
+// ChapterDownload is the result of ChapterDownloader.Download.
+type ChapterDownload struct {
+	ChapterID openapi_types.UUID
+	Dir       string
+	Pages     []DownloadedPage
+}
+
+// ChapterDownloader fetches whole chapters through the at-home API.
+type ChapterDownloader struct {
+	Resolver *AtHomeResolver
+
+	// Concurrency is the number of pages fetched at once. Defaults to 4.
+	Concurrency int
+
+	// Retries is the number of extra attempts per page and quality. Each
+	// retry uses a freshly requested baseUrl. Defaults to 2; a negative
+	// value disables retries.
+	Retries int
+
+	// Quality defaults to QualityData.
+	Quality PageQuality
+
+	// DisableFallback stops falling back to data-saver images when the
+	// original quality cannot be fetched.
+	DisableFallback bool
+
+	// SkipExisting reuses pages already present in the target directory when
+	// their content matches the checksum in the page file name.
+	SkipExisting bool
+
+	OnProgress func(DownloadProgress)
+}
+
+// NewChapterDownloader creates a ChapterDownloader using client.
+func NewChapterDownloader(client ClientWithResponsesInterface) *ChapterDownloader {
+	return &ChapterDownloader{
+		Resolver:    NewAtHomeResolver(client),
+		Concurrency: 4,
+		Retries:     2,
+		Quality:     QualityData,
+	}
+}
+
+// Download writes every page of chapterID into dir as 001.ext, 002.ext, ...
+// Pages are written to a temporary file and renamed into place, so dir never
+// contains partial images.
+func (d *ChapterDownloader) Download(ctx context.Context, chapterID openapi_types.UUID, dir string) (*ChapterDownload, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	st := &downloadState{resolver: d.Resolver, chapterID: chapterID}
+	pages, err := d.Resolver.Pages(ctx, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	st.pages = pages
+
+	total := len(pages.FileNames(d.quality()))
+	if total == 0 {
+		total = len(pages.DataSaver)
+	}
+	if total == 0 {
+		return nil, ErrNoPages
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result := &ChapterDownload{ChapterID: chapterID, Dir: dir, Pages: make([]DownloadedPage, total)}
+	errs := make([]error, total)
+	jobs := make(chan int)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		completed int
+	)
+	for w := 0; w < d.concurrency(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				page, err := d.downloadPage(ctx, st, i, total, dir, func(p DownloadProgress) {
+					mu.Lock()
+					if p.Err == nil {
+						completed++
+					}
+					p.Completed = completed
+					mu.Unlock()
+					if d.OnProgress != nil {
+						d.OnProgress(p)
+					}
+				})
+				if err != nil {
+					errs[i] = fmt.Errorf("page %d: %w", i+1, err)
+					continue
+				}
+				result.Pages[i] = *page
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < total; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return result, err
+	}
+	return result, ctx.Err()
+}
+
+func (d *ChapterDownloader) downloadPage(
+	ctx context.Context,
+	st *downloadState,
+	index int,
+	total int,
+	dir string,
+	progress func(DownloadProgress),
+) (*DownloadedPage, error) {
+
+	if d.SkipExisting {
+		pages, _ := st.current()
+		if page := d.existingPage(pages, index, dir); page != nil {
+			progress(DownloadProgress{
+				ChapterID: st.chapterID,
+				Page:      index,
+				Total:     total,
+				FileName:  page.FileName,
+				Quality:   page.Quality,
+				Bytes:     page.Bytes,
+			})
+			return page, nil
+		}
+	}
+
+	var lastErr error
+	failedGen := -1
+	for _, quality := range d.qualities() {
+		for attempt := 0; attempt <= d.retries(); attempt++ {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if failedGen >= 0 {
+				// retry against a fresh baseUrl
+				if err := st.refresh(ctx, failedGen); err != nil {
+					return nil, err
+				}
+			}
+
+			pages, gen := st.current()
+			names := pages.FileNames(quality)
+			if index >= len(names) {
+				lastErr = fmt.Errorf("no %s file for page", quality)
+				break
+			}
+			name := names[index]
+
+			p := DownloadProgress{
+				ChapterID: st.chapterID,
+				Page:      index,
+				Total:     total,
+				FileName:  name,
+				Quality:   quality,
+				Attempt:   attempt,
+			}
+
+			page, err := d.fetchPage(ctx, pages, quality, index, name, dir)
+			if err == nil {
+				p.Bytes = page.Bytes
+				progress(p)
+				return page, nil
+			}
+
+			lastErr = err
+			failedGen = gen
+			p.Err = err
+			progress(p)
+		}
+	}
+	return nil, lastErr
+}
+
+func (d *ChapterDownloader) fetchPage(
+	ctx context.Context,
+	pages *ChapterPages,
+	quality PageQuality,
+	index int,
+	name string,
+	dir string,
+) (*DownloadedPage, error) {
+
+	u, err := pages.URL(quality, name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := d.Resolver.Fetch(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	if want := PageChecksum(name); want != "" && !strings.HasPrefix(digest, want) {
+		return nil, fmt.Errorf("%w: %s", ErrChecksumMismatch, name)
+	}
+
+	target := pagePath(dir, index, name)
+	if err := writeFileAtomic(target, data); err != nil {
+		return nil, err
+	}
+	return &DownloadedPage{
+		Index:    index,
+		Path:     target,
+		FileName: name,
+		Quality:  quality,
+		Bytes:    int64(len(data)),
+		SHA256:   digest,
+	}, nil
+}
+
+// existingPage returns the page at index if a previous run already wrote a
+// file that passes the checksum.
+func (d *ChapterDownloader) existingPage(pages *ChapterPages, index int, dir string) *DownloadedPage {
+	for _, quality := range d.qualities() {
+		names := pages.FileNames(quality)
+		if index >= len(names) {
+			continue
+		}
+		name := names[index]
+		target := pagePath(dir, index, name)
+		data, err := os.ReadFile(target)
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		digest := hex.EncodeToString(sum[:])
+		if want := PageChecksum(name); want == "" || !strings.HasPrefix(digest, want) {
+			continue
+		}
+		return &DownloadedPage{
+			Index:    index,
+			Path:     target,
+			FileName: name,
+			Quality:  quality,
+			Bytes:    int64(len(data)),
+			SHA256:   digest,
+		}
+	}
+	return nil
+}
+
+func pagePath(dir string, index int, name string) string {
+	return filepath.Join(dir, fmt.Sprintf("%03d%s", index+1, path.Ext(name)))
+}
+
+// PageChecksum returns the lower-case SHA-256 hex prefix embedded in a
+// MangaDex page file name such as "1-9d4b...e2.png", or "" if there is none.
+func PageChecksum(fileName string) string {
+	base := strings.TrimSuffix(fileName, path.Ext(fileName))
+	i := strings.LastIndex(base, "-")
+	if i < 0 {
+		return ""
+	}
+	sum := strings.ToLower(base[i+1:])
+	if len(sum) < 8 || len(sum) > sha256.Size*2 {
+		return ""
+	}
+	for _, r := range sum {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return ""
+		}
+	}
+	return sum
+}
+
+func writeFileAtomic(target string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+func (d *ChapterDownloader) qualities() []PageQuality {
+	q := d.quality()
+	if q == QualityData && !d.DisableFallback {
+		return []PageQuality{QualityData, QualityDataSaver}
+	}
+	return []PageQuality{q}
+}
+
+func (d *ChapterDownloader) quality() PageQuality {
+	if d.Quality == "" {
+		return QualityData
+	}
+	return d.Quality
+}
+
+func (d *ChapterDownloader) concurrency() int {
+	if d.Concurrency <= 0 {
+		return 4
+	}
+	return d.Concurrency
+}
+
+func (d *ChapterDownloader) retries() int {
+	switch {
+	case d.Retries < 0:
+		return 0
+	case d.Retries == 0:
+		return 2
+	}
+	return d.Retries
+}
+
+// downloadState shares the current baseUrl between workers so that a burst
+// of failures against one baseUrl only triggers a single refresh.
+type downloadState struct {
+	resolver  *AtHomeResolver
+	chapterID openapi_types.UUID
+
+	mu    sync.Mutex
+	pages *ChapterPages
+	gen   int
+}
+
+func (s *downloadState) current() (*ChapterPages, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pages, s.gen
+}
+
+func (s *downloadState) refresh(ctx context.Context, gen int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.gen != gen {
+		return nil
+	}
+	pages, err := s.resolver.Refresh(ctx, s.chapterID)
+	if err != nil {
+		return err
+	}
+	s.pages = pages
+	s.gen++
+	return nil
+}
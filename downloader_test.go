@@ -0,0 +1,182 @@
+package mangadex
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// pageName names an image the way MangaDex does, with the SHA-256 of its
+// content after the page number.
+func pageName(n int, data []byte) string {
+	return fmt.Sprintf("%d-%x.png", n, sha256.Sum256(data))
+}
+
+func TestPageChecksum(t *testing.T) {
+	full := fmt.Sprintf("%x", sha256.Sum256([]byte("page")))
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"1-" + full + ".png", full},
+		{"12-9D4B21E2.jpg", "9d4b21e2"},
+		{"x1-abc-9d4b21e2.png", "9d4b21e2"},
+		{"1-9d4b21e.png", ""},
+		{"1-" + full + "00.png", ""},
+		{"1-9d4b21zz.png", ""},
+		{"9d4b21e2.png", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PageChecksum(tt.name); got != tt.want {
+				t.Fatalf("PageChecksum = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChapterDownloader(t *testing.T) {
+	one, two := []byte("page one"), []byte("page two")
+	small1, small2 := []byte("small one"), []byte("small two")
+
+	tests := []struct {
+		name    string
+		setup   func(f *fakeAtHome, d *ChapterDownloader)
+		wantErr string
+		want    [][]byte
+		quality PageQuality
+	}{
+		{
+			name:    "original quality",
+			setup:   func(*fakeAtHome, *ChapterDownloader) {},
+			want:    [][]byte{one, two},
+			quality: QualityData,
+		},
+		{
+			name:    "falls back to data saver",
+			setup:   func(f *fakeAtHome, _ *ChapterDownloader) { f.Break(QualityData) },
+			want:    [][]byte{small1, small2},
+			quality: QualityDataSaver,
+		},
+		{
+			name: "checksum mismatch falls back",
+			setup: func(f *fakeAtHome, _ *ChapterDownloader) {
+				for name := range f.Data {
+					f.Data[name] = []byte("corrupt")
+				}
+			},
+			want:    [][]byte{small1, small2},
+			quality: QualityDataSaver,
+		},
+		{
+			name: "fallback disabled",
+			setup: func(f *fakeAtHome, d *ChapterDownloader) {
+				f.Break(QualityData)
+				d.DisableFallback = true
+			},
+			wantErr: "502 Bad Gateway",
+		},
+		{
+			name: "checksum mismatch without fallback",
+			setup: func(f *fakeAtHome, d *ChapterDownloader) {
+				for name := range f.Data {
+					f.Data[name] = []byte("corrupt")
+				}
+				d.DisableFallback = true
+			},
+			wantErr: ErrChecksumMismatch.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeAtHome(t)
+			f.Data[pageName(1, one)] = one
+			f.Data[pageName(2, two)] = two
+			f.DataSaver[pageName(1, small1)] = small1
+			f.DataSaver[pageName(2, small2)] = small2
+			d := NewChapterDownloader(nil)
+			d.Resolver = f.resolver(t, false)
+			d.Retries = 1
+			tt.setup(f, d)
+
+			dl, err := d.Download(context.Background(), uuid.New(), t.TempDir())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, page := range dl.Pages {
+				data, err := os.ReadFile(page.Path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(data) != string(tt.want[i]) || page.Quality != tt.quality {
+					t.Fatalf("page %d = %q (%s), want %q (%s)", i+1, data, page.Quality, tt.want[i], tt.quality)
+				}
+				if want := fmt.Sprintf("%x", sha256.Sum256(data)); page.SHA256 != want {
+					t.Fatalf("page %d sha256 = %s", i+1, page.SHA256)
+				}
+			}
+		})
+	}
+}
+
+func TestChapterDownloaderSkipExisting(t *testing.T) {
+	f := newFakeAtHome(t)
+	one, two := []byte("page one"), []byte("page two")
+	f.Data[pageName(1, one)] = one
+	f.Data[pageName(2, two)] = two
+	d := NewChapterDownloader(nil)
+	d.Resolver = f.resolver(t, false)
+	d.SkipExisting = true
+	ctx := context.Background()
+	dir := t.TempDir()
+	chapter := uuid.New()
+
+	first, err := d.Download(ctx, chapter, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a damaged file is fetched again, the intact one is kept
+	if err := os.WriteFile(first.Pages[1].Path, []byte("truncated"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f.Break(QualityData)
+	f.Break(QualityDataSaver)
+	_, err = d.Download(ctx, chapter, dir)
+	if err == nil || strings.Contains(err.Error(), "page 1:") || !strings.Contains(err.Error(), "page 2:") {
+		t.Fatalf("err = %v, want only page 2 fetched again", err)
+	}
+	if data, _ := os.ReadFile(first.Pages[0].Path); string(data) != string(one) {
+		t.Fatalf("intact page = %q", data)
+	}
+}
+
+func TestChapterDownloaderDefaults(t *testing.T) {
+	tests := []struct {
+		retries, want int
+	}{
+		{0, 2},
+		{-1, 0},
+		{5, 5},
+	}
+	for _, tt := range tests {
+		d := &ChapterDownloader{Retries: tt.retries}
+		if got := d.retries(); got != tt.want {
+			t.Errorf("retries() with Retries=%d = %d, want %d", tt.retries, got, tt.want)
+		}
+	}
+	if d := (&ChapterDownloader{}); d.concurrency() != 4 {
+		t.Errorf("concurrency() = %d, want 4", d.concurrency())
+	}
+}
@@ -0,0 +1,45 @@
+package mangadex
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned when the API answers with an unexpected status.
+type APIError struct {
+	StatusCode int
+	Errors     []Error
+}
+
+func (e *APIError) Error() string {
+	var details []string
+	for _, err := range e.Errors {
+		switch {
+		case err.Detail != nil && *err.Detail != "":
+			details = append(details, *err.Detail)
+		case err.Title != nil && *err.Title != "":
+			details = append(details, *err.Title)
+		}
+	}
+	msg := fmt.Sprintf("mangadex: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if len(details) > 0 {
+		msg += ": " + strings.Join(details, "; ")
+	}
+	return msg
+}
+
+// newAPIError builds an APIError from a response and whichever error body
+// the generated client decoded for it.
+func newAPIError(resp *http.Response, bodies ...*ErrorResponse) *APIError {
+	e := &APIError{}
+	if resp != nil {
+		e.StatusCode = resp.StatusCode
+	}
+	for _, b := range bodies {
+		if b != nil && b.Errors != nil {
+			e.Errors = append(e.Errors, *b.Errors...)
+		}
+	}
+	return e
+}
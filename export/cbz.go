@@ -0,0 +1,255 @@
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/Seann-Moser/mangadex"
+	"github.com/Seann-Moser/mangadex/chapters"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// Exporter packages chapters fetched through the at-home API into archives.
+type Exporter struct {
+	Client     mangadex.ClientWithResponsesInterface
+	Downloader *mangadex.ChapterDownloader
+	Localizer  *mangadex.Localizer
+
+	// Covers builds cover art URLs. Defaults to the public uploads host.
+	Covers *mangadex.CoverURLs
+
+	// HTTPClient fetches cover art. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// WorkDir holds downloaded pages until they are archived. Defaults to
+	// os.TempDir().
+	WorkDir string
+}
+
+// NewExporter creates an Exporter using client.
+func NewExporter(client mangadex.ClientWithResponsesInterface) *Exporter {
+	return &Exporter{
+		Client:     client,
+		Downloader: mangadex.NewChapterDownloader(client),
+		Localizer:  defaultLocalizer(),
+	}
+}
+
+// ChapterCBZ writes a single chapter as a CBZ archive to w.
+func (e *Exporter) ChapterCBZ(ctx context.Context, chapterID openapi_types.UUID, w io.Writer) error {
+	chapter, err := e.chapter(ctx, chapterID)
+	if err != nil {
+		return err
+	}
+	manga, err := e.mangaOf(ctx, chapter)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp(e.WorkDir, "mangadex-chapter-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	dl, err := e.Downloader.Download(ctx, chapterID, dir)
+	if err != nil {
+		return err
+	}
+
+	info := NewComicInfo(manga, chapter, e.Localizer)
+	return WriteCBZ(w, info, archivePages(dl, ""))
+}
+
+// VolumeCBZ writes every chapter of a volume, as listed by the manga
+// aggregate, into a single CBZ archive. volume is the aggregate key, e.g.
+// "3" or "none". languages restricts the aggregate to the given translated
+// languages and should normally hold exactly one entry.
+func (e *Exporter) VolumeCBZ(ctx context.Context, mangaID openapi_types.UUID, volume string, languages []string, w io.Writer) error {
+	manga, err := e.manga(ctx, mangaID)
+	if err != nil {
+		return err
+	}
+	ids, err := e.volumeChapters(ctx, mangaID, volume, languages)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp(e.WorkDir, "mangadex-volume-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		pages []ArchivePage
+		first *mangadex.Chapter
+	)
+	for i, id := range ids {
+		chapter, err := e.chapter(ctx, id)
+		if err != nil {
+			return err
+		}
+		if first == nil {
+			first = chapter
+		}
+		dl, err := e.Downloader.Download(ctx, id, filepath.Join(dir, strconv.Itoa(i)))
+		if err != nil {
+			return err
+		}
+		pages = append(pages, archivePages(dl, fmt.Sprintf("c%03d-", i+1))...)
+	}
+
+	info := NewComicInfo(manga, nil, e.Localizer)
+	if volume != "none" {
+		info.Volume = volume
+		info.Number = volume
+		info.Title = "Volume " + volume
+	}
+	if first != nil && first.Attributes != nil {
+		info.LanguageISO = deref(first.Attributes.TranslatedLanguage)
+	}
+	return WriteCBZ(w, info, pages)
+}
+
+// ArchivePage is an image file to be stored in an archive under Name.
+type ArchivePage struct {
+	Name string
+	Path string
+	Size int64
+}
+
+func archivePages(dl *mangadex.ChapterDownload, prefix string) []ArchivePage {
+	out := make([]ArchivePage, 0, len(dl.Pages))
+	for _, p := range dl.Pages {
+		out = append(out, ArchivePage{
+			Name: prefix + filepath.Base(p.Path),
+			Path: p.Path,
+			Size: p.Bytes,
+		})
+	}
+	return out
+}
+
+// WriteCBZ writes a CBZ archive holding ComicInfo.xml followed by pages in
+// order. Images are stored uncompressed.
+func WriteCBZ(w io.Writer, info *ComicInfo, pages []ArchivePage) error {
+	sizes := make([]int64, len(pages))
+	for i, p := range pages {
+		sizes[i] = p.Size
+	}
+	info.SetPages(sizes)
+
+	doc, err := info.Marshal()
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	f, err := zw.Create("ComicInfo.xml")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(doc); err != nil {
+		return err
+	}
+
+	for _, p := range pages {
+		if err := addFile(zw, p.Name, p.Path, zip.Store); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func addFile(zw *zip.Writer, name string, src string, method uint16) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: method})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	return err
+}
+
+func (e *Exporter) chapter(ctx context.Context, id openapi_types.UUID) (*mangadex.Chapter, error) {
+	includes := mangadex.ReferenceExpansionChapter{mangadex.RelationshipManga, mangadex.RelationshipScanlationGroup}
+	resp, err := e.Client.GetChapterIdWithResponse(ctx, id, &mangadex.GetChapterIdParams{Includes: &includes})
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil || resp.JSON200.Data == nil {
+		return nil, fmt.Errorf("chapter %s: %s", id, resp.Status())
+	}
+	return resp.JSON200.Data, nil
+}
+
+func (e *Exporter) manga(ctx context.Context, id openapi_types.UUID) (*mangadex.Manga, error) {
+	includes := mangadex.ReferenceExpansionManga{mangadex.RelationshipAuthor, mangadex.RelationshipArtist, mangadex.RelationshipCoverArt}
+	resp, err := e.Client.GetMangaIdWithResponse(ctx, id, &mangadex.GetMangaIdParams{Includes: &includes})
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil || resp.JSON200.Data == nil {
+		return nil, fmt.Errorf("manga %s: %s", id, resp.Status())
+	}
+	return resp.JSON200.Data, nil
+}
+
+func (e *Exporter) mangaOf(ctx context.Context, chapter *mangadex.Chapter) (*mangadex.Manga, error) {
+	rel, err := chapter.Manga()
+	if err != nil {
+		return nil, err
+	}
+	if rel == nil {
+		return nil, fmt.Errorf("chapter has no manga relationship")
+	}
+	return e.manga(ctx, rel.Id)
+}
+
+// volumeChapters returns the chapter ids of a volume in reading order.
+func (e *Exporter) volumeChapters(ctx context.Context, mangaID openapi_types.UUID, volume string, languages []string) ([]openapi_types.UUID, error) {
+	params := &mangadex.GetMangaAggregateParams{}
+	if len(languages) > 0 {
+		params.TranslatedLanguage = &languages
+	}
+	resp, err := e.Client.GetMangaAggregateWithResponse(ctx, mangaID, params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil || resp.JSON200.Volumes == nil {
+		return nil, fmt.Errorf("manga %s aggregate: %s", mangaID, resp.Status())
+	}
+	vol, ok := (*resp.JSON200.Volumes)[volume]
+	if !ok || vol.Chapters == nil {
+		return nil, fmt.Errorf("manga %s has no volume %q", mangaID, volume)
+	}
+
+	keys := make([]string, 0, len(*vol.Chapters))
+	for k := range *vol.Chapters {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return chapters.Compare(chapters.Parse(keys[i]), chapters.Parse(keys[j])) < 0
+	})
+
+	ids := make([]openapi_types.UUID, 0, len(keys))
+	for _, k := range keys {
+		if c := (*vol.Chapters)[k]; c.Id != nil {
+			ids = append(ids, *c.Id)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,330 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/Seann-Moser/mangadex"
+	"github.com/google/uuid"
+)
+
+var (
+	testMangaID = uuid.MustParse("a1c7c817-4e59-43b7-9365-09675a149a6f")
+	testGroupID = uuid.MustParse("5c5ab3f3-8a1a-4c8e-8c3e-6d4e0c8e7b2d")
+)
+
+type fakeChapter struct {
+	ID     uuid.UUID
+	Volume string
+	Number string
+	Pages  [][]byte
+}
+
+// fakeAPI serves a manga with its aggregate, chapters, covers and the
+// at-home pages of every chapter.
+type fakeAPI struct {
+	*httptest.Server
+
+	Manga    mangadex.Manga
+	Chapters []fakeChapter
+	Covers   map[string][]byte // volume -> image
+}
+
+func newFakeAPI(t *testing.T) *fakeAPI {
+	t.Helper()
+	f := &fakeAPI{
+		Manga: mangadex.Manga{
+			Id: &testMangaID,
+			Attributes: &mangadex.MangaAttributes{
+				Title:            &mangadex.LocalizedString{"en": "Berserk"},
+				OriginalLanguage: mangadex.String("ja"),
+			},
+			Relationships: &[]mangadex.Relationship{
+				relationship(mangadex.RelationshipAuthor, uuid.New(), map[string]any{"name": "Miura, Kentaro"}),
+				relationship(mangadex.RelationshipAuthor, uuid.New(), map[string]any{"name": "Studio Gaga"}),
+				relationship(mangadex.RelationshipArtist, uuid.New(), map[string]any{"name": "Miura, Kentaro"}),
+			},
+		},
+		Covers: map[string][]byte{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /manga/{id}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, mangadex.MangaResponse{Data: &f.Manga})
+	})
+	mux.HandleFunc("GET /manga/{id}/aggregate", f.aggregate)
+	mux.HandleFunc("GET /chapter/{id}", f.chapter)
+	mux.HandleFunc("GET /at-home/server/{id}", f.atHome)
+	mux.HandleFunc("GET /node/data/{hash}/{name}", f.page)
+	mux.HandleFunc("GET /cover", f.coverList)
+	mux.HandleFunc("GET /covers/{manga}/{file}", f.cover)
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func relationship(relType string, id uuid.UUID, attrs map[string]any) mangadex.Relationship {
+	rel := mangadex.Relationship{Id: &id, Type: mangadex.String(relType)}
+	if attrs != nil {
+		rel.Attributes = &attrs
+	}
+	return rel
+}
+
+// AddChapter adds a chapter of two pages to the manga.
+func (f *fakeAPI) AddChapter(volume, number string) uuid.UUID {
+	id := uuid.New()
+	f.Chapters = append(f.Chapters, fakeChapter{
+		ID:     id,
+		Volume: volume,
+		Number: number,
+		Pages:  [][]byte{[]byte("ch" + number + " p1"), []byte("ch" + number + " p2")},
+	})
+	return id
+}
+
+func (f *fakeAPI) find(id string) (fakeChapter, bool) {
+	for _, c := range f.Chapters {
+		if c.ID.String() == id {
+			return c, true
+		}
+	}
+	return fakeChapter{}, false
+}
+
+func (f *fakeAPI) aggregate(w http.ResponseWriter, r *http.Request) {
+	volumes := map[string]any{}
+	for _, c := range f.Chapters {
+		v, _ := volumes[c.Volume].(map[string]any)
+		if v == nil {
+			v = map[string]any{"volume": c.Volume, "chapters": map[string]any{}}
+			volumes[c.Volume] = v
+		}
+		v["chapters"].(map[string]any)[c.Number] = map[string]any{"chapter": c.Number, "id": c.ID}
+	}
+	writeJSON(w, map[string]any{"result": "ok", "volumes": volumes})
+}
+
+func (f *fakeAPI) chapter(w http.ResponseWriter, r *http.Request) {
+	c, ok := f.find(r.PathValue("id"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, mangadex.ChapterResponse{Data: &mangadex.Chapter{
+		Id: &c.ID,
+		Attributes: &mangadex.ChapterAttributes{
+			Volume:             mangadex.String(c.Volume),
+			Chapter:            mangadex.String(c.Number),
+			Title:              mangadex.String("The Black Swordsman"),
+			TranslatedLanguage: mangadex.String("en"),
+			Pages:              mangadex.Int(len(c.Pages)),
+			PublishAt:          mangadex.String("2024-04-01T08:00:00+00:00"),
+		},
+		Relationships: &[]mangadex.Relationship{
+			relationship(mangadex.RelationshipManga, testMangaID, nil),
+			relationship(mangadex.RelationshipScanlationGroup, testGroupID, map[string]any{"name": "Evil Genius"}),
+		},
+	}})
+}
+
+func (f *fakeAPI) atHome(w http.ResponseWriter, r *http.Request) {
+	c, ok := f.find(r.PathValue("id"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	names := make([]string, len(c.Pages))
+	for i, p := range c.Pages {
+		names[i] = fmt.Sprintf("%d-%x.png", i+1, sha256.Sum256(p))
+	}
+	writeJSON(w, map[string]any{
+		"result":  "ok",
+		"baseUrl": f.URL + "/node",
+		"chapter": map[string]any{"hash": c.ID.String(), "data": names, "dataSaver": []string{}},
+	})
+}
+
+func (f *fakeAPI) page(w http.ResponseWriter, r *http.Request) {
+	c, ok := f.find(r.PathValue("hash"))
+	var n int
+	fmt.Sscanf(r.PathValue("name"), "%d-", &n)
+	if !ok || n < 1 || n > len(c.Pages) {
+		http.NotFound(w, r)
+		return
+	}
+	_, _ = w.Write(c.Pages[n-1])
+}
+
+func (f *fakeAPI) coverList(w http.ResponseWriter, r *http.Request) {
+	var data []mangadex.Cover
+	for volume := range f.Covers {
+		id := uuid.New()
+		data = append(data, mangadex.Cover{
+			Id: &id,
+			Attributes: &mangadex.CoverAttributes{
+				Volume:   mangadex.String(volume),
+				FileName: mangadex.String("v" + volume + ".png"),
+			},
+			Relationships: &[]mangadex.Relationship{relationship(mangadex.RelationshipManga, testMangaID, nil)},
+		})
+	}
+	writeJSON(w, mangadex.CoverList{Data: &data, Total: mangadex.Int(len(data))})
+}
+
+func (f *fakeAPI) cover(w http.ResponseWriter, r *http.Request) {
+	for volume, data := range f.Covers {
+		if r.PathValue("file") == "v"+volume+".png" {
+			_, _ = w.Write(data)
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (f *fakeAPI) exporter(t *testing.T) *Exporter {
+	t.Helper()
+	client, err := mangadex.NewClientWithResponses(f.URL, mangadex.WithHTTPClient(f.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewExporter(client)
+	e.Downloader.Resolver.HTTPClient = f.Client()
+	e.Downloader.Resolver.DisableReports = true
+	e.Covers = mangadex.NewCoverURLs(f.URL)
+	e.HTTPClient = f.Client()
+	e.WorkDir = t.TempDir()
+	return e
+}
+
+// readZip returns the entries of an archive in order.
+func readZip(t *testing.T, data []byte) []*zip.File {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return zr.File
+}
+
+func readEntry(t *testing.T, f *zip.File) []byte {
+	t.Helper()
+	rc, err := f.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func entryNames(files []*zip.File) []string {
+	out := make([]string, len(files))
+	for i, f := range files {
+		out[i] = f.Name
+	}
+	return out
+}
+
+func TestChapterCBZ(t *testing.T) {
+	f := newFakeAPI(t)
+	id := f.AddChapter("1", "3")
+	var buf bytes.Buffer
+	if err := f.exporter(t).ChapterCBZ(context.Background(), id, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	files := readZip(t, buf.Bytes())
+	if want := []string{"ComicInfo.xml", "001.png", "002.png"}; !slices.Equal(entryNames(files), want) {
+		t.Fatalf("entries = %v, want %v", entryNames(files), want)
+	}
+	for i, file := range files[1:] {
+		if file.Method != zip.Store {
+			t.Errorf("%s compressed with method %d", file.Name, file.Method)
+		}
+		if got := readEntry(t, file); !bytes.Equal(got, f.Chapters[0].Pages[i]) {
+			t.Errorf("%s = %q", file.Name, got)
+		}
+	}
+
+	var info ComicInfo
+	if err := xml.Unmarshal(readEntry(t, files[0]), &info); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		field, got, want string
+	}{
+		{"Series", info.Series, "Berserk"},
+		{"Title", info.Title, "The Black Swordsman"},
+		{"Number", info.Number, "3"},
+		{"Volume", info.Volume, "1"},
+		{"Writer", info.Writer, "Miura, Kentaro, Studio Gaga"},
+		{"Penciller", info.Penciller, "Miura, Kentaro"},
+		{"Translator", info.Translator, "Evil Genius"},
+		{"LanguageISO", info.LanguageISO, "en"},
+		{"Manga", info.Manga, "YesAndRightToLeft"},
+		{"PageCount", fmt.Sprint(info.PageCount), "2"},
+		{"Date", fmt.Sprintf("%d-%02d-%02d", info.Year, info.Month, info.Day), "2024-04-01"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.field, tt.got, tt.want)
+		}
+	}
+	if info.Pages == nil || len(info.Pages.Page) != 2 || info.Pages.Page[0].Type != "FrontCover" || info.Pages.Page[1].ImageSize != int64(len(f.Chapters[0].Pages[1])) {
+		t.Errorf("Pages = %+v", info.Pages)
+	}
+}
+
+func TestVolumeCBZ(t *testing.T) {
+	f := newFakeAPI(t)
+	f.AddChapter("2", "10")
+	f.AddChapter("2", "9.5")
+	f.AddChapter("2", "9")
+	f.AddChapter("3", "11")
+	var buf bytes.Buffer
+	if err := f.exporter(t).VolumeCBZ(context.Background(), testMangaID, "2", []string{"en"}, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	files := readZip(t, buf.Bytes())
+	want := []string{"ComicInfo.xml", "c001-001.png", "c001-002.png", "c002-001.png", "c002-002.png", "c003-001.png", "c003-002.png"}
+	if !slices.Equal(entryNames(files), want) {
+		t.Fatalf("entries = %v, want %v", entryNames(files), want)
+	}
+	// chapters are ordered by number, not as the aggregate lists them
+	for i, number := range []string{"9", "9.5", "10"} {
+		if got := string(readEntry(t, files[1+2*i])); got != "ch"+number+" p1" {
+			t.Errorf("chapter %d starts with %q, want ch%s", i+1, got, number)
+		}
+	}
+
+	var info ComicInfo
+	if err := xml.Unmarshal(readEntry(t, files[0]), &info); err != nil {
+		t.Fatal(err)
+	}
+	if info.Title != "Volume 2" || info.Number != "2" || info.Volume != "2" || info.PageCount != 6 || info.LanguageISO != "en" {
+		t.Errorf("ComicInfo = %+v", info)
+	}
+
+	if err := f.exporter(t).VolumeCBZ(context.Background(), testMangaID, "7", nil, io.Discard); err == nil {
+		t.Error("missing volume exported")
+	}
+}
@@ -0,0 +1,207 @@
+package export
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+
+	"github.com/Seann-Moser/mangadex"
+)
+
+// MangadexWebURL is used to build ComicInfo Web links.
+const MangadexWebURL = "https://mangadex.org"
+
+// ComicInfo is the ComicInfo.xml document read by Komga, Kavita and most
+// comic readers. Field order follows the ComicInfo v2 schema.
+type ComicInfo struct {
+	XMLName         xml.Name    `xml:"ComicInfo"`
+	XMLNSXSI        string      `xml:"xmlns:xsi,attr,omitempty"`
+	XMLNSXSD        string      `xml:"xmlns:xsd,attr,omitempty"`
+	Title           string      `xml:"Title,omitempty"`
+	Series          string      `xml:"Series,omitempty"`
+	Number          string      `xml:"Number,omitempty"`
+	Count           int         `xml:"Count,omitempty"`
+	Volume          string      `xml:"Volume,omitempty"`
+	Summary         string      `xml:"Summary,omitempty"`
+	Year            int         `xml:"Year,omitempty"`
+	Month           int         `xml:"Month,omitempty"`
+	Day             int         `xml:"Day,omitempty"`
+	Writer          string      `xml:"Writer,omitempty"`
+	Penciller       string      `xml:"Penciller,omitempty"`
+	Translator      string      `xml:"Translator,omitempty"`
+	Genre           string      `xml:"Genre,omitempty"`
+	Tags            string      `xml:"Tags,omitempty"`
+	Web             string      `xml:"Web,omitempty"`
+	PageCount       int         `xml:"PageCount,omitempty"`
+	LanguageISO     string      `xml:"LanguageISO,omitempty"`
+	Manga           string      `xml:"Manga,omitempty"`
+	ScanInformation string      `xml:"ScanInformation,omitempty"`
+	AgeRating       string      `xml:"AgeRating,omitempty"`
+	Pages           *ComicPages `xml:"Pages,omitempty"`
+}
+
+// ComicPages lists the pages of the archive.
+type ComicPages struct {
+	Page []ComicPage `xml:"Page"`
+}
+
+// ComicPage describes a single page of the archive.
+type ComicPage struct {
+	Image     int    `xml:"Image,attr"`
+	Type      string `xml:"Type,attr,omitempty"`
+	ImageSize int64  `xml:"ImageSize,attr,omitempty"`
+}
+
+// NewComicInfo builds a ComicInfo from a manga and, optionally, one of its
+// chapters. manga should be fetched with includes[]=author&includes[]=artist
+// and chapter with includes[]=scanlation_group to fill in credits.
+func NewComicInfo(manga *mangadex.Manga, chapter *mangadex.Chapter, loc *mangadex.Localizer) *ComicInfo {
+	if loc == nil {
+		loc = defaultLocalizer()
+	}
+	info := &ComicInfo{
+		XMLNSXSI: "http://www.w3.org/2001/XMLSchema-instance",
+		XMLNSXSD: "http://www.w3.org/2001/XMLSchema",
+	}
+
+	if manga != nil {
+		info.Series, _ = loc.Title(manga)
+		info.Summary, _ = loc.Description(manga)
+		if manga.Id != nil {
+			info.Web = MangadexWebURL + "/title/" + manga.Id.String()
+		}
+		if authors, err := manga.Authors(); err == nil {
+			info.Writer = strings.Join(personNames(authors), ", ")
+		}
+		if artists, err := manga.Artists(); err == nil {
+			info.Penciller = strings.Join(personNames(artists), ", ")
+		}
+		if a := manga.Attributes; a != nil {
+			genres, others := tagNames(a.Tags, loc)
+			info.Genre, info.Tags = strings.Join(genres, ", "), strings.Join(others, ", ")
+			if a.Year != nil {
+				info.Year = *a.Year
+			}
+			if a.OriginalLanguage != nil && *a.OriginalLanguage == "ja" {
+				info.Manga = "YesAndRightToLeft"
+			}
+			if a.ContentRating != nil {
+				info.AgeRating = ageRating(*a.ContentRating)
+			}
+		}
+	}
+
+	if chapter != nil {
+		if chapter.Id != nil {
+			// Web holds space-separated URLs: the title, then the chapter
+			info.Web = strings.TrimSpace(info.Web + " " + MangadexWebURL + "/chapter/" + chapter.Id.String())
+		}
+		if groups, err := chapter.ScanlationGroups(); err == nil {
+			var names []string
+			for _, g := range groups {
+				if g.Attributes != nil && g.Attributes.Name != nil {
+					names = append(names, *g.Attributes.Name)
+				}
+			}
+			info.Translator = strings.Join(names, ", ")
+			info.ScanInformation = info.Translator
+		}
+		if a := chapter.Attributes; a != nil {
+			info.Title = deref(a.Title)
+			info.Number = deref(a.Chapter)
+			info.Volume = deref(a.Volume)
+			info.LanguageISO = deref(a.TranslatedLanguage)
+			if a.Pages != nil {
+				info.PageCount = *a.Pages
+			}
+			if a.PublishAt != nil {
+				if t, err := time.Parse(time.RFC3339, *a.PublishAt); err == nil {
+					info.Year, info.Month, info.Day = t.Year(), int(t.Month()), t.Day()
+				}
+			}
+		}
+	}
+	return info
+}
+
+// SetPages fills in PageCount and the page list, marking the first page as
+// the front cover.
+func (c *ComicInfo) SetPages(sizes []int64) {
+	c.PageCount = len(sizes)
+	c.Pages = &ComicPages{}
+	for i, size := range sizes {
+		p := ComicPage{Image: i, ImageSize: size}
+		if i == 0 {
+			p.Type = "FrontCover"
+		}
+		c.Pages.Page = append(c.Pages.Page, p)
+	}
+}
+
+// Marshal encodes the document with an XML header.
+func (c *ComicInfo) Marshal() ([]byte, error) {
+	b, err := xml.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+func defaultLocalizer() *mangadex.Localizer {
+	return mangadex.NewLocalizer("en", "ja-ro", mangadex.LanguageOriginal)
+}
+
+// personNames returns the names of expanded authors or artists.
+func personNames(people []mangadex.Expanded[mangadex.AuthorAttributes]) []string {
+	var names []string
+	for _, p := range people {
+		if p.Attributes != nil && p.Attributes.Name != nil {
+			names = append(names, *p.Attributes.Name)
+		}
+	}
+	return names
+}
+
+// tagNames splits manga tags into genres and everything else.
+func tagNames(tags *[]mangadex.Tag, loc *mangadex.Localizer) (genres []string, others []string) {
+	if tags == nil {
+		return nil, nil
+	}
+	var g, o []string
+	for _, t := range *tags {
+		if t.Attributes == nil || t.Attributes.Name == nil {
+			continue
+		}
+		name, _ := loc.String(*t.Attributes.Name)
+		if name == "" {
+			continue
+		}
+		if t.Attributes.Group != nil && *t.Attributes.Group == mangadex.Genre {
+			g = append(g, name)
+		} else {
+			o = append(o, name)
+		}
+	}
+	return g, o
+}
+
+func ageRating(r mangadex.MangaAttributesContentRating) string {
+	switch r {
+	case mangadex.MangaAttributesContentRatingSafe:
+		return "Everyone"
+	case mangadex.MangaAttributesContentRatingSuggestive:
+		return "Teen"
+	case mangadex.MangaAttributesContentRatingErotica:
+		return "Mature 17+"
+	case mangadex.MangaAttributesContentRatingPornographic:
+		return "Adults Only 18+"
+	}
+	return ""
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,34 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/Seann-Moser/mangadex"
+	"github.com/google/uuid"
+)
+
+func TestNewComicInfoWeb(t *testing.T) {
+	mangaID := uuid.MustParse("a1c7c817-4e59-43b7-9365-09675a149a6f")
+	chapterID := uuid.MustParse("0d2a6d3c-7d6c-4c0e-8c8e-1f2a3b4c5d6e")
+	manga := &mangadex.Manga{Id: &mangaID}
+	chapter := &mangadex.Chapter{Id: &chapterID}
+
+	tests := []struct {
+		name    string
+		manga   *mangadex.Manga
+		chapter *mangadex.Chapter
+		want    string
+	}{
+		{"manga", manga, nil, MangadexWebURL + "/title/" + mangaID.String()},
+		{"chapter", nil, chapter, MangadexWebURL + "/chapter/" + chapterID.String()},
+		{"both", manga, chapter, MangadexWebURL + "/title/" + mangaID.String() + " " + MangadexWebURL + "/chapter/" + chapterID.String()},
+		{"neither", &mangadex.Manga{}, &mangadex.Chapter{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewComicInfo(tt.manga, tt.chapter, nil).Web; got != tt.want {
+				t.Fatalf("Web = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,482 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Seann-Moser/mangadex"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// EPUBOptions selects what goes into an EPUB.
+type EPUBOptions struct {
+	// Volumes are aggregate volume keys, e.g. "1", "2" or "none", in the
+	// order they should appear in the book.
+	Volumes []string
+
+	// Language is the translated language to use. Defaults to "en".
+	Language string
+}
+
+// defaultPageSize is used for the fixed-layout viewport when an image's
+// dimensions cannot be read.
+var defaultPageSize = image.Point{X: 1000, Y: 1500}
+
+type epubPage struct {
+	ID     string
+	Href   string
+	Image  string
+	Media  string
+	Width  int
+	Height int
+}
+
+type epubChapter struct {
+	Title string
+	Href  string
+}
+
+type epubBook struct {
+	ID          string
+	Title       string
+	Language    string
+	Description string
+	Creators    []string
+	Subjects    []string
+	Modified    string
+	RTL         bool
+	Cover       *epubPage
+	Pages       []epubPage
+	Chapters    []epubChapter
+}
+
+// EPUB writes an EPUB 3 fixed-layout book of the selected volumes of a
+// manga to w. Every page becomes its own XHTML document, the navigation
+// document holds one entry per chapter and the page progression is
+// right-to-left when the manga's original language is Japanese.
+func (e *Exporter) EPUB(ctx context.Context, mangaID openapi_types.UUID, opts EPUBOptions, w io.Writer) error {
+	if len(opts.Volumes) == 0 {
+		return fmt.Errorf("no volumes selected")
+	}
+	lang := opts.Language
+	if lang == "" {
+		lang = "en"
+	}
+
+	manga, err := e.manga(ctx, mangaID)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp(e.WorkDir, "mangadex-epub-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	book := e.newEPUBBook(manga, lang, opts.Volumes)
+
+	cover, err := e.downloadCover(ctx, manga, opts.Volumes[0], dir)
+	if err != nil {
+		return err
+	}
+	book.Cover = cover
+
+	files := map[string]string{}
+	if cover != nil {
+		files[cover.Image] = filepath.Join(dir, path.Base(cover.Image))
+	}
+
+	n := 0
+	for _, volume := range opts.Volumes {
+		ids, err := e.volumeChapters(ctx, mangaID, volume, []string{lang})
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			n++
+			chapter, err := e.chapter(ctx, id)
+			if err != nil {
+				return err
+			}
+			dl, err := e.Downloader.Download(ctx, id, filepath.Join(dir, strconv.Itoa(n)))
+			if err != nil {
+				return err
+			}
+			for i, p := range dl.Pages {
+				page := newEPUBPage(fmt.Sprintf("c%03d-%03d", n, i+1), p.Path)
+				files[page.Image] = p.Path
+				if i == 0 {
+					book.Chapters = append(book.Chapters, epubChapter{
+						Title: chapterTitle(chapter, volume),
+						Href:  page.Href,
+					})
+				}
+				book.Pages = append(book.Pages, page)
+			}
+		}
+	}
+	return writeEPUB(w, book, files)
+}
+
+// newEPUBBook fills in the book metadata. Each selection of volumes and
+// language is its own book, so readers do not mistake one export for an
+// update of another: the identifier is a UUIDv5 of the manga id and the
+// selection, and the title names the volumes.
+func (e *Exporter) newEPUBBook(manga *mangadex.Manga, lang string, volumes []string) *epubBook {
+	info := NewComicInfo(manga, nil, e.Localizer)
+	book := &epubBook{
+		Title:       info.Series,
+		Language:    lang,
+		Description: info.Summary,
+		Modified:    time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	if label := volumesLabel(volumes); label != "" {
+		book.Title += " " + label
+	}
+	if manga.Id != nil {
+		selection := lang + "/" + strings.Join(volumes, ",")
+		book.ID = "urn:uuid:" + uuid.NewSHA1(*manga.Id, []byte(selection)).String()
+	}
+	authors, _ := manga.Authors()
+	artists, _ := manga.Artists()
+	for _, n := range append(personNames(authors), personNames(artists)...) {
+		if !contains(book.Creators, n) {
+			book.Creators = append(book.Creators, n)
+		}
+	}
+	if a := manga.Attributes; a != nil {
+		genres, others := tagNames(a.Tags, e.localizer())
+		book.Subjects = append(genres, others...)
+		if a.OriginalLanguage != nil && *a.OriginalLanguage == "ja" {
+			book.RTL = true
+		}
+	}
+	return book
+}
+
+// downloadCover fetches the cover of the given volume from the cover art
+// endpoint, falling back to the manga's main cover.
+func (e *Exporter) downloadCover(ctx context.Context, manga *mangadex.Manga, volume string, dir string) (*epubPage, error) {
+	covers := e.Covers
+	if covers == nil {
+		covers = mangadex.NewCoverURLs("")
+	}
+
+	var coverURL string
+	if manga.Id != nil {
+		ids := []openapi_types.UUID{*manga.Id}
+		resp, err := e.Client.GetCoverWithResponse(ctx, &mangadex.GetCoverParams{Manga: &ids, Limit: mangadex.Int(100)})
+		if err != nil {
+			return nil, err
+		}
+		if resp.JSON200 != nil && resp.JSON200.Data != nil {
+			for _, c := range *resp.JSON200.Data {
+				if c.Attributes == nil || deref(c.Attributes.Volume) != volume {
+					continue
+				}
+				if u, err := covers.FromCover(&c, mangadex.CoverOriginal); err == nil {
+					coverURL = u
+					break
+				}
+			}
+		}
+	}
+	if coverURL == "" {
+		u, err := covers.FromManga(manga, mangadex.CoverOriginal)
+		if err != nil {
+			return nil, nil
+		}
+		coverURL = u
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, coverURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := e.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching cover %s: %s", coverURL, resp.Status)
+	}
+
+	target := filepath.Join(dir, "cover"+path.Ext(coverURL))
+	f, err := os.Create(target)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	page := newEPUBPage("cover", target)
+	return &page, nil
+}
+
+func (e *Exporter) httpClient() *http.Client {
+	if e.HTTPClient != nil {
+		return e.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+func (e *Exporter) localizer() *mangadex.Localizer {
+	if e.Localizer != nil {
+		return e.Localizer
+	}
+	return defaultLocalizer()
+}
+
+func newEPUBPage(id string, file string) epubPage {
+	ext := strings.ToLower(filepath.Ext(file))
+	p := epubPage{
+		ID:    id,
+		Href:  "pages/" + id + ".xhtml",
+		Image: "images/" + id + ext,
+		Media: imageMediaType(ext),
+	}
+	size := defaultPageSize
+	if f, err := os.Open(file); err == nil {
+		if cfg, _, err := image.DecodeConfig(f); err == nil {
+			size = image.Point{X: cfg.Width, Y: cfg.Height}
+		}
+		f.Close()
+	}
+	p.Width, p.Height = size.X, size.Y
+	return p
+}
+
+func imageMediaType(ext string) string {
+	switch ext {
+	case ".png":
+		return "image/png"
+	case ".gif":
+		return "image/gif"
+	case ".webp":
+		return "image/webp"
+	}
+	return "image/jpeg"
+}
+
+func chapterTitle(c *mangadex.Chapter, volume string) string {
+	if c.Attributes == nil {
+		return "Chapter"
+	}
+	var parts []string
+	if volume != "none" && volume != "" {
+		parts = append(parts, "Vol. "+volume)
+	}
+	if n := deref(c.Attributes.Chapter); n != "" {
+		parts = append(parts, "Ch. "+n)
+	}
+	title := strings.Join(parts, " ")
+	if t := deref(c.Attributes.Title); t != "" {
+		if title != "" {
+			title += " - "
+		}
+		title += t
+	}
+	if title == "" {
+		title = "Oneshot"
+	}
+	return title
+}
+
+// volumesLabel is "Vol. 3" or "Vol. 1, 2, 4"; volumes without a number are
+// left out.
+func volumesLabel(volumes []string) string {
+	var numbered []string
+	for _, v := range volumes {
+		if v != "none" && v != "" {
+			numbered = append(numbered, v)
+		}
+	}
+	if len(numbered) == 0 {
+		return ""
+	}
+	return "Vol. " + strings.Join(numbered, ", ")
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func writeEPUB(w io.Writer, book *epubBook, files map[string]string) error {
+	zw := zip.NewWriter(w)
+
+	// mimetype must be the first entry and stored uncompressed
+	mt, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(mt, "application/epub+zip"); err != nil {
+		return err
+	}
+
+	type doc struct {
+		name string
+		tpl  *template.Template
+		data interface{}
+	}
+	docs := []doc{
+		{"META-INF/container.xml", containerTpl, nil},
+		{"OEBPS/content.opf", opfTpl, book},
+		{"OEBPS/nav.xhtml", navTpl, book},
+	}
+	if book.Cover != nil {
+		docs = append(docs, doc{"OEBPS/" + book.Cover.Href, pageTpl, book.Cover})
+	}
+	for i := range book.Pages {
+		docs = append(docs, doc{"OEBPS/" + book.Pages[i].Href, pageTpl, &book.Pages[i]})
+	}
+
+	for _, d := range docs {
+		var buf bytes.Buffer
+		if err := d.tpl.Execute(&buf, d.data); err != nil {
+			return err
+		}
+		f, err := zw.Create(d.name)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	images := make([]string, 0, len(files))
+	if book.Cover != nil {
+		images = append(images, book.Cover.Image)
+	}
+	for _, p := range book.Pages {
+		images = append(images, p.Image)
+	}
+	for _, name := range images {
+		if err := addFile(zw, "OEBPS/"+name, files[name], zip.Store); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+var tplFuncs = template.FuncMap{"x": xmlEscape}
+
+var containerTpl = template.Must(template.New("container").Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`))
+
+var opfTpl = template.Must(template.New("opf").Funcs(tplFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id" prefix="rendition: http://www.idpf.org/vocab/rendition/#">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="book-id">{{x .ID}}</dc:identifier>
+    <dc:title>{{x .Title}}</dc:title>
+    <dc:language>{{x .Language}}</dc:language>
+{{- range .Creators}}
+    <dc:creator>{{x .}}</dc:creator>
+{{- end}}
+{{- range .Subjects}}
+    <dc:subject>{{x .}}</dc:subject>
+{{- end}}
+{{- if .Description}}
+    <dc:description>{{x .Description}}</dc:description>
+{{- end}}
+    <dc:publisher>MangaDex</dc:publisher>
+    <meta property="dcterms:modified">{{.Modified}}</meta>
+    <meta property="rendition:layout">pre-paginated</meta>
+    <meta property="rendition:orientation">portrait</meta>
+    <meta property="rendition:spread">landscape</meta>
+{{- if .Cover}}
+    <meta name="cover" content="img-{{.Cover.ID}}"/>
+{{- end}}
+  </metadata>
+  <manifest>
+    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+{{- with .Cover}}
+    <item id="img-{{.ID}}" href="{{.Image}}" media-type="{{.Media}}" properties="cover-image"/>
+    <item id="{{.ID}}" href="{{.Href}}" media-type="application/xhtml+xml"/>
+{{- end}}
+{{- range .Pages}}
+    <item id="img-{{.ID}}" href="{{.Image}}" media-type="{{.Media}}"/>
+    <item id="{{.ID}}" href="{{.Href}}" media-type="application/xhtml+xml"/>
+{{- end}}
+  </manifest>
+  <spine{{if .RTL}} page-progression-direction="rtl"{{end}}>
+{{- with .Cover}}
+    <itemref idref="{{.ID}}" properties="rendition:page-spread-center"/>
+{{- end}}
+{{- range .Pages}}
+    <itemref idref="{{.ID}}"/>
+{{- end}}
+  </spine>
+</package>
+`))
+
+var navTpl = template.Must(template.New("nav").Funcs(tplFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head><title>{{x .Title}}</title></head>
+<body>
+  <nav epub:type="toc" id="toc">
+    <h1>{{x .Title}}</h1>
+    <ol>
+{{- range .Chapters}}
+      <li><a href="{{.Href}}">{{x .Title}}</a></li>
+{{- end}}
+    </ol>
+  </nav>
+</body>
+</html>
+`))
+
+var pageTpl = template.Must(template.New("page").Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head>
+  <title>{{.ID}}</title>
+  <meta name="viewport" content="width={{.Width}}, height={{.Height}}"/>
+  <style>html,body{margin:0;padding:0}img{width:{{.Width}}px;height:{{.Height}}px}</style>
+</head>
+<body>
+  <img src="../{{.Image}}" alt=""/>
+</body>
+</html>
+`))
@@ -0,0 +1,168 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Seann-Moser/mangadex"
+	"github.com/google/uuid"
+)
+
+func TestNewEPUBBookIdentifiesSelection(t *testing.T) {
+	id := uuid.MustParse("a1c7c817-4e59-43b7-9365-09675a149a6f")
+	manga := &mangadex.Manga{
+		Id: &id,
+		Attributes: &mangadex.MangaAttributes{
+			Title: &mangadex.LocalizedString{"en": "One Piece"},
+		},
+	}
+	e := NewExporter(nil)
+
+	tests := []struct {
+		name    string
+		lang    string
+		volumes []string
+		title   string
+	}{
+		{"one volume", "en", []string{"1"}, "One Piece Vol. 1"},
+		{"other volume", "en", []string{"2"}, "One Piece Vol. 2"},
+		{"several volumes", "en", []string{"1", "2"}, "One Piece Vol. 1, 2"},
+		{"other language", "fr", []string{"1"}, "One Piece Vol. 1"},
+		{"no volume", "en", []string{"none"}, "One Piece"},
+	}
+	ids := map[string]string{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book := e.newEPUBBook(manga, tt.lang, tt.volumes)
+			if book.Title != tt.title {
+				t.Fatalf("title = %q, want %q", book.Title, tt.title)
+			}
+			if other, ok := ids[book.ID]; ok {
+				t.Fatalf("%s shares identifier %s with %s", tt.name, book.ID, other)
+			}
+			ids[book.ID] = tt.name
+			if again := e.newEPUBBook(manga, tt.lang, tt.volumes); again.ID != book.ID {
+				t.Fatalf("identifier not stable: %s, %s", book.ID, again.ID)
+			}
+		})
+	}
+}
+
+// opfPackage decodes the parts of content.opf the tests look at.
+type opfPackage struct {
+	Identifier string   `xml:"metadata>identifier"`
+	Title      string   `xml:"metadata>title"`
+	Creators   []string `xml:"metadata>creator"`
+	Manifest   []struct {
+		ID         string `xml:"id,attr"`
+		Href       string `xml:"href,attr"`
+		Properties string `xml:"properties,attr"`
+	} `xml:"manifest>item"`
+	Spine struct {
+		Direction string `xml:"page-progression-direction,attr"`
+		Items     []struct {
+			IDRef string `xml:"idref,attr"`
+		} `xml:"itemref"`
+	} `xml:"spine"`
+}
+
+type navDocument struct {
+	Links []struct {
+		Href  string `xml:"href,attr"`
+		Title string `xml:",chardata"`
+	} `xml:"body>nav>ol>li>a"`
+}
+
+func TestEPUB(t *testing.T) {
+	tests := []struct {
+		name      string
+		original  string
+		direction string
+	}{
+		{"japanese", "ja", "rtl"},
+		{"korean", "ko", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeAPI(t)
+			f.Manga.Attributes.OriginalLanguage = mangadex.String(tt.original)
+			f.AddChapter("1", "2")
+			f.AddChapter("1", "1")
+			f.Covers["1"] = []byte("cover one")
+			var buf bytes.Buffer
+			if err := f.exporter(t).EPUB(context.Background(), testMangaID, EPUBOptions{Volumes: []string{"1"}}, &buf); err != nil {
+				t.Fatal(err)
+			}
+
+			files := readZip(t, buf.Bytes())
+			want := []string{
+				"mimetype",
+				"META-INF/container.xml",
+				"OEBPS/content.opf",
+				"OEBPS/nav.xhtml",
+				"OEBPS/pages/cover.xhtml",
+				"OEBPS/pages/c001-001.xhtml",
+				"OEBPS/pages/c001-002.xhtml",
+				"OEBPS/pages/c002-001.xhtml",
+				"OEBPS/pages/c002-002.xhtml",
+				"OEBPS/images/cover.png",
+				"OEBPS/images/c001-001.png",
+				"OEBPS/images/c001-002.png",
+				"OEBPS/images/c002-001.png",
+				"OEBPS/images/c002-002.png",
+			}
+			if !slices.Equal(entryNames(files), want) {
+				t.Fatalf("entries = %v, want %v", entryNames(files), want)
+			}
+			// readers sniff the first entry, which must be stored as is
+			if files[0].Method != zip.Store || string(readEntry(t, files[0])) != "application/epub+zip" {
+				t.Fatalf("mimetype entry: method %d, %q", files[0].Method, readEntry(t, files[0]))
+			}
+			if got := string(readEntry(t, files[9])); got != "cover one" {
+				t.Fatalf("cover = %q", got)
+			}
+			if got := string(readEntry(t, files[10])); got != "ch1 p1" {
+				t.Fatalf("first page = %q, want chapter 1", got)
+			}
+
+			var opf opfPackage
+			if err := xml.Unmarshal(readEntry(t, files[2]), &opf); err != nil {
+				t.Fatal(err)
+			}
+			if opf.Spine.Direction != tt.direction {
+				t.Errorf("page-progression-direction = %q, want %q", opf.Spine.Direction, tt.direction)
+			}
+			if opf.Title != "Berserk Vol. 1" || !strings.HasPrefix(opf.Identifier, "urn:uuid:") {
+				t.Errorf("title %q, identifier %q", opf.Title, opf.Identifier)
+			}
+			// names containing commas stay whole, and artists who also
+			// wrote are credited once
+			if want := []string{"Miura, Kentaro", "Studio Gaga"}; !slices.Equal(opf.Creators, want) {
+				t.Errorf("creators = %q, want %q", opf.Creators, want)
+			}
+			var spine []string
+			for _, item := range opf.Spine.Items {
+				spine = append(spine, item.IDRef)
+			}
+			if want := []string{"cover", "c001-001", "c001-002", "c002-001", "c002-002"}; !slices.Equal(spine, want) {
+				t.Errorf("spine = %v, want %v", spine, want)
+			}
+			if len(opf.Manifest) != 11 || opf.Manifest[1].Properties != "cover-image" {
+				t.Errorf("manifest = %+v", opf.Manifest)
+			}
+
+			var nav navDocument
+			if err := xml.Unmarshal(readEntry(t, files[3]), &nav); err != nil {
+				t.Fatal(err)
+			}
+			if len(nav.Links) != 2 || nav.Links[0].Title != "Vol. 1 Ch. 1 - The Black Swordsman" || nav.Links[1].Href != "pages/c002-001.xhtml" {
+				t.Errorf("nav = %+v", nav.Links)
+			}
+		})
+	}
+}
@@ -0,0 +1,443 @@
+// Package feed serves Atom and RSS feeds of recently published chapters.
+package feed
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Seann-Moser/mangadex"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	// DefaultWebURL is where entry links point.
+	DefaultWebURL = "https://mangadex.org"
+
+	DefaultLimit = 50
+	maxLimit     = 100
+)
+
+// Format is a feed document format.
+type Format string
+
+const (
+	FormatAtom Format = "atom"
+	FormatRSS  Format = "rss"
+)
+
+// Handler serves feeds under the following paths, relative to where it is
+// mounted (use http.StripPrefix):
+//
+//	/manga/{id}   chapters of a manga
+//	/list/{id}    chapters of a custom list's manga
+//	/group/{id}   chapters uploaded by a scanlation group
+//
+// The id may carry a ".atom" or ".rss" extension, or the format may be set
+// with ?format=; Atom is the default. Chapters are filtered by translated
+// language with ?lang=en&lang=fr (or lang=en,fr), and ?limit= caps the
+// number of entries. Responses carry ETag and Last-Modified headers and
+// answer conditional requests with 304 Not Modified.
+type Handler struct {
+	Client    mangadex.ClientWithResponsesInterface
+	Localizer *mangadex.Localizer
+	WebURL    string
+
+	// Limit is the default and maximum number of entries.
+	Limit int
+
+	mux *http.ServeMux
+}
+
+func NewHandler(client mangadex.ClientWithResponsesInterface) *Handler {
+	h := &Handler{
+		Client:    client,
+		Localizer: mangadex.NewLocalizer("en", mangadex.LanguageOriginal),
+		WebURL:    DefaultWebURL,
+		Limit:     DefaultLimit,
+	}
+	h.mux = http.NewServeMux()
+	h.mux.HandleFunc("GET /manga/{id}", h.serve(h.MangaFeed))
+	h.mux.HandleFunc("GET /list/{id}", h.serve(h.ListFeed))
+	h.mux.HandleFunc("GET /group/{id}", h.serve(h.GroupFeed))
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// Query selects the chapters of a feed.
+type Query struct {
+	Languages []string
+	Limit     int
+}
+
+type builder func(ctx context.Context, id openapi_types.UUID, q Query) (*Feed, error)
+
+func (h *Handler) serve(build builder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		raw, ext, _ := strings.Cut(r.PathValue("id"), ".")
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			http.Error(w, "invalid id", http.StatusBadRequest)
+			return
+		}
+		format := Format(strings.ToLower(r.URL.Query().Get("format")))
+		if ext != "" {
+			format = Format(strings.ToLower(ext))
+		}
+		if format == "" {
+			format = FormatAtom
+		}
+		if format != FormatAtom && format != FormatRSS {
+			http.Error(w, "unknown feed format", http.StatusBadRequest)
+			return
+		}
+
+		f, err := build(r.Context(), id, h.query(r))
+		if err != nil {
+			http.Error(w, err.Error(), statusOf(err))
+			return
+		}
+		f.SelfURL = selfURL(r)
+
+		var (
+			body        []byte
+			contentType string
+		)
+		if format == FormatRSS {
+			body, err = f.RSS()
+			contentType = "application/rss+xml; charset=utf-8"
+		} else {
+			body, err = f.Atom()
+			contentType = "application/atom+xml; charset=utf-8"
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		sum := sha256.Sum256(body)
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+		// ServeContent answers If-None-Match and If-Modified-Since, and
+		// leaves out Last-Modified when Updated is zero
+		http.ServeContent(w, r, "", f.Updated, bytes.NewReader(body))
+	}
+}
+
+func (h *Handler) query(r *http.Request) Query {
+	q := Query{Limit: h.limit()}
+	for _, v := range r.URL.Query()["lang"] {
+		for _, l := range strings.Split(v, ",") {
+			if l = strings.TrimSpace(l); l != "" {
+				q.Languages = append(q.Languages, l)
+			}
+		}
+	}
+	if n, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && n > 0 && n < q.Limit {
+		q.Limit = n
+	}
+	return q
+}
+
+func (h *Handler) limit() int {
+	if h.Limit <= 0 || h.Limit > maxLimit {
+		return DefaultLimit
+	}
+	return h.Limit
+}
+
+// MangaFeed builds the feed of a manga's latest chapters.
+func (h *Handler) MangaFeed(ctx context.Context, id openapi_types.UUID, q Query) (*Feed, error) {
+	mresp, err := h.Client.GetMangaIdWithResponse(ctx, id, &mangadex.GetMangaIdParams{})
+	if err != nil {
+		return nil, err
+	}
+	if mresp.JSON200 == nil || mresp.JSON200.Data == nil {
+		return nil, upstream(mresp.HTTPResponse, "manga %s: %s", id, mresp.Status())
+	}
+	title, _ := h.localizer().Title(mresp.JSON200.Data)
+
+	params := &mangadex.GetMangaIdFeedParams{
+		Limit:    mangadex.Int(q.Limit),
+		Includes: includes(),
+	}
+	if len(q.Languages) > 0 {
+		params.TranslatedLanguage = &q.Languages
+	}
+	desc := mangadex.GetMangaIdFeedParamsOrderPublishAt("desc")
+	params.Order = &struct {
+		Chapter    *mangadex.GetMangaIdFeedParamsOrderChapter    `json:"chapter,omitempty" bson:"chapter"`
+		CreatedAt  *mangadex.GetMangaIdFeedParamsOrderCreatedAt  `json:"createdAt,omitempty" bson:"createdAt"`
+		PublishAt  *mangadex.GetMangaIdFeedParamsOrderPublishAt  `json:"publishAt,omitempty" bson:"publishAt"`
+		ReadableAt *mangadex.GetMangaIdFeedParamsOrderReadableAt `json:"readableAt,omitempty" bson:"readableAt"`
+		UpdatedAt  *mangadex.GetMangaIdFeedParamsOrderUpdatedAt  `json:"updatedAt,omitempty" bson:"updatedAt"`
+		Volume     *mangadex.GetMangaIdFeedParamsOrderVolume     `json:"volume,omitempty" bson:"volume"`
+	}{PublishAt: &desc}
+
+	resp, err := h.Client.GetMangaIdFeedWithResponse(ctx, id, params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, upstream(resp.HTTPResponse, "manga %s feed: %s", id, resp.Status())
+	}
+	var updated time.Time
+	if a := mresp.JSON200.Data.Attributes; a != nil && a.UpdatedAt != nil {
+		updated, _ = time.Parse(time.RFC3339, *a.UpdatedAt)
+	}
+	return h.build("urn:uuid:"+id.String(), title, h.webURL()+"/title/"+id.String(), updated, resp.JSON200), nil
+}
+
+// ListFeed builds the feed of the latest chapters of a custom list's manga.
+func (h *Handler) ListFeed(ctx context.Context, id openapi_types.UUID, q Query) (*Feed, error) {
+	lresp, err := h.Client.GetListIdWithResponse(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if lresp.JSON200 == nil || lresp.JSON200.Data == nil {
+		return nil, upstream(lresp.HTTPResponse, "list %s: %s", id, lresp.Status())
+	}
+	title := "MangaDex list"
+	if a := lresp.JSON200.Data.Attributes; a != nil && a.Name != nil {
+		title = *a.Name
+	}
+
+	params := &mangadex.GetListIdFeedParams{
+		Limit:    mangadex.Int(q.Limit),
+		Includes: includes(),
+	}
+	if len(q.Languages) > 0 {
+		params.TranslatedLanguage = &q.Languages
+	}
+	desc := mangadex.GetListIdFeedParamsOrderPublishAt("desc")
+	params.Order = &struct {
+		Chapter    *mangadex.GetListIdFeedParamsOrderChapter    `json:"chapter,omitempty" bson:"chapter"`
+		CreatedAt  *mangadex.GetListIdFeedParamsOrderCreatedAt  `json:"createdAt,omitempty" bson:"createdAt"`
+		PublishAt  *mangadex.GetListIdFeedParamsOrderPublishAt  `json:"publishAt,omitempty" bson:"publishAt"`
+		ReadableAt *mangadex.GetListIdFeedParamsOrderReadableAt `json:"readableAt,omitempty" bson:"readableAt"`
+		UpdatedAt  *mangadex.GetListIdFeedParamsOrderUpdatedAt  `json:"updatedAt,omitempty" bson:"updatedAt"`
+		Volume     *mangadex.GetListIdFeedParamsOrderVolume     `json:"volume,omitempty" bson:"volume"`
+	}{PublishAt: &desc}
+
+	resp, err := h.Client.GetListIdFeedWithResponse(ctx, id, params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, upstream(resp.HTTPResponse, "list %s feed: %s", id, resp.Status())
+	}
+	// lists carry no timestamp of their own, so an empty one is dated by
+	// the request
+	now := time.Now().UTC().Truncate(time.Second)
+	return h.build("urn:uuid:"+id.String(), title, h.webURL()+"/list/"+id.String(), now, resp.JSON200), nil
+}
+
+// GroupFeed builds the feed of a scanlation group's latest chapters.
+func (h *Handler) GroupFeed(ctx context.Context, id openapi_types.UUID, q Query) (*Feed, error) {
+	gresp, err := h.Client.GetGroupIdWithResponse(ctx, id, &mangadex.GetGroupIdParams{})
+	if err != nil {
+		return nil, err
+	}
+	if gresp.JSON200 == nil || gresp.JSON200.Data == nil {
+		return nil, upstream(gresp.HTTPResponse, "group %s: %s", id, gresp.Status())
+	}
+	title := "MangaDex group"
+	if a := gresp.JSON200.Data.Attributes; a != nil && a.Name != nil {
+		title = *a.Name
+	}
+
+	groups := []openapi_types.UUID{id}
+	params := &mangadex.GetChapterParams{
+		Limit:    mangadex.Int(q.Limit),
+		Groups:   &groups,
+		Includes: &[]mangadex.GetChapterParamsIncludes{mangadex.GetChapterParamsIncludesManga, mangadex.GetChapterParamsIncludesScanlationGroup},
+	}
+	if len(q.Languages) > 0 {
+		params.TranslatedLanguage = &q.Languages
+	}
+	desc := mangadex.GetChapterParamsOrderPublishAt("desc")
+	params.Order = &struct {
+		Chapter    *mangadex.GetChapterParamsOrderChapter    `json:"chapter,omitempty" bson:"chapter"`
+		CreatedAt  *mangadex.GetChapterParamsOrderCreatedAt  `json:"createdAt,omitempty" bson:"createdAt"`
+		PublishAt  *mangadex.GetChapterParamsOrderPublishAt  `json:"publishAt,omitempty" bson:"publishAt"`
+		ReadableAt *mangadex.GetChapterParamsOrderReadableAt `json:"readableAt,omitempty" bson:"readableAt"`
+		UpdatedAt  *mangadex.GetChapterParamsOrderUpdatedAt  `json:"updatedAt,omitempty" bson:"updatedAt"`
+		Volume     *mangadex.GetChapterParamsOrderVolume     `json:"volume,omitempty" bson:"volume"`
+	}{PublishAt: &desc}
+
+	resp, err := h.Client.GetChapterWithResponse(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, upstream(resp.HTTPResponse, "group %s chapters: %s", id, resp.Status())
+	}
+	var updated time.Time
+	if a := gresp.JSON200.Data.Attributes; a != nil && a.UpdatedAt != nil {
+		updated, _ = time.Parse(time.RFC3339, *a.UpdatedAt)
+	}
+	return h.build("urn:uuid:"+id.String(), title, h.webURL()+"/group/"+id.String(), updated, resp.JSON200), nil
+}
+
+// build assembles a feed from a chapter list. The feed is as new as its
+// newest entry; an empty feed uses updated, the time its manga or group last
+// changed, so that its body and ETag stay the same between requests. Empty
+// list feeds are dated by the request instead.
+func (h *Handler) build(id, title, link string, updated time.Time, list *mangadex.ChapterList) *Feed {
+	f := &Feed{ID: id, Title: title, Link: link}
+	if list.Data != nil {
+		for _, c := range *list.Data {
+			if c.Id == nil {
+				continue
+			}
+			e := h.entry(c)
+			if e.Updated.After(f.Updated) {
+				f.Updated = e.Updated
+			}
+			f.Entries = append(f.Entries, e)
+		}
+	}
+	sort.SliceStable(f.Entries, func(i, j int) bool {
+		return f.Entries[i].Updated.After(f.Entries[j].Updated)
+	})
+	if f.Updated.IsZero() {
+		f.Updated = updated
+	}
+	return f
+}
+
+func (h *Handler) entry(c mangadex.Chapter) Entry {
+	e := Entry{
+		ID:   "urn:uuid:" + c.Id.String(),
+		Link: h.webURL() + "/chapter/" + c.Id.String(),
+	}
+	a := c.Attributes
+	if a == nil {
+		a = &mangadex.ChapterAttributes{}
+	}
+	if a.ExternalUrl != nil && *a.ExternalUrl != "" {
+		e.Link = *a.ExternalUrl
+	}
+	if a.TranslatedLanguage != nil {
+		e.Language = *a.TranslatedLanguage
+	}
+	if a.PublishAt != nil {
+		e.Published, _ = time.Parse(time.RFC3339, *a.PublishAt)
+		e.Updated = e.Published
+	}
+
+	var mangaTitle string
+	if m, err := c.Manga(); err == nil && m != nil && m.Attributes != nil {
+		mangaTitle, _ = h.localizer().Title(&mangadex.Manga{Id: &m.Id, Attributes: m.Attributes})
+	}
+	if groups, err := c.ScanlationGroups(); err == nil {
+		for _, g := range groups {
+			if g.Attributes != nil && g.Attributes.Name != nil {
+				e.Authors = append(e.Authors, *g.Attributes.Name)
+			}
+		}
+	}
+	if len(e.Authors) == 0 {
+		e.Authors = []string{"No Group"}
+	}
+
+	label := chapterLabel(a)
+	e.Title = label
+	if mangaTitle != "" {
+		e.Title = mangaTitle + " - " + label
+	}
+	e.Summary = fmt.Sprintf("%s by %s", label, strings.Join(e.Authors, ", "))
+	if a.Pages != nil && *a.Pages > 0 {
+		e.Summary += fmt.Sprintf(", %d pages", *a.Pages)
+	}
+	if e.Language != "" {
+		e.Summary += " [" + e.Language + "]"
+	}
+	return e
+}
+
+// chapterLabel renders "Vol. 2 Ch. 10: Title", or "Oneshot" for chapters
+// without a number.
+func chapterLabel(a *mangadex.ChapterAttributes) string {
+	var parts []string
+	if a.Volume != nil && *a.Volume != "" {
+		parts = append(parts, "Vol. "+*a.Volume)
+	}
+	if a.Chapter != nil && *a.Chapter != "" {
+		parts = append(parts, "Ch. "+*a.Chapter)
+	} else {
+		parts = append(parts, "Oneshot")
+	}
+	label := strings.Join(parts, " ")
+	if a.Title != nil && *a.Title != "" {
+		label += ": " + *a.Title
+	}
+	return label
+}
+
+func includes() *mangadex.ReferenceExpansionChapter {
+	return &mangadex.ReferenceExpansionChapter{mangadex.RelationshipManga, mangadex.RelationshipScanlationGroup}
+}
+
+func (h *Handler) localizer() *mangadex.Localizer {
+	if h.Localizer == nil {
+		return mangadex.NewLocalizer()
+	}
+	return h.Localizer
+}
+
+func (h *Handler) webURL() string {
+	if h.WebURL == "" {
+		return DefaultWebURL
+	}
+	return strings.TrimRight(h.WebURL, "/")
+}
+
+func selfURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	// RequestURI is the path before any http.StripPrefix
+	uri := r.RequestURI
+	if uri == "" {
+		uri = r.URL.RequestURI()
+	}
+	return scheme + "://" + r.Host + uri
+}
+
+// upstreamError carries the API status so the handler can pass 404s on.
+type upstreamError struct {
+	status int
+	msg    string
+}
+
+func (e *upstreamError) Error() string { return e.msg }
+
+func upstream(resp *http.Response, format string, args ...any) error {
+	e := &upstreamError{status: http.StatusBadGateway, msg: fmt.Sprintf(format, args...)}
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		e.status = http.StatusNotFound
+	}
+	return e
+}
+
+func statusOf(err error) int {
+	var ue *upstreamError
+	if errors.As(err, &ue) {
+		return ue.status
+	}
+	return http.StatusBadGateway
+}
@@ -0,0 +1,227 @@
+package feed
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Seann-Moser/mangadex"
+	"github.com/google/uuid"
+)
+
+const mangaUpdatedAt = "2024-03-01T10:00:00+00:00"
+
+// fakeAPI serves a manga and its feed, whose chapters can change between
+// requests.
+type fakeAPI struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	chapters []mangadex.Chapter
+}
+
+func newFakeAPI(t *testing.T) *fakeAPI {
+	t.Helper()
+	f := &fakeAPI{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /manga/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := uuid.MustParse(r.PathValue("id"))
+		writeJSON(w, mangadex.MangaResponse{Data: &mangadex.Manga{
+			Id: &id,
+			Attributes: &mangadex.MangaAttributes{
+				Title:     &mangadex.LocalizedString{"en": "Berserk"},
+				UpdatedAt: mangadex.String(mangaUpdatedAt),
+			},
+		}})
+	})
+	mux.HandleFunc("GET /manga/{id}/feed", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		data := append([]mangadex.Chapter(nil), f.chapters...)
+		f.mu.Unlock()
+		writeJSON(w, mangadex.ChapterList{Data: &data, Total: mangadex.Int(len(data))})
+	})
+	mux.HandleFunc("GET /list/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := uuid.MustParse(r.PathValue("id"))
+		writeJSON(w, mangadex.CustomListResponse{Data: &mangadex.CustomList{
+			Id:         &id,
+			Attributes: &mangadex.CustomListAttributes{Name: mangadex.String("Reading"), Version: mangadex.Int(3)},
+		}})
+	})
+	mux.HandleFunc("GET /list/{id}/feed", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		data := append([]mangadex.Chapter(nil), f.chapters...)
+		f.mu.Unlock()
+		writeJSON(w, mangadex.ChapterList{Data: &data, Total: mangadex.Int(len(data))})
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeAPI) AddChapter(number string, publishAt time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := uuid.New()
+	f.chapters = append(f.chapters, mangadex.Chapter{Id: &id, Attributes: &mangadex.ChapterAttributes{
+		Chapter:            mangadex.String(number),
+		TranslatedLanguage: mangadex.String("en"),
+		PublishAt:          mangadex.String(publishAt.Format(time.RFC3339)),
+	}})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (f *fakeAPI) handler(t *testing.T) *Handler {
+	t.Helper()
+	client, err := mangadex.NewClientWithResponses(f.URL, mangadex.WithHTTPClient(f.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewHandler(client)
+}
+
+func get(h http.Handler, target string, header http.Header) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestHandlerConditionalRequests(t *testing.T) {
+	api := newFakeAPI(t)
+	h := api.handler(t)
+	target := "/manga/" + uuid.NewString()
+	published := time.Date(2024, 4, 1, 8, 0, 0, 0, time.UTC)
+	api.AddChapter("1", published)
+
+	first := get(h, target, nil)
+	if first.Code != http.StatusOK {
+		t.Fatalf("status = %d", first.Code)
+	}
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag")
+	}
+	if got := first.Header().Get("Last-Modified"); got != published.Format(http.TimeFormat) {
+		t.Fatalf("Last-Modified = %q", got)
+	}
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   int
+	}{
+		{"matching etag", http.Header{"If-None-Match": {etag}}, http.StatusNotModified},
+		{"other etag", http.Header{"If-None-Match": {`"stale"`}}, http.StatusOK},
+		{"not modified since", http.Header{"If-Modified-Since": {published.Format(http.TimeFormat)}}, http.StatusNotModified},
+		{"modified since", http.Header{"If-Modified-Since": {published.Add(-time.Hour).Format(http.TimeFormat)}}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := get(h, target, tt.header); rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+
+	// a new chapter changes the document
+	api.AddChapter("2", published.Add(time.Hour))
+	rec := get(h, target, http.Header{"If-None-Match": {etag}})
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Fatalf("status = %d, ETag = %s after a new chapter", rec.Code, rec.Header().Get("ETag"))
+	}
+}
+
+func TestHandlerEmptyFeedIsStable(t *testing.T) {
+	api := newFakeAPI(t)
+	h := api.handler(t)
+	target := "/manga/" + uuid.NewString() + ".atom"
+
+	first := get(h, target, nil)
+	if first.Code != http.StatusOK {
+		t.Fatalf("status = %d", first.Code)
+	}
+	if !strings.Contains(first.Body.String(), "<updated>2024-03-01T10:00:00Z</updated>") {
+		t.Fatalf("feed not dated by the manga:\n%s", first.Body)
+	}
+	time.Sleep(1100 * time.Millisecond)
+	if rec := get(h, target, http.Header{"If-None-Match": {first.Header().Get("ETag")}}); rec.Code != http.StatusNotModified {
+		t.Fatalf("empty feed changed: status %d", rec.Code)
+	}
+}
+
+func TestHandlerFormats(t *testing.T) {
+	api := newFakeAPI(t)
+	api.AddChapter("1", time.Date(2024, 4, 1, 8, 0, 0, 0, time.UTC))
+	h := api.handler(t)
+	id := uuid.NewString()
+
+	tests := []struct {
+		name   string
+		target string
+		status int
+		ctype  string
+	}{
+		{"default", "/manga/" + id, http.StatusOK, "application/atom+xml"},
+		{"extension", "/manga/" + id + ".rss", http.StatusOK, "application/rss+xml"},
+		{"query", "/manga/" + id + "?format=rss", http.StatusOK, "application/rss+xml"},
+		{"extension wins", "/manga/" + id + ".atom?format=rss", http.StatusOK, "application/atom+xml"},
+		{"unknown format", "/manga/" + id + ".json", http.StatusBadRequest, ""},
+		{"bad id", "/manga/not-a-uuid", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(h, tt.target, nil)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if tt.ctype != "" && !strings.HasPrefix(rec.Header().Get("Content-Type"), tt.ctype) {
+				t.Fatalf("Content-Type = %q", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestHandlerEmptyListFeedIsDated(t *testing.T) {
+	api := newFakeAPI(t)
+	h := api.handler(t)
+	id := uuid.NewString()
+	before := time.Now().UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name   string
+		target string
+		layout string
+		re     *regexp.Regexp
+	}{
+		{"atom", "/list/" + id + ".atom", time.RFC3339, regexp.MustCompile(`<updated>([^<]+)</updated>`)},
+		{"rss", "/list/" + id + ".rss", time.RFC1123Z, regexp.MustCompile(`<lastBuildDate>([^<]+)</lastBuildDate>`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(h, tt.target, nil)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+			}
+			m := tt.re.FindStringSubmatch(rec.Body.String())
+			if m == nil {
+				t.Fatalf("no feed date:\n%s", rec.Body)
+			}
+			at, err := time.Parse(tt.layout, m[1])
+			if err != nil || at.Before(before) {
+				t.Fatalf("feed dated %q, want the request time", m[1])
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Feed is a format-neutral feed rendered as Atom or RSS.
+type Feed struct {
+	ID      string
+	Title   string
+	Link    string
+	SelfURL string
+	Updated time.Time
+	Entries []Entry
+}
+
+// Entry is a single chapter in a Feed.
+type Entry struct {
+	ID        string
+	Title     string
+	Link      string
+	Summary   string
+	Authors   []string
+	Language  string
+	Published time.Time
+	Updated   time.Time
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string       `xml:"id"`
+	Title     string       `xml:"title"`
+	Updated   string       `xml:"updated"`
+	Published string       `xml:"published,omitempty"`
+	Links     []atomLink   `xml:"link"`
+	Authors   []atomPerson `xml:"author"`
+	Summary   string       `xml:"summary,omitempty"`
+	Lang      string       `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+// Atom renders f as an Atom 1.0 document.
+func (f *Feed) Atom() ([]byte, error) {
+	a := atomFeed{
+		ID:      f.ID,
+		Title:   f.Title,
+		Updated: f.Updated.UTC().Format(time.RFC3339),
+		Links:   []atomLink{{Href: f.Link, Rel: "alternate", Type: "text/html"}},
+	}
+	if f.SelfURL != "" {
+		a.Links = append(a.Links, atomLink{Href: f.SelfURL, Rel: "self", Type: "application/atom+xml"})
+	}
+	for _, e := range f.Entries {
+		ae := atomEntry{
+			ID:      e.ID,
+			Title:   e.Title,
+			Updated: e.Updated.UTC().Format(time.RFC3339),
+			Links:   []atomLink{{Href: e.Link, Rel: "alternate", Type: "text/html"}},
+			Summary: e.Summary,
+			Lang:    e.Language,
+		}
+		if !e.Published.IsZero() {
+			ae.Published = e.Published.UTC().Format(time.RFC3339)
+		}
+		for _, name := range e.Authors {
+			ae.Authors = append(ae.Authors, atomPerson{Name: name})
+		}
+		a.Entries = append(a.Entries, ae)
+	}
+	return marshal(a)
+}
+
+type rssDoc struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Self          *atomLink `xml:"atom:link,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description,omitempty"`
+	Author      string  `xml:"dc:creator,omitempty"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// RSS renders f as an RSS 2.0 document.
+func (f *Feed) RSS() ([]byte, error) {
+	doc := rssDoc{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		DC:      "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Title,
+			LastBuildDate: f.Updated.UTC().Format(time.RFC1123Z),
+		},
+	}
+	if f.SelfURL != "" {
+		doc.Channel.Self = &atomLink{Href: f.SelfURL, Rel: "self", Type: "application/rss+xml"}
+	}
+	for _, e := range f.Entries {
+		item := rssItem{
+			Title:       e.Title,
+			Link:        e.Link,
+			GUID:        rssGUID{Value: e.ID},
+			PubDate:     e.Updated.UTC().Format(time.RFC1123Z),
+			Description: e.Summary,
+		}
+		if len(e.Authors) > 0 {
+			item.Author = e.Authors[0]
+		}
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+	return marshal(doc)
+}
+
+func marshal(v any) ([]byte, error) {
+	out, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
@@ -0,0 +1,71 @@
+// Package chapternum parses the free-form volume and chapter numbers
+// MangaDex stores. It backs chapters.Number and the bulk downloader's
+// release grouping, which cannot import the chapters package.
+package chapternum
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Parts is a parsed number.
+type Parts struct {
+	// Value is the leading numeric part, valid when Numeric is true.
+	Value   float64
+	Numeric bool
+
+	// Suffix is whatever follows the numeric part, lower-cased ("a" in "7a").
+	Suffix string
+
+	// None is set for empty, "none" and "null" numbers.
+	None bool
+}
+
+// Split parses a volume or chapter number.
+func Split(s string) Parts {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.EqualFold(s, "none") || strings.EqualFold(s, "null") {
+		return Parts{None: true}
+	}
+
+	end := 0
+	dot := false
+scan:
+	for i, r := range s {
+		switch {
+		case unicode.IsDigit(r):
+			end = i + 1
+		case r == '.' && !dot && end > 0:
+			dot = true
+		default:
+			break scan
+		}
+	}
+	if end > 0 {
+		if v, err := strconv.ParseFloat(s[:end], 64); err == nil {
+			return Parts{
+				Value:   v,
+				Numeric: true,
+				Suffix:  strings.ToLower(strings.TrimSpace(strings.TrimPrefix(s[end:], "."))),
+			}
+		}
+	}
+	return Parts{Suffix: strings.ToLower(s)}
+}
+
+// Key is a canonical form of p, so that "10", "10.0" and "010" share a key.
+func (p Parts) Key() string {
+	switch {
+	case p.None:
+		return "none"
+	case p.Numeric:
+		return strconv.FormatFloat(p.Value, 'f', -1, 64) + p.Suffix
+	}
+	return p.Suffix
+}
+
+// Key is Split(s).Key().
+func Key(s string) string {
+	return Split(s).Key()
+}
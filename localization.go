@@ -0,0 +1,221 @@
+package mangadex
+
+import (
+	"sort"
+	"strings"
+)
+
+// LanguageOriginal may be used in a preference list to stand for the manga's
+// originalLanguage.
+const LanguageOriginal = "original"
+
+// Localizer resolves LocalizedString values against an ordered list of
+// language preferences, e.g. []string{"en", "ja-ro", LanguageOriginal}.
+//
+// Tags are compared as BCP-47 tags, case-insensitively and treating "_" as
+// "-". A preference without a region matches any region of the same
+// language ("pt" matches "pt-br") and a regional preference falls back to the
+// bare language ("en-us" matches "en"). MangaDex's "-ro" romanization suffix
+// is treated as a script, so "ja" never matches "ja-ro" and vice versa.
+type Localizer struct {
+	Preferences []string
+}
+
+// NewLocalizer creates a Localizer with the given language preferences.
+func NewLocalizer(preferences ...string) *Localizer {
+	return &Localizer{Preferences: preferences}
+}
+
+// Title returns the best title of m and the language it was found in. Both
+// the main title and alt titles are considered for each preference before
+// falling back to the main title in any language.
+func (l *Localizer) Title(m *Manga) (title string, lang string) {
+	if m == nil || m.Attributes == nil {
+		return "", ""
+	}
+	a := m.Attributes
+
+	candidates := l.titleCandidates(a)
+	for _, pref := range l.expand(a) {
+		if lang, ok := bestMatch(pref, candidates); ok {
+			return candidates[lang][0], lang
+		}
+	}
+
+	if a.Title != nil {
+		if lang, v, ok := anyValue(*a.Title, originalLanguage(a)); ok {
+			return v, lang
+		}
+	}
+	return "", ""
+}
+
+// Titles returns every title of m grouped by normalized language tag. The
+// main title comes first within its language, followed by alt titles in API
+// order.
+func (l *Localizer) Titles(m *Manga) map[string][]string {
+	if m == nil || m.Attributes == nil {
+		return nil
+	}
+	return l.titleCandidates(m.Attributes)
+}
+
+// Description returns the best description of m and its language.
+func (l *Localizer) Description(m *Manga) (description string, lang string) {
+	if m == nil || m.Attributes == nil || m.Attributes.Description == nil {
+		return "", ""
+	}
+	return l.resolve(*m.Attributes.Description, l.expand(m.Attributes), originalLanguage(m.Attributes))
+}
+
+// String resolves s against the preferences, falling back to English and then
+// to any non-empty value.
+func (l *Localizer) String(s LocalizedString) (value string, lang string) {
+	return l.resolve(s, l.Preferences, "")
+}
+
+func (l *Localizer) resolve(s LocalizedString, prefs []string, original string) (string, string) {
+	candidates := make(map[string][]string, len(s))
+	for k, v := range s {
+		if v == "" {
+			continue
+		}
+		k = NormalizeLanguage(k)
+		candidates[k] = append(candidates[k], v)
+	}
+	for _, pref := range prefs {
+		if lang, ok := bestMatch(pref, candidates); ok {
+			return candidates[lang][0], lang
+		}
+	}
+	if lang, v, ok := anyValue(s, original); ok {
+		return v, lang
+	}
+	return "", ""
+}
+
+func (l *Localizer) titleCandidates(a *MangaAttributes) map[string][]string {
+	out := make(map[string][]string)
+	if a.Title != nil {
+		for k, v := range *a.Title {
+			if v != "" {
+				k = NormalizeLanguage(k)
+				out[k] = append(out[k], v)
+			}
+		}
+	}
+	if a.AltTitles != nil {
+		for _, alt := range *a.AltTitles {
+			for k, v := range alt {
+				if v != "" {
+					k = NormalizeLanguage(k)
+					out[k] = append(out[k], v)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// expand substitutes LanguageOriginal with the manga's original language.
+func (l *Localizer) expand(a *MangaAttributes) []string {
+	prefs := make([]string, 0, len(l.Preferences))
+	for _, p := range l.Preferences {
+		if strings.EqualFold(p, LanguageOriginal) {
+			if o := originalLanguage(a); o != "" {
+				prefs = append(prefs, o)
+			}
+			continue
+		}
+		prefs = append(prefs, p)
+	}
+	return prefs
+}
+
+func originalLanguage(a *MangaAttributes) string {
+	if a == nil || a.OriginalLanguage == nil {
+		return ""
+	}
+	return *a.OriginalLanguage
+}
+
+// anyValue picks a deterministic fallback from s: the original language,
+// then English, then the lexically first language with a value.
+func anyValue(s LocalizedString, original string) (string, string, bool) {
+	for _, want := range []string{original, "en"} {
+		if want == "" {
+			continue
+		}
+		for k, v := range s {
+			if v != "" && NormalizeLanguage(k) == NormalizeLanguage(want) {
+				return NormalizeLanguage(k), v, true
+			}
+		}
+	}
+	keys := make([]string, 0, len(s))
+	for k, v := range s {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return "", "", false
+	}
+	sort.Strings(keys)
+	return NormalizeLanguage(keys[0]), s[keys[0]], true
+}
+
+// NormalizeLanguage lower-cases a language tag and replaces "_" with "-".
+func NormalizeLanguage(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+}
+
+type languageTag struct {
+	base      string
+	romanized bool
+	rest      []string
+}
+
+func parseLanguage(tag string) languageTag {
+	parts := strings.Split(NormalizeLanguage(tag), "-")
+	t := languageTag{base: parts[0]}
+	for _, p := range parts[1:] {
+		if p == "ro" || p == "latn" {
+			t.romanized = true
+			continue
+		}
+		if p != "" {
+			t.rest = append(t.rest, p)
+		}
+	}
+	return t
+}
+
+// bestMatch returns the candidate language that best matches pref.
+func bestMatch(pref string, candidates map[string][]string) (string, bool) {
+	pref = NormalizeLanguage(pref)
+	if _, ok := candidates[pref]; ok {
+		return pref, true
+	}
+
+	p := parseLanguage(pref)
+	best, bestScore := "", 0
+	for lang := range candidates {
+		c := parseLanguage(lang)
+		if c.base != p.base || c.romanized != p.romanized {
+			continue
+		}
+		score := 1
+		switch {
+		case len(p.rest) == 0 && len(c.rest) == 0:
+			score = 3
+		case len(c.rest) == 0:
+			// regional preference, bare candidate: "en-us" -> "en"
+			score = 2
+		}
+		if score > bestScore || (score == bestScore && lang < best) {
+			best, bestScore = lang, score
+		}
+	}
+	return best, bestScore > 0
+}
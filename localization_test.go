@@ -0,0 +1,101 @@
+package mangadex
+
+import "testing"
+
+func TestBestMatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		pref       string
+		candidates []string
+		want       string
+	}{
+		{"exact", "en", []string{"en", "ja"}, "en"},
+		{"case and underscore", "PT_br", []string{"pt-br", "pt"}, "pt-br"},
+		{"bare preference picks a region", "pt", []string{"pt-br"}, "pt-br"},
+		{"bare preference prefers bare", "es", []string{"es-la", "es"}, "es"},
+		{"regional preference falls back to bare", "en-us", []string{"en", "en-gb"}, "en"},
+		{"other region when no bare", "en-us", []string{"en-gb"}, "en-gb"},
+		{"regions tie alphabetically", "es", []string{"es-mx", "es-la"}, "es-la"},
+		{"romanized is not the script", "ja", []string{"ja-ro"}, ""},
+		{"script is not romanized", "ja-ro", []string{"ja"}, ""},
+		{"romanized", "ko-ro", []string{"ko", "ko-ro"}, "ko-ro"},
+		{"latn counts as romanized", "zh-ro", []string{"zh-latn"}, "zh-latn"},
+		{"romanized region", "zh-ro", []string{"zh-hk", "zh-hk-ro"}, "zh-hk-ro"},
+		{"other language", "fr", []string{"en", "ja"}, ""},
+		{"no candidates", "en", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates := map[string][]string{}
+			for _, c := range tt.candidates {
+				candidates[c] = []string{c}
+			}
+			got, ok := bestMatch(tt.pref, candidates)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Fatalf("bestMatch(%q) = %q, %v; want %q", tt.pref, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalizerTitle(t *testing.T) {
+	manga := &Manga{Attributes: &MangaAttributes{
+		Title:            &LocalizedString{"ja-ro": "Shingeki no Kyojin"},
+		OriginalLanguage: String("ja"),
+		AltTitles: &[]LocalizedString{
+			{"en": "Attack on Titan"},
+			{"ja": "進撃の巨人"},
+			{"pt-br": "Ataque dos Titãs"},
+			{"en": "AoT"},
+		},
+	}}
+
+	tests := []struct {
+		name  string
+		prefs []string
+		title string
+		lang  string
+	}{
+		{"alt title", []string{"en"}, "Attack on Titan", "en"},
+		{"main title", []string{"ja-ro", "en"}, "Shingeki no Kyojin", "ja-ro"},
+		{"original", []string{LanguageOriginal, "en"}, "進撃の巨人", "ja"},
+		{"order of preference", []string{"fr", "pt", "en"}, "Ataque dos Titãs", "pt-br"},
+		{"falls back to main title", []string{"de"}, "Shingeki no Kyojin", "ja-ro"},
+		{"no preferences", nil, "Shingeki no Kyojin", "ja-ro"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, lang := NewLocalizer(tt.prefs...).Title(manga)
+			if title != tt.title || lang != tt.lang {
+				t.Fatalf("Title = %q (%s), want %q (%s)", title, lang, tt.title, tt.lang)
+			}
+		})
+	}
+}
+
+func TestLocalizerString(t *testing.T) {
+	s := LocalizedString{"fr": "Bonjour", "en": "Hello", "de": "", "es-la": "Hola"}
+	tests := []struct {
+		name  string
+		prefs []string
+		value string
+		lang  string
+	}{
+		{"preferred", []string{"fr"}, "Bonjour", "fr"},
+		{"empty values skipped", []string{"de", "es"}, "Hola", "es-la"},
+		{"english fallback", []string{"it"}, "Hello", "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, lang := NewLocalizer(tt.prefs...).String(s)
+			if value != tt.value || lang != tt.lang {
+				t.Fatalf("String = %q (%s), want %q (%s)", value, lang, tt.value, tt.lang)
+			}
+		})
+	}
+
+	// without English the lexically first language is used
+	if value, lang := NewLocalizer("it").String(LocalizedString{"fr": "Bonjour", "de": "Hallo"}); value != "Hallo" || lang != "de" {
+		t.Fatalf("fallback = %q (%s)", value, lang)
+	}
+}
@@ -0,0 +1,119 @@
+package opds
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+// Media types used by OPDS 1.2 catalogs.
+const (
+	TypeNavigation  = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	TypeAcquisition = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+	TypeOpenSearch  = "application/opensearchdescription+xml"
+	TypeCBZ         = "application/vnd.comicbook+zip"
+)
+
+// Link relations.
+const (
+	RelAcquisition = "http://opds-spec.org/acquisition"
+	RelImage       = "http://opds-spec.org/image"
+	RelThumbnail   = "http://opds-spec.org/image/thumbnail"
+	RelSortNew     = "http://opds-spec.org/sort/new"
+	RelSortPopular = "http://opds-spec.org/sort/popular"
+	RelPSEStream   = "http://vaemendis.net/opds-pse/stream"
+)
+
+type feed struct {
+	XMLName    xml.Name `xml:"feed"`
+	Xmlns      string   `xml:"xmlns,attr"`
+	OPDS       string   `xml:"xmlns:opds,attr"`
+	PSE        string   `xml:"xmlns:pse,attr"`
+	OpenSearch string   `xml:"xmlns:opensearch,attr"`
+	DC         string   `xml:"xmlns:dc,attr"`
+
+	ID      string  `xml:"id"`
+	Title   string  `xml:"title"`
+	Updated string  `xml:"updated"`
+	Author  *person `xml:"author,omitempty"`
+	Links   []link  `xml:"link"`
+
+	TotalResults int `xml:"opensearch:totalResults,omitempty"`
+	ItemsPerPage int `xml:"opensearch:itemsPerPage,omitempty"`
+	StartIndex   int `xml:"opensearch:startIndex,omitempty"`
+
+	Entries []entry `xml:"entry"`
+}
+
+type link struct {
+	Rel   string `xml:"rel,attr,omitempty"`
+	Href  string `xml:"href,attr"`
+	Type  string `xml:"type,attr,omitempty"`
+	Title string `xml:"title,attr,omitempty"`
+
+	// Count is the page count of an OPDS-PSE stream link.
+	Count int `xml:"pse:count,attr,omitempty"`
+}
+
+type person struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type category struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr,omitempty"`
+}
+
+type content struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type entry struct {
+	ID         string     `xml:"id"`
+	Title      string     `xml:"title"`
+	Updated    string     `xml:"updated"`
+	Authors    []person   `xml:"author,omitempty"`
+	Language   string     `xml:"dc:language,omitempty"`
+	Categories []category `xml:"category,omitempty"`
+	Content    *content   `xml:"content,omitempty"`
+	Links      []link     `xml:"link"`
+}
+
+func newFeed(id, title, updated string) *feed {
+	return &feed{
+		Xmlns:      "http://www.w3.org/2005/Atom",
+		OPDS:       "http://opds-spec.org/2010/catalog",
+		PSE:        "http://vaemendis.net/opds-pse/ns",
+		OpenSearch: "http://a9.com/-/spec/opensearch/1.1/",
+		DC:         "http://purl.org/dc/terms/",
+		ID:         id,
+		Title:      title,
+		Updated:    updated,
+	}
+}
+
+type openSearchDescription struct {
+	XMLName        xml.Name      `xml:"http://a9.com/-/spec/opensearch/1.1/ OpenSearchDescription"`
+	ShortName      string        `xml:"ShortName"`
+	Description    string        `xml:"Description"`
+	InputEncoding  string        `xml:"InputEncoding"`
+	OutputEncoding string        `xml:"OutputEncoding"`
+	URL            openSearchURL `xml:"Url"`
+}
+
+type openSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Template string `xml:"template,attr"`
+}
+
+func writeXML(w http.ResponseWriter, contentType string, v any) {
+	out, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType+";charset=utf-8")
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(out)
+}
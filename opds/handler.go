@@ -0,0 +1,631 @@
+// Package opds serves an OPDS 1.2 catalog of MangaDex for e-reader apps,
+// with CBZ acquisition links and OPDS-PSE page streaming.
+package opds
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Seann-Moser/mangadex"
+	"github.com/Seann-Moser/mangadex/export"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	DefaultPageSize = 20
+	chapterPageSize = 100
+)
+
+// Handler is an OPDS catalog. All links it emits are absolute paths below
+// BasePath, so it must be mounted there without stripping the prefix:
+//
+//	mux.Handle("/opds/", opds.NewHandler(client, "/opds"))
+type Handler struct {
+	Client    mangadex.ClientWithResponsesInterface
+	Exporter  *export.Exporter
+	Resolver  *mangadex.AtHomeResolver
+	Localizer *mangadex.Localizer
+	Covers    *mangadex.CoverURLs
+
+	// Languages restricts chapters to these translated languages and manga
+	// listings to titles available in them.
+	Languages []string
+
+	// Quality of streamed pages. Defaults to mangadex.QualityData.
+	Quality mangadex.PageQuality
+
+	// PageSize is the number of manga per navigation page.
+	PageSize int
+
+	// RequestEditors authenticates the followed feed for the requesting
+	// reader, e.g. by mapping HTTP basic auth to a stored user's tokens.
+	// The followed feed is not offered when nil.
+	RequestEditors func(r *http.Request) ([]mangadex.RequestEditorFn, error)
+
+	BasePath string
+	Title    string
+
+	mux *http.ServeMux
+}
+
+// NewHandler creates a catalog mounted at basePath, e.g. "/opds".
+func NewHandler(client mangadex.ClientWithResponsesInterface, basePath string) *Handler {
+	h := &Handler{
+		Client:    client,
+		Exporter:  export.NewExporter(client),
+		Resolver:  mangadex.NewAtHomeResolver(client),
+		Localizer: mangadex.NewLocalizer("en", "ja-ro", mangadex.LanguageOriginal),
+		Covers:    mangadex.NewCoverURLs(mangadex.DefaultUploadsHost),
+		Quality:   mangadex.QualityData,
+		PageSize:  DefaultPageSize,
+		BasePath:  "/" + strings.Trim(basePath, "/"),
+		Title:     "MangaDex",
+	}
+	if h.BasePath == "/" {
+		h.BasePath = ""
+	}
+
+	h.mux = http.NewServeMux()
+	h.mux.HandleFunc("GET "+h.BasePath+"/{$}", h.root)
+	h.mux.HandleFunc("GET "+h.BasePath+"/opensearch.xml", h.openSearch)
+	h.mux.HandleFunc("GET "+h.BasePath+"/latest", h.latest)
+	h.mux.HandleFunc("GET "+h.BasePath+"/popular", h.popular)
+	h.mux.HandleFunc("GET "+h.BasePath+"/followed", h.followed)
+	h.mux.HandleFunc("GET "+h.BasePath+"/search", h.search)
+	h.mux.HandleFunc("GET "+h.BasePath+"/tags", h.tags)
+	h.mux.HandleFunc("GET "+h.BasePath+"/tags/{id}", h.tag)
+	h.mux.HandleFunc("GET "+h.BasePath+"/manga/{id}", h.manga)
+	h.mux.HandleFunc("GET "+h.BasePath+"/chapter/{id}/cbz", h.cbz)
+	h.mux.HandleFunc("GET "+h.BasePath+"/chapter/{id}/page/{page}", h.page)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) link(p string, query ...string) string {
+	u := h.BasePath + p
+	if len(query) > 0 {
+		v := url.Values{}
+		for i := 0; i+1 < len(query); i += 2 {
+			v.Set(query[i], query[i+1])
+		}
+		u += "?" + v.Encode()
+	}
+	return u
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+func (h *Handler) root(w http.ResponseWriter, r *http.Request) {
+	f := newFeed("urn:mangadex:opds:root", h.Title, now())
+	f.Links = []link{
+		{Rel: "self", Href: h.link("/"), Type: TypeNavigation},
+		{Rel: "start", Href: h.link("/"), Type: TypeNavigation},
+		{Rel: "search", Href: h.link("/opensearch.xml"), Type: TypeOpenSearch},
+	}
+	add := func(id, title, summary, href, rel string) {
+		f.Entries = append(f.Entries, entry{
+			ID:      "urn:mangadex:opds:" + id,
+			Title:   title,
+			Updated: f.Updated,
+			Content: &content{Type: "text", Value: summary},
+			Links:   []link{{Rel: rel, Href: href, Type: TypeNavigation}},
+		})
+	}
+	add("latest", "Latest updates", "Manga with recently uploaded chapters", h.link("/latest"), RelSortNew)
+	add("popular", "Popular", "Most followed manga", h.link("/popular"), RelSortPopular)
+	if h.RequestEditors != nil {
+		add("followed", "Followed", "Manga you follow", h.link("/followed"), "subsection")
+	}
+	add("tags", "Tags", "Browse by genre, theme and format", h.link("/tags"), "subsection")
+	writeXML(w, TypeNavigation, f)
+}
+
+func (h *Handler) openSearch(w http.ResponseWriter, r *http.Request) {
+	writeXML(w, TypeOpenSearch, openSearchDescription{
+		ShortName:      h.Title,
+		Description:    "Search " + h.Title + " by title",
+		InputEncoding:  "UTF-8",
+		OutputEncoding: "UTF-8",
+		URL: openSearchURL{
+			Type:     TypeNavigation,
+			Template: h.link("/search") + "?q={searchTerms}",
+		},
+	})
+}
+
+// searchParams returns search parameters for page of the listing with an
+// empty order ready to be filled in.
+func (h *Handler) searchParams(page int) *mangadex.GetSearchMangaParams {
+	size := h.pageSize()
+	includes := mangadex.ReferenceExpansionManga{mangadex.RelationshipCoverArt, mangadex.RelationshipAuthor}
+	p := &mangadex.GetSearchMangaParams{
+		Limit:    mangadex.Int(size),
+		Offset:   mangadex.Int((page - 1) * size),
+		Includes: &includes,
+		Order: &struct {
+			CreatedAt             *mangadex.GetSearchMangaParamsOrderCreatedAt             `json:"createdAt,omitempty" bson:"createdAt"`
+			FollowedCount         *mangadex.GetSearchMangaParamsOrderFollowedCount         `json:"followedCount,omitempty" bson:"followedCount"`
+			LatestUploadedChapter *mangadex.GetSearchMangaParamsOrderLatestUploadedChapter `json:"latestUploadedChapter,omitempty" bson:"latestUploadedChapter"`
+			Rating                *mangadex.GetSearchMangaParamsOrderRating                `json:"rating,omitempty" bson:"rating"`
+			Relevance             *mangadex.GetSearchMangaParamsOrderRelevance             `json:"relevance,omitempty" bson:"relevance"`
+			Title                 *mangadex.GetSearchMangaParamsOrderTitle                 `json:"title,omitempty" bson:"title"`
+			UpdatedAt             *mangadex.GetSearchMangaParamsOrderUpdatedAt             `json:"updatedAt,omitempty" bson:"updatedAt"`
+			Year                  *mangadex.GetSearchMangaParamsOrderYear                  `json:"year,omitempty" bson:"year"`
+		}{},
+	}
+	if len(h.Languages) > 0 {
+		langs := append([]string(nil), h.Languages...)
+		p.AvailableTranslatedLanguage = &langs
+	}
+	return p
+}
+
+func (h *Handler) latest(w http.ResponseWriter, r *http.Request) {
+	page := pageOf(r)
+	p := h.searchParams(page)
+	desc := mangadex.GetSearchMangaParamsOrderLatestUploadedChapter("desc")
+	p.Order.LatestUploadedChapter = &desc
+	h.searchFeed(w, r, "latest", "Latest updates", page, p)
+}
+
+func (h *Handler) popular(w http.ResponseWriter, r *http.Request) {
+	page := pageOf(r)
+	p := h.searchParams(page)
+	desc := mangadex.GetSearchMangaParamsOrderFollowedCount("desc")
+	p.Order.FollowedCount = &desc
+	h.searchFeed(w, r, "popular", "Popular", page, p)
+}
+
+func (h *Handler) search(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		http.Error(w, "missing search terms", http.StatusBadRequest)
+		return
+	}
+	page := pageOf(r)
+	p := h.searchParams(page)
+	p.Title = &q
+	desc := mangadex.GetSearchMangaParamsOrderRelevance("desc")
+	p.Order.Relevance = &desc
+	h.searchFeed(w, r, "search", "Search: "+q, page, p, "q", q)
+}
+
+func (h *Handler) tag(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid tag id", http.StatusBadRequest)
+		return
+	}
+	page := pageOf(r)
+	p := h.searchParams(page)
+	p.IncludedTags = &[]openapi_types.UUID{id}
+	desc := mangadex.GetSearchMangaParamsOrderFollowedCount("desc")
+	p.Order.FollowedCount = &desc
+	title := "Tag"
+	if name := r.URL.Query().Get("name"); name != "" {
+		title = name
+	}
+	h.searchFeed(w, r, "tags/"+id.String(), title, page, p, "name", title)
+}
+
+func (h *Handler) searchFeed(w http.ResponseWriter, r *http.Request, id, title string, page int, p *mangadex.GetSearchMangaParams, query ...string) {
+	resp, err := h.Client.GetSearchMangaWithResponse(r.Context(), p)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if resp.JSON200 == nil {
+		http.Error(w, "search: "+resp.Status(), http.StatusBadGateway)
+		return
+	}
+	h.mangaFeed(w, id, title, page, resp.JSON200, query...)
+}
+
+func (h *Handler) followed(w http.ResponseWriter, r *http.Request) {
+	if h.RequestEditors == nil {
+		http.NotFound(w, r)
+		return
+	}
+	editors, err := h.RequestEditors(r)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Basic realm="`+h.Title+`"`)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	page := pageOf(r)
+	size := h.pageSize()
+	includes := mangadex.ReferenceExpansionManga{mangadex.RelationshipCoverArt, mangadex.RelationshipAuthor}
+	resp, err := h.Client.GetUserFollowsMangaWithResponse(r.Context(), &mangadex.GetUserFollowsMangaParams{
+		Limit:    mangadex.Int(size),
+		Offset:   mangadex.Int((page - 1) * size),
+		Includes: &includes,
+	}, editors...)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if resp.JSON200 == nil {
+		status := http.StatusBadGateway
+		if resp.StatusCode() == http.StatusUnauthorized || resp.StatusCode() == http.StatusForbidden {
+			status = resp.StatusCode()
+		}
+		http.Error(w, "follows: "+resp.Status(), status)
+		return
+	}
+	h.mangaFeed(w, "followed", "Followed", page, resp.JSON200)
+}
+
+// mangaFeed renders a page of manga as a navigation feed whose entries
+// lead to each manga's chapter list.
+func (h *Handler) mangaFeed(w http.ResponseWriter, id, title string, page int, list *mangadex.MangaList, query ...string) {
+	f := newFeed("urn:mangadex:opds:"+id, title, now())
+	self := h.link("/"+id, append(query, "page", strconv.Itoa(page))...)
+	f.Links = []link{
+		{Rel: "self", Href: self, Type: TypeNavigation},
+		{Rel: "start", Href: h.link("/"), Type: TypeNavigation},
+		{Rel: "search", Href: h.link("/opensearch.xml"), Type: TypeOpenSearch},
+	}
+	size := h.pageSize()
+	f.ItemsPerPage = size
+	f.StartIndex = (page-1)*size + 1
+	if list.Total != nil {
+		f.TotalResults = *list.Total
+		if page*size < *list.Total {
+			f.Links = append(f.Links, link{Rel: "next", Href: h.link("/"+id, append(query, "page", strconv.Itoa(page+1))...), Type: TypeNavigation})
+		}
+	}
+	if page > 1 {
+		f.Links = append(f.Links, link{Rel: "previous", Href: h.link("/"+id, append(query, "page", strconv.Itoa(page-1))...), Type: TypeNavigation})
+	}
+	if list.Data != nil {
+		for i := range *list.Data {
+			if e, ok := h.mangaEntry(&(*list.Data)[i]); ok {
+				f.Entries = append(f.Entries, e)
+			}
+		}
+	}
+	writeXML(w, TypeNavigation, f)
+}
+
+func (h *Handler) mangaEntry(m *mangadex.Manga) (entry, bool) {
+	if m.Id == nil {
+		return entry{}, false
+	}
+	title, _ := h.Localizer.Title(m)
+	e := entry{
+		ID:      "urn:uuid:" + m.Id.String(),
+		Title:   title,
+		Updated: now(),
+		Links:   []link{{Rel: "subsection", Href: h.link("/manga/" + m.Id.String()), Type: TypeAcquisition}},
+	}
+	if a := m.Attributes; a != nil {
+		if a.UpdatedAt != nil {
+			e.Updated = *a.UpdatedAt
+		}
+		if desc, _ := h.Localizer.Description(m); desc != "" {
+			e.Content = &content{Type: "text", Value: desc}
+		}
+		if a.Tags != nil {
+			for _, t := range *a.Tags {
+				if t.Attributes == nil || t.Attributes.Name == nil {
+					continue
+				}
+				name, _ := h.Localizer.String(*t.Attributes.Name)
+				e.Categories = append(e.Categories, category{Term: name, Label: name})
+			}
+		}
+	}
+	if authors, err := m.Authors(); err == nil {
+		for _, a := range authors {
+			if a.Attributes != nil && a.Attributes.Name != nil {
+				e.Authors = append(e.Authors, person{Name: *a.Attributes.Name})
+			}
+		}
+	}
+	if u, err := h.Covers.FromManga(m, mangadex.Cover512); err == nil {
+		e.Links = append(e.Links, link{Rel: RelImage, Href: u, Type: "image/jpeg"})
+	}
+	if u, err := h.Covers.FromManga(m, mangadex.Cover256); err == nil {
+		e.Links = append(e.Links, link{Rel: RelThumbnail, Href: u, Type: "image/jpeg"})
+	}
+	return e, true
+}
+
+func (h *Handler) tags(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.Client.GetMangaTagWithResponse(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if resp.JSON200 == nil || resp.JSON200.Data == nil {
+		http.Error(w, "tags: "+resp.Status(), http.StatusBadGateway)
+		return
+	}
+
+	f := newFeed("urn:mangadex:opds:tags", "Tags", now())
+	f.Links = []link{
+		{Rel: "self", Href: h.link("/tags"), Type: TypeNavigation},
+		{Rel: "start", Href: h.link("/"), Type: TypeNavigation},
+	}
+	for _, t := range *resp.JSON200.Data {
+		if t.Id == nil || t.Attributes == nil || t.Attributes.Name == nil {
+			continue
+		}
+		name, _ := h.Localizer.String(*t.Attributes.Name)
+		e := entry{
+			ID:      "urn:uuid:" + t.Id.String(),
+			Title:   name,
+			Updated: f.Updated,
+			Links:   []link{{Rel: "subsection", Href: h.link("/tags/"+t.Id.String(), "name", name), Type: TypeNavigation}},
+		}
+		if t.Attributes.Group != nil {
+			e.Content = &content{Type: "text", Value: string(*t.Attributes.Group)}
+		}
+		f.Entries = append(f.Entries, e)
+	}
+	sort.Slice(f.Entries, func(i, j int) bool { return f.Entries[i].Title < f.Entries[j].Title })
+	writeXML(w, TypeNavigation, f)
+}
+
+func (h *Handler) manga(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid manga id", http.StatusBadRequest)
+		return
+	}
+	ctx := r.Context()
+	includes := mangadex.ReferenceExpansionManga{mangadex.RelationshipCoverArt, mangadex.RelationshipAuthor}
+	mresp, err := h.Client.GetMangaIdWithResponse(ctx, id, &mangadex.GetMangaIdParams{Includes: &includes})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if mresp.JSON200 == nil || mresp.JSON200.Data == nil {
+		status := http.StatusBadGateway
+		if mresp.StatusCode() == http.StatusNotFound {
+			status = http.StatusNotFound
+		}
+		http.Error(w, "manga: "+mresp.Status(), status)
+		return
+	}
+	m := mresp.JSON200.Data
+	title, _ := h.Localizer.Title(m)
+
+	page := pageOf(r)
+	list, err := h.chapters(ctx, id, page)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	f := newFeed("urn:uuid:"+id.String(), title, now())
+	self := h.link("/manga/"+id.String(), "page", strconv.Itoa(page))
+	f.Links = []link{
+		{Rel: "self", Href: self, Type: TypeAcquisition},
+		{Rel: "start", Href: h.link("/"), Type: TypeNavigation},
+		{Rel: "up", Href: h.link("/"), Type: TypeNavigation},
+	}
+	if u, err := h.Covers.FromManga(m, mangadex.Cover256); err == nil {
+		f.Links = append(f.Links, link{Rel: RelThumbnail, Href: u, Type: "image/jpeg"})
+	}
+	f.ItemsPerPage = chapterPageSize
+	f.StartIndex = (page-1)*chapterPageSize + 1
+	if list.Total != nil {
+		f.TotalResults = *list.Total
+		if page*chapterPageSize < *list.Total {
+			f.Links = append(f.Links, link{Rel: "next", Href: h.link("/manga/"+id.String(), "page", strconv.Itoa(page+1)), Type: TypeAcquisition})
+		}
+	}
+	if page > 1 {
+		f.Links = append(f.Links, link{Rel: "previous", Href: h.link("/manga/"+id.String(), "page", strconv.Itoa(page-1)), Type: TypeAcquisition})
+	}
+
+	var thumb string
+	if u, err := h.Covers.FromManga(m, mangadex.Cover256); err == nil {
+		thumb = u
+	}
+	if list.Data != nil {
+		for _, c := range *list.Data {
+			if e, ok := h.chapterEntry(c, thumb); ok {
+				f.Entries = append(f.Entries, e)
+			}
+		}
+	}
+	writeXML(w, TypeAcquisition, f)
+}
+
+func (h *Handler) chapters(ctx context.Context, mangaID openapi_types.UUID, page int) (*mangadex.ChapterList, error) {
+	includes := mangadex.ReferenceExpansionChapter{mangadex.RelationshipScanlationGroup}
+	params := &mangadex.GetMangaIdFeedParams{
+		Limit:    mangadex.Int(chapterPageSize),
+		Offset:   mangadex.Int((page - 1) * chapterPageSize),
+		Includes: &includes,
+	}
+	if len(h.Languages) > 0 {
+		langs := append([]string(nil), h.Languages...)
+		params.TranslatedLanguage = &langs
+	}
+	vol := mangadex.GetMangaIdFeedParamsOrderVolume("asc")
+	ch := mangadex.GetMangaIdFeedParamsOrderChapter("asc")
+	params.Order = &struct {
+		Chapter    *mangadex.GetMangaIdFeedParamsOrderChapter    `json:"chapter,omitempty" bson:"chapter"`
+		CreatedAt  *mangadex.GetMangaIdFeedParamsOrderCreatedAt  `json:"createdAt,omitempty" bson:"createdAt"`
+		PublishAt  *mangadex.GetMangaIdFeedParamsOrderPublishAt  `json:"publishAt,omitempty" bson:"publishAt"`
+		ReadableAt *mangadex.GetMangaIdFeedParamsOrderReadableAt `json:"readableAt,omitempty" bson:"readableAt"`
+		UpdatedAt  *mangadex.GetMangaIdFeedParamsOrderUpdatedAt  `json:"updatedAt,omitempty" bson:"updatedAt"`
+		Volume     *mangadex.GetMangaIdFeedParamsOrderVolume     `json:"volume,omitempty" bson:"volume"`
+	}{Volume: &vol, Chapter: &ch}
+
+	resp, err := h.Client.GetMangaIdFeedWithResponse(ctx, mangaID, params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, fmt.Errorf("manga %s feed: %s", mangaID, resp.Status())
+	}
+	return resp.JSON200, nil
+}
+
+// chapterEntry renders a chapter with a CBZ acquisition link and an OPDS-PSE
+// stream link. External and unavailable chapters have no pages to offer and
+// are left out.
+func (h *Handler) chapterEntry(c mangadex.Chapter, thumb string) (entry, bool) {
+	if c.Id == nil || c.Attributes == nil {
+		return entry{}, false
+	}
+	a := c.Attributes
+	if (a.ExternalUrl != nil && *a.ExternalUrl != "") || (a.IsUnavailable != nil && *a.IsUnavailable) {
+		return entry{}, false
+	}
+	pages := 0
+	if a.Pages != nil {
+		pages = *a.Pages
+	}
+	if pages == 0 {
+		return entry{}, false
+	}
+
+	id := c.Id.String()
+	e := entry{
+		ID:      "urn:uuid:" + id,
+		Title:   chapterTitle(a),
+		Updated: now(),
+		Links: []link{
+			{Rel: RelAcquisition, Href: h.link("/chapter/" + id + "/cbz"), Type: TypeCBZ},
+			{Rel: RelPSEStream, Href: h.link("/chapter/"+id+"/page/") + "{pageNumber}", Type: "image/jpeg", Count: pages},
+		},
+	}
+	if a.PublishAt != nil {
+		e.Updated = *a.PublishAt
+	}
+	if a.TranslatedLanguage != nil {
+		e.Language = *a.TranslatedLanguage
+	}
+	if groups, err := c.ScanlationGroups(); err == nil {
+		for _, g := range groups {
+			if g.Attributes != nil && g.Attributes.Name != nil {
+				e.Authors = append(e.Authors, person{Name: *g.Attributes.Name})
+			}
+		}
+	}
+	if thumb != "" {
+		e.Links = append(e.Links, link{Rel: RelThumbnail, Href: thumb, Type: "image/jpeg"})
+	}
+	return e, true
+}
+
+func chapterTitle(a *mangadex.ChapterAttributes) string {
+	var parts []string
+	if a.Volume != nil && *a.Volume != "" {
+		parts = append(parts, "Vol. "+*a.Volume)
+	}
+	if a.Chapter != nil && *a.Chapter != "" {
+		parts = append(parts, "Ch. "+*a.Chapter)
+	} else {
+		parts = append(parts, "Oneshot")
+	}
+	title := strings.Join(parts, " ")
+	if a.Title != nil && *a.Title != "" {
+		title += ": " + *a.Title
+	}
+	if a.TranslatedLanguage != nil {
+		title += " [" + *a.TranslatedLanguage + "]"
+	}
+	return title
+}
+
+// cbz streams a chapter archive built on the fly.
+func (h *Handler) cbz(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid chapter id", http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", TypeCBZ)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": id.String() + ".cbz"}))
+	cw := &countingWriter{w: w}
+	if err := h.Exporter.ChapterCBZ(r.Context(), id, cw); err != nil && cw.n == 0 {
+		w.Header().Del("Content-Disposition")
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	}
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// page streams a single page for OPDS-PSE. Page numbers start at 0.
+func (h *Handler) page(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid chapter id", http.StatusBadRequest)
+		return
+	}
+	n, err := strconv.Atoi(r.PathValue("page"))
+	if err != nil || n < 0 {
+		http.Error(w, "invalid page number", http.StatusBadRequest)
+		return
+	}
+	quality := h.Quality
+	if quality == "" {
+		quality = mangadex.QualityData
+	}
+	urls, err := h.Resolver.PageURLs(r.Context(), id, quality)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if n >= len(urls) {
+		http.NotFound(w, r)
+		return
+	}
+	data, err := h.Resolver.Fetch(r.Context(), urls[n])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	ct := mime.TypeByExtension(path.Ext(urls[n]))
+	if ct == "" {
+		ct = http.DetectContentType(data)
+	}
+	w.Header().Set("Content-Type", ct)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	_, _ = w.Write(data)
+}
+
+func (h *Handler) pageSize() int {
+	if h.PageSize <= 0 || h.PageSize > 100 {
+		return DefaultPageSize
+	}
+	return h.PageSize
+}
+
+func pageOf(r *http.Request) int {
+	if n, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && n > 0 {
+		return n
+	}
+	return 1
+}
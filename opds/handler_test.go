@@ -0,0 +1,284 @@
+package opds
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Seann-Moser/mangadex"
+	"github.com/google/uuid"
+)
+
+// fakeAPI serves the manga search, a single manga with its feed and the
+// tag list, recording the search queries it receives.
+type fakeAPI struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	searches []url.Values
+}
+
+func newFakeAPI(t *testing.T) *fakeAPI {
+	t.Helper()
+	f := &fakeAPI{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /manga", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.searches = append(f.searches, r.URL.Query())
+		f.mu.Unlock()
+		data := []mangadex.Manga{testManga(uuid.New(), "Berserk"), testManga(uuid.New(), "Vagabond")}
+		writeJSON(w, mangadex.MangaList{Data: &data, Total: mangadex.Int(45)})
+	})
+	mux.HandleFunc("GET /manga/{id}", func(w http.ResponseWriter, r *http.Request) {
+		m := testManga(uuid.MustParse(r.PathValue("id")), "Berserk")
+		writeJSON(w, mangadex.MangaResponse{Data: &m})
+	})
+	mux.HandleFunc("GET /manga/{id}/feed", func(w http.ResponseWriter, r *http.Request) {
+		data := []mangadex.Chapter{
+			testChapter("1", 20, nil),
+			testChapter("2", 18, mangadex.String("https://example.test/ch/2")),
+			testChapter("3", 0, nil),
+		}
+		writeJSON(w, mangadex.ChapterList{Data: &data, Total: mangadex.Int(len(data))})
+	})
+	mux.HandleFunc("GET /manga/tag", func(w http.ResponseWriter, r *http.Request) {
+		data := []mangadex.Tag{testTag("Romance"), testTag("Action")}
+		writeJSON(w, mangadex.TagResponse{Data: &data})
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeAPI) lastSearch() url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.searches) == 0 {
+		return nil
+	}
+	return f.searches[len(f.searches)-1]
+}
+
+func testManga(id uuid.UUID, title string) mangadex.Manga {
+	return mangadex.Manga{Id: &id, Attributes: &mangadex.MangaAttributes{
+		Title:     &mangadex.LocalizedString{"en": title},
+		UpdatedAt: mangadex.String("2024-03-01T10:00:00+00:00"),
+	}}
+}
+
+func testChapter(number string, pages int, external *string) mangadex.Chapter {
+	id := uuid.New()
+	return mangadex.Chapter{Id: &id, Attributes: &mangadex.ChapterAttributes{
+		Chapter:            mangadex.String(number),
+		TranslatedLanguage: mangadex.String("en"),
+		Pages:              mangadex.Int(pages),
+		ExternalUrl:        external,
+	}}
+}
+
+func testTag(name string) mangadex.Tag {
+	id := uuid.New()
+	return mangadex.Tag{Id: &id, Attributes: &mangadex.TagAttributes{Name: &mangadex.LocalizedString{"en": name}}}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (f *fakeAPI) handler(t *testing.T) *Handler {
+	t.Helper()
+	client, err := mangadex.NewClientWithResponses(f.URL, mangadex.WithHTTPClient(f.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewHandler(client, "/opds")
+}
+
+// testFeed decodes the parts of a feed the tests look at.
+type testFeed struct {
+	Title   string     `xml:"title"`
+	Links   []testLink `xml:"link"`
+	Total   int        `xml:"totalResults"`
+	Entries []struct {
+		Title string     `xml:"title"`
+		Links []testLink `xml:"link"`
+	} `xml:"entry"`
+}
+
+type testLink struct {
+	Rel   string `xml:"rel,attr"`
+	Href  string `xml:"href,attr"`
+	Type  string `xml:"type,attr"`
+	Count int    `xml:"count,attr"`
+}
+
+func (f testFeed) link(rel string) string {
+	for _, l := range f.Links {
+		if l.Rel == rel {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+func (f testFeed) titles() []string {
+	out := make([]string, len(f.Entries))
+	for i, e := range f.Entries {
+		out[i] = e.Title
+	}
+	return out
+}
+
+func TestHandler(t *testing.T) {
+	api := newFakeAPI(t)
+	h := api.handler(t)
+	manga := uuid.NewString()
+
+	tests := []struct {
+		name   string
+		target string
+		status int
+		ctype  string
+		check  func(t *testing.T, body string)
+	}{
+		{
+			name:   "root",
+			target: "/opds/",
+			status: http.StatusOK,
+			ctype:  TypeNavigation,
+			check: func(t *testing.T, body string) {
+				f := decodeFeed(t, body)
+				// the followed feed needs RequestEditors
+				if want := []string{"Latest updates", "Popular", "Tags"}; !slices.Equal(f.titles(), want) {
+					t.Fatalf("entries = %v, want %v", f.titles(), want)
+				}
+				if got := f.link("search"); got != "/opds/opensearch.xml" {
+					t.Fatalf("search link = %q", got)
+				}
+			},
+		},
+		{
+			name:   "opensearch",
+			target: "/opds/opensearch.xml",
+			status: http.StatusOK,
+			ctype:  TypeOpenSearch,
+			check: func(t *testing.T, body string) {
+				if !strings.Contains(body, `template="/opds/search?q={searchTerms}"`) {
+					t.Fatalf("no search template:\n%s", body)
+				}
+			},
+		},
+		{
+			name:   "latest",
+			target: "/opds/latest?page=2",
+			status: http.StatusOK,
+			ctype:  TypeNavigation,
+			check: func(t *testing.T, body string) {
+				f := decodeFeed(t, body)
+				if q := api.lastSearch(); q.Get("offset") != "20" || q.Get("order[latestUploadedChapter]") != "desc" {
+					t.Fatalf("search query = %v", q)
+				}
+				if f.Total != 45 || f.link("next") != "/opds/latest?page=3" || f.link("previous") != "/opds/latest?page=1" {
+					t.Fatalf("total %d, next %q, previous %q", f.Total, f.link("next"), f.link("previous"))
+				}
+				if want := []string{"Berserk", "Vagabond"}; !slices.Equal(f.titles(), want) {
+					t.Fatalf("entries = %v, want %v", f.titles(), want)
+				}
+			},
+		},
+		{
+			name:   "last page",
+			target: "/opds/popular?page=3",
+			status: http.StatusOK,
+			ctype:  TypeNavigation,
+			check: func(t *testing.T, body string) {
+				if f := decodeFeed(t, body); f.link("next") != "" {
+					t.Fatalf("next = %q on the last page", f.link("next"))
+				}
+			},
+		},
+		{
+			name:   "search",
+			target: "/opds/search?q=+berserk+",
+			status: http.StatusOK,
+			ctype:  TypeNavigation,
+			check: func(t *testing.T, body string) {
+				f := decodeFeed(t, body)
+				if q := api.lastSearch(); q.Get("title") != "berserk" {
+					t.Fatalf("search query = %v", q)
+				}
+				if f.Title != "Search: berserk" || f.link("next") != "/opds/search?page=2&q=berserk" {
+					t.Fatalf("title %q, next %q", f.Title, f.link("next"))
+				}
+			},
+		},
+		{
+			name:   "tags",
+			target: "/opds/tags",
+			status: http.StatusOK,
+			ctype:  TypeNavigation,
+			check: func(t *testing.T, body string) {
+				if f := decodeFeed(t, body); !slices.Equal(f.titles(), []string{"Action", "Romance"}) {
+					t.Fatalf("entries = %v, want sorted tags", f.titles())
+				}
+			},
+		},
+		{
+			name:   "manga",
+			target: "/opds/manga/" + manga,
+			status: http.StatusOK,
+			ctype:  TypeAcquisition,
+			check: func(t *testing.T, body string) {
+				f := decodeFeed(t, body)
+				// external and empty chapters are left out
+				if want := []string{"Ch. 1 [en]"}; !slices.Equal(f.titles(), want) {
+					t.Fatalf("entries = %v, want %v", f.titles(), want)
+				}
+				links := f.Entries[0].Links
+				if len(links) < 2 || links[0].Rel != RelAcquisition || links[0].Type != TypeCBZ || !strings.HasSuffix(links[0].Href, "/cbz") {
+					t.Fatalf("acquisition link = %+v", links)
+				}
+				if links[1].Rel != RelPSEStream || links[1].Count != 20 || !strings.HasSuffix(links[1].Href, "/page/{pageNumber}") {
+					t.Fatalf("stream link = %+v", links[1])
+				}
+			},
+		},
+		{name: "followed without editors", target: "/opds/followed", status: http.StatusNotFound},
+		{name: "search without terms", target: "/opds/search?q=+", status: http.StatusBadRequest},
+		{name: "bad tag id", target: "/opds/tags/romance", status: http.StatusBadRequest},
+		{name: "bad manga id", target: "/opds/manga/berserk", status: http.StatusBadRequest},
+		{name: "bad chapter id", target: "/opds/chapter/1/cbz", status: http.StatusBadRequest},
+		{name: "bad page number", target: "/opds/chapter/" + uuid.NewString() + "/page/-1", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.ctype != "" && !strings.HasPrefix(rec.Header().Get("Content-Type"), tt.ctype) {
+				t.Fatalf("Content-Type = %q", rec.Header().Get("Content-Type"))
+			}
+			if tt.check != nil {
+				tt.check(t, rec.Body.String())
+			}
+		})
+	}
+}
+
+func decodeFeed(t *testing.T, body string) testFeed {
+	t.Helper()
+	var f testFeed
+	if err := xml.Unmarshal([]byte(body), &f); err != nil {
+		t.Fatalf("decode feed: %v\n%s", err, body)
+	}
+	return f
+}
@@ -0,0 +1,149 @@
+package mangadex
+
+import (
+	"encoding/json"
+	"fmt"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// Relationship types returned by the API and accepted by includes[].
+const (
+	RelationshipManga           = "manga"
+	RelationshipChapter         = "chapter"
+	RelationshipCoverArt        = "cover_art"
+	RelationshipAuthor          = "author"
+	RelationshipArtist          = "artist"
+	RelationshipScanlationGroup = "scanlation_group"
+	RelationshipUser            = "user"
+	RelationshipCreator         = "creator"
+	RelationshipLeader          = "leader"
+	RelationshipMember          = "member"
+)
+
+// Expanded is a relationship whose attributes have been decoded into T.
+// Attributes is nil when the relationship was not reference-expanded.
+type Expanded[T any] struct {
+	Id         openapi_types.UUID
+	Type       string
+	Related    *RelationshipRelated
+	Attributes *T
+}
+
+// DecodeRelationship decodes the expanded attributes of rel into T.
+// It returns nil, nil when rel carries no attributes. Each call decodes
+// afresh, so callers own the result and see later changes to the map.
+func DecodeRelationship[T any](rel Relationship) (*T, error) {
+	if rel.Attributes == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(*rel.Attributes)
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s relationship attributes: %w", relationshipType(rel), err)
+	}
+	out := new(T)
+	if err := json.Unmarshal(raw, out); err != nil {
+		return nil, fmt.Errorf("decoding %s relationship attributes: %w", relationshipType(rel), err)
+	}
+	return out, nil
+}
+
+// FindRelationships returns every relationship in rels with the given type,
+// decoding expanded attributes into T.
+func FindRelationships[T any](rels *[]Relationship, relType string) ([]Expanded[T], error) {
+	if rels == nil {
+		return nil, nil
+	}
+	var out []Expanded[T]
+	for _, rel := range *rels {
+		if relationshipType(rel) != relType {
+			continue
+		}
+		attrs, err := DecodeRelationship[T](rel)
+		if err != nil {
+			return nil, err
+		}
+		e := Expanded[T]{Type: relType, Related: rel.Related, Attributes: attrs}
+		if rel.Id != nil {
+			e.Id = *rel.Id
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// FindRelationship returns the first relationship in rels with the given type.
+func FindRelationship[T any](rels *[]Relationship, relType string) (*Expanded[T], error) {
+	all, err := FindRelationships[T](rels, relType)
+	if err != nil || len(all) == 0 {
+		return nil, err
+	}
+	return &all[0], nil
+}
+
+func relationshipType(rel Relationship) string {
+	if rel.Type == nil {
+		return ""
+	}
+	return *rel.Type
+}
+
+// CoverArt returns the manga's cover_art relationship.
+func (m *Manga) CoverArt() (*Expanded[CoverAttributes], error) {
+	return FindRelationship[CoverAttributes](m.Relationships, RelationshipCoverArt)
+}
+
+// Authors returns the manga's author relationships.
+func (m *Manga) Authors() ([]Expanded[AuthorAttributes], error) {
+	return FindRelationships[AuthorAttributes](m.Relationships, RelationshipAuthor)
+}
+
+// Artists returns the manga's artist relationships.
+func (m *Manga) Artists() ([]Expanded[AuthorAttributes], error) {
+	return FindRelationships[AuthorAttributes](m.Relationships, RelationshipArtist)
+}
+
+// RelatedManga returns the manga's related manga relationships.
+func (m *Manga) RelatedManga() ([]Expanded[MangaAttributes], error) {
+	return FindRelationships[MangaAttributes](m.Relationships, RelationshipManga)
+}
+
+// Creator returns the user that created the manga entry.
+func (m *Manga) Creator() (*Expanded[UserAttributes], error) {
+	return FindRelationship[UserAttributes](m.Relationships, RelationshipCreator)
+}
+
+// Manga returns the manga the chapter belongs to.
+func (c *Chapter) Manga() (*Expanded[MangaAttributes], error) {
+	return FindRelationship[MangaAttributes](c.Relationships, RelationshipManga)
+}
+
+// ScanlationGroups returns the groups credited for the chapter.
+func (c *Chapter) ScanlationGroups() ([]Expanded[ScanlationGroupAttributes], error) {
+	return FindRelationships[ScanlationGroupAttributes](c.Relationships, RelationshipScanlationGroup)
+}
+
+// Uploader returns the user that uploaded the chapter.
+func (c *Chapter) Uploader() (*Expanded[UserAttributes], error) {
+	return FindRelationship[UserAttributes](c.Relationships, RelationshipUser)
+}
+
+// Manga returns the manga the cover belongs to.
+func (c *Cover) Manga() (*Expanded[MangaAttributes], error) {
+	return FindRelationship[MangaAttributes](c.Relationships, RelationshipManga)
+}
+
+// Uploader returns the user that uploaded the cover.
+func (c *Cover) Uploader() (*Expanded[UserAttributes], error) {
+	return FindRelationship[UserAttributes](c.Relationships, RelationshipUser)
+}
+
+// Manga returns the manga in the list.
+func (l *CustomList) Manga() ([]Expanded[MangaAttributes], error) {
+	return FindRelationships[MangaAttributes](l.Relationships, RelationshipManga)
+}
+
+// Owner returns the user that owns the list.
+func (l *CustomList) Owner() (*Expanded[UserAttributes], error) {
+	return FindRelationship[UserAttributes](l.Relationships, RelationshipUser)
+}
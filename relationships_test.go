@@ -0,0 +1,103 @@
+package mangadex
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodeRelationship(t *testing.T) {
+	tests := []struct {
+		name    string
+		rel     string
+		want    string
+		wantNil bool
+		wantErr bool
+	}{
+		{"expanded", `{"type":"author","attributes":{"name":"Oda Eiichiro"}}`, "Oda Eiichiro", false, false},
+		{"unknown fields ignored", `{"type":"author","attributes":{"name":"Miura","imageUrl":null,"extra":[1]}}`, "Miura", false, false},
+		{"not expanded", `{"type":"author"}`, "", true, false},
+		{"null attributes", `{"type":"author","attributes":null}`, "", true, false},
+		{"wrong shape", `{"type":"author","attributes":{"name":42}}`, "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rel Relationship
+			if err := json.Unmarshal([]byte(tt.rel), &rel); err != nil {
+				t.Fatal(err)
+			}
+			got, err := DecodeRelationship[AuthorAttributes](rel)
+			switch {
+			case tt.wantErr:
+				if err == nil {
+					t.Fatalf("decoded %+v, want an error", got)
+				}
+			case err != nil:
+				t.Fatal(err)
+			case tt.wantNil:
+				if got != nil {
+					t.Fatalf("got %+v, want nil", got)
+				}
+			case got == nil || got.Name == nil || *got.Name != tt.want:
+				t.Fatalf("got %+v, want name %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeRelationshipIsolated(t *testing.T) {
+	var rel Relationship
+	if err := json.Unmarshal([]byte(`{"type":"author","attributes":{"name":"Miura"}}`), &rel); err != nil {
+		t.Fatal(err)
+	}
+	first, err := DecodeRelationship[AuthorAttributes](rel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	*first.Name = "changed"
+	(*rel.Attributes)["twitter"] = "https://twitter.com/example"
+
+	again, err := DecodeRelationship[AuthorAttributes](rel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again == first || *again.Name != "Miura" {
+		t.Fatalf("decode shares the earlier result: %+v", again)
+	}
+	if again.Twitter == nil || *again.Twitter != "https://twitter.com/example" {
+		t.Fatalf("decode ignores map changes: %+v", again)
+	}
+}
+
+func TestFindRelationships(t *testing.T) {
+	var m Manga
+	if err := json.Unmarshal([]byte(`{"relationships":[
+		{"id":"9fe70d7c-2de7-4d67-9f8b-1f9d3b8e8e1e","type":"author","attributes":{"name":"Author"}},
+		{"id":"0bd1a8f3-8b47-4d5e-9a31-3bdc5a7a1e9c","type":"artist"},
+		{"id":"5c5ab3f3-8a1a-4c8e-8c3e-6d4e0c8e7b2d","type":"author"},
+		{"id":"1b4c1c3e-7a84-46fd-b4df-9e5a9f6c4d1a","type":"manga","related":"sequel"}
+	]}`), &m); err != nil {
+		t.Fatal(err)
+	}
+
+	authors, err := m.Authors()
+	if err != nil || len(authors) != 2 {
+		t.Fatalf("authors = %+v, %v", authors, err)
+	}
+	if authors[0].Attributes == nil || *authors[0].Attributes.Name != "Author" || authors[1].Attributes != nil {
+		t.Fatalf("authors = %+v", authors)
+	}
+	if authors[1].Id.String() != "5c5ab3f3-8a1a-4c8e-8c3e-6d4e0c8e7b2d" || authors[1].Type != RelationshipAuthor {
+		t.Fatalf("second author = %+v", authors[1])
+	}
+
+	related, err := m.RelatedManga()
+	if err != nil || len(related) != 1 || related[0].Related == nil || *related[0].Related != "sequel" {
+		t.Fatalf("related = %+v, %v", related, err)
+	}
+	if art, err := m.CoverArt(); art != nil || err != nil {
+		t.Fatalf("cover art = %+v, %v", art, err)
+	}
+	if none, err := (&Manga{}).Authors(); none != nil || err != nil {
+		t.Fatalf("no relationships: %+v, %v", none, err)
+	}
+}
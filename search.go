@@ -0,0 +1,465 @@
+package mangadex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// maxSearchWindow is the largest offset+limit the API serves.
+const maxSearchWindow = 10000
+
+// YearNone matches manga without a release year.
+const YearNone = -1
+
+// SortDirection is the direction of a search order key.
+type SortDirection string
+
+const (
+	Asc  SortDirection = "asc"
+	Desc SortDirection = "desc"
+)
+
+// SearchOrderKey is a field manga searches can be ordered by.
+type SearchOrderKey string
+
+const (
+	SearchOrderTitle                 SearchOrderKey = "title"
+	SearchOrderYear                  SearchOrderKey = "year"
+	SearchOrderCreatedAt             SearchOrderKey = "createdAt"
+	SearchOrderUpdatedAt             SearchOrderKey = "updatedAt"
+	SearchOrderLatestUploadedChapter SearchOrderKey = "latestUploadedChapter"
+	SearchOrderFollowedCount         SearchOrderKey = "followedCount"
+	SearchOrderRelevance             SearchOrderKey = "relevance"
+	SearchOrderRating                SearchOrderKey = "rating"
+)
+
+// SearchOrder is one key of a multi-key ordering.
+type SearchOrder struct {
+	Key       SearchOrderKey
+	Direction SortDirection
+}
+
+// TagResolver maps a tag name to its id. The tag catalogue implements it.
+type TagResolver interface {
+	TagID(name string) (openapi_types.UUID, bool)
+}
+
+var ErrUnknownTag = errors.New("unknown tag")
+
+// SearchBuilder builds manga search parameters. Setters record invalid
+// values, which Build reports together, so calls can be chained freely:
+//
+//	q, err := mangadex.NewSearch().
+//		Title("frieren").
+//		IncludeTags("Fantasy", "Adventure").
+//		ContentRatings("safe", "suggestive").
+//		OrderBy(mangadex.SearchOrderFollowedCount, mangadex.Desc).
+//		Build()
+type SearchBuilder struct {
+	title            string
+	year             *int
+	included         []string
+	excluded         []string
+	includedMode     string
+	excludedMode     string
+	ratings          []string
+	demographics     []string
+	statuses         []string
+	original         []string
+	excludedOriginal []string
+	available        []string
+	authors          []openapi_types.UUID
+	artists          []openapi_types.UUID
+	ids              []openapi_types.UUID
+	group            *openapi_types.UUID
+	hasChapters      *bool
+	order            []SearchOrder
+	includes         []string
+	limit            int
+	offset           int
+	tags             TagResolver
+	errs             []error
+}
+
+func NewSearch() *SearchBuilder {
+	return &SearchBuilder{}
+}
+
+func (b *SearchBuilder) fail(format string, args ...any) *SearchBuilder {
+	b.errs = append(b.errs, fmt.Errorf(format, args...))
+	return b
+}
+
+func (b *SearchBuilder) Title(title string) *SearchBuilder {
+	b.title = strings.TrimSpace(title)
+	return b
+}
+
+// Year filters by release year; pass YearNone for manga without one.
+func (b *SearchBuilder) Year(year int) *SearchBuilder {
+	if year != YearNone && (year < 1 || year > 9999) {
+		return b.fail("year %d out of range", year)
+	}
+	b.year = &year
+	return b
+}
+
+// WithTagResolver sets how tag names are turned into ids. Tags given as
+// UUIDs never need resolving.
+func (b *SearchBuilder) WithTagResolver(r TagResolver) *SearchBuilder {
+	b.tags = r
+	return b
+}
+
+// IncludeTags requires tags, by name or id.
+func (b *SearchBuilder) IncludeTags(tags ...string) *SearchBuilder {
+	b.included = append(b.included, tags...)
+	return b
+}
+
+// ExcludeTags rejects tags, by name or id.
+func (b *SearchBuilder) ExcludeTags(tags ...string) *SearchBuilder {
+	b.excluded = append(b.excluded, tags...)
+	return b
+}
+
+// IncludedTagsMode is "AND" (all included tags, the API default) or "OR".
+func (b *SearchBuilder) IncludedTagsMode(mode string) *SearchBuilder {
+	mode = strings.ToUpper(mode)
+	if mode != "AND" && mode != "OR" {
+		return b.fail("included tags mode %q must be AND or OR", mode)
+	}
+	b.includedMode = mode
+	return b
+}
+
+// ExcludedTagsMode is "OR" (any excluded tag, the API default) or "AND".
+func (b *SearchBuilder) ExcludedTagsMode(mode string) *SearchBuilder {
+	mode = strings.ToUpper(mode)
+	if mode != "AND" && mode != "OR" {
+		return b.fail("excluded tags mode %q must be AND or OR", mode)
+	}
+	b.excludedMode = mode
+	return b
+}
+
+func (b *SearchBuilder) ContentRatings(ratings ...string) *SearchBuilder {
+	return b.enum(&b.ratings, "content rating", ratings, "safe", "suggestive", "erotica", "pornographic")
+}
+
+func (b *SearchBuilder) Demographics(demographics ...string) *SearchBuilder {
+	return b.enum(&b.demographics, "demographic", demographics, "shounen", "shoujo", "josei", "seinen", "none")
+}
+
+func (b *SearchBuilder) Status(statuses ...string) *SearchBuilder {
+	return b.enum(&b.statuses, "status", statuses, "ongoing", "completed", "hiatus", "cancelled")
+}
+
+func (b *SearchBuilder) OriginalLanguages(languages ...string) *SearchBuilder {
+	b.original = append(b.original, languages...)
+	return b
+}
+
+func (b *SearchBuilder) ExcludedOriginalLanguages(languages ...string) *SearchBuilder {
+	b.excludedOriginal = append(b.excludedOriginal, languages...)
+	return b
+}
+
+// AvailableLanguages keeps manga with chapters translated to any of languages.
+func (b *SearchBuilder) AvailableLanguages(languages ...string) *SearchBuilder {
+	b.available = append(b.available, languages...)
+	return b
+}
+
+func (b *SearchBuilder) Authors(ids ...openapi_types.UUID) *SearchBuilder {
+	b.authors = append(b.authors, ids...)
+	return b
+}
+
+func (b *SearchBuilder) Artists(ids ...openapi_types.UUID) *SearchBuilder {
+	b.artists = append(b.artists, ids...)
+	return b
+}
+
+func (b *SearchBuilder) IDs(ids ...openapi_types.UUID) *SearchBuilder {
+	b.ids = append(b.ids, ids...)
+	return b
+}
+
+func (b *SearchBuilder) Group(id openapi_types.UUID) *SearchBuilder {
+	b.group = &id
+	return b
+}
+
+func (b *SearchBuilder) HasAvailableChapters(has bool) *SearchBuilder {
+	b.hasChapters = &has
+	return b
+}
+
+// OrderBy appends an order key; earlier keys take precedence.
+func (b *SearchBuilder) OrderBy(key SearchOrderKey, dir SortDirection) *SearchBuilder {
+	switch key {
+	case SearchOrderTitle, SearchOrderYear, SearchOrderCreatedAt, SearchOrderUpdatedAt, SearchOrderLatestUploadedChapter,
+		SearchOrderFollowedCount, SearchOrderRelevance, SearchOrderRating:
+	default:
+		return b.fail("unknown order key %q", key)
+	}
+	if dir != Asc && dir != Desc {
+		return b.fail("order direction %q must be asc or desc", dir)
+	}
+	for _, o := range b.order {
+		if o.Key == key {
+			return b.fail("order key %q given twice", key)
+		}
+	}
+	b.order = append(b.order, SearchOrder{Key: key, Direction: dir})
+	return b
+}
+
+// Include expands relationships, e.g. RelationshipCoverArt.
+func (b *SearchBuilder) Include(relationships ...string) *SearchBuilder {
+	return b.enum(&b.includes, "include", relationships,
+		RelationshipManga, RelationshipCoverArt, RelationshipAuthor, RelationshipArtist, "tag", RelationshipCreator)
+}
+
+func (b *SearchBuilder) Limit(limit int) *SearchBuilder {
+	if limit < 1 || limit > 100 {
+		return b.fail("limit %d must be between 1 and 100", limit)
+	}
+	b.limit = limit
+	return b
+}
+
+func (b *SearchBuilder) Offset(offset int) *SearchBuilder {
+	if offset < 0 {
+		return b.fail("offset %d must not be negative", offset)
+	}
+	b.offset = offset
+	return b
+}
+
+func (b *SearchBuilder) enum(dst *[]string, what string, values []string, allowed ...string) *SearchBuilder {
+next:
+	for _, v := range values {
+		for _, a := range allowed {
+			if strings.EqualFold(v, a) {
+				*dst = append(*dst, a)
+				continue next
+			}
+		}
+		b.fail("unknown %s %q", what, v)
+	}
+	return b
+}
+
+// Build validates the search and produces the query.
+func (b *SearchBuilder) Build() (*SearchQuery, error) {
+	errs := append([]error(nil), b.errs...)
+
+	included, err := b.resolveTags(b.included)
+	errs = append(errs, err...)
+	excluded, err := b.resolveTags(b.excluded)
+	errs = append(errs, err...)
+	for _, id := range included {
+		for _, ex := range excluded {
+			if id == ex {
+				errs = append(errs, fmt.Errorf("tag %s is both included and excluded", id))
+			}
+		}
+	}
+	if b.includedMode != "" && len(included) == 0 {
+		errs = append(errs, errors.New("included tags mode set without included tags"))
+	}
+	if b.excludedMode != "" && len(excluded) == 0 {
+		errs = append(errs, errors.New("excluded tags mode set without excluded tags"))
+	}
+	for _, o := range b.order {
+		if o.Key == SearchOrderRelevance && b.title == "" {
+			errs = append(errs, errors.New("relevance ordering requires a title"))
+		}
+	}
+	if len(b.ids) > 100 {
+		errs = append(errs, fmt.Errorf("%d ids given, at most 100 allowed", len(b.ids)))
+	}
+	limit := b.limit
+	if limit == 0 {
+		limit = 10
+	}
+	if b.offset+limit > maxSearchWindow {
+		errs = append(errs, fmt.Errorf("offset %d + limit %d exceeds the %d result window", b.offset, limit, maxSearchWindow))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	q := &SearchQuery{Order: append([]SearchOrder(nil), b.order...)}
+	p := &q.Params
+	p.Title = String(b.title)
+	p.Limit = Int(b.limit)
+	p.Offset = Int(b.offset)
+	if b.year != nil {
+		q.Year = strconv.Itoa(*b.year)
+		if *b.year == YearNone {
+			q.Year = "none"
+		}
+	}
+	if len(included) > 0 {
+		p.IncludedTags = &included
+	}
+	if len(excluded) > 0 {
+		p.ExcludedTags = &excluded
+	}
+	if b.includedMode != "" {
+		m := GetSearchMangaParamsIncludedTagsMode(b.includedMode)
+		p.IncludedTagsMode = &m
+	}
+	if b.excludedMode != "" {
+		m := GetSearchMangaParamsExcludedTagsMode(b.excludedMode)
+		p.ExcludedTagsMode = &m
+	}
+	if len(b.ratings) > 0 {
+		v := make([]GetSearchMangaParamsContentRating, len(b.ratings))
+		for i, r := range b.ratings {
+			v[i] = GetSearchMangaParamsContentRating(r)
+		}
+		p.ContentRating = &v
+	}
+	if len(b.demographics) > 0 {
+		v := make([]GetSearchMangaParamsPublicationDemographic, len(b.demographics))
+		for i, d := range b.demographics {
+			v[i] = GetSearchMangaParamsPublicationDemographic(d)
+		}
+		p.PublicationDemographic = &v
+	}
+	if len(b.statuses) > 0 {
+		v := make([]GetSearchMangaParamsStatus, len(b.statuses))
+		for i, s := range b.statuses {
+			v[i] = GetSearchMangaParamsStatus(s)
+		}
+		p.Status = &v
+	}
+	p.OriginalLanguage = stringsPtr(b.original)
+	p.ExcludedOriginalLanguage = stringsPtr(b.excludedOriginal)
+	p.AvailableTranslatedLanguage = stringsPtr(b.available)
+	p.Authors = uuidsPtr(b.authors)
+	p.Artists = uuidsPtr(b.artists)
+	p.Ids = uuidsPtr(b.ids)
+	p.Group = b.group
+	if b.hasChapters != nil {
+		v := GetSearchMangaParamsHasAvailableChapters(strconv.FormatBool(*b.hasChapters))
+		p.HasAvailableChapters = &v
+	}
+	if len(b.includes) > 0 {
+		v := ReferenceExpansionManga(append([]string(nil), b.includes...))
+		p.Includes = &v
+	}
+	return q, nil
+}
+
+func (b *SearchBuilder) resolveTags(tags []string) ([]openapi_types.UUID, []error) {
+	var (
+		out  []openapi_types.UUID
+		errs []error
+		seen = map[openapi_types.UUID]bool{}
+	)
+	for _, t := range tags {
+		id, err := uuid.Parse(t)
+		if err != nil {
+			var ok bool
+			if b.tags != nil {
+				id, ok = b.tags.TagID(t)
+			}
+			if !ok {
+				errs = append(errs, fmt.Errorf("%w %q", ErrUnknownTag, t))
+				continue
+			}
+		}
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out, errs
+}
+
+// SearchQuery is a validated manga search. Params holds everything the
+// generated client can encode; Year and Order are applied by RequestEditor
+// because the client can neither set the year union nor keep order keys in
+// priority order.
+type SearchQuery struct {
+	Params GetSearchMangaParams
+
+	// Year is a release year or "none"; empty when unset.
+	Year  string
+	Order []SearchOrder
+}
+
+// RequestEditor adds the year and the ordered order[...] parameters to a
+// search request. Pass it with Params to GetSearchMangaWithResponse.
+func (q *SearchQuery) RequestEditor() RequestEditorFn {
+	return func(_ context.Context, req *http.Request) error {
+		req.URL.RawQuery = q.encode(req.URL.Query())
+		return nil
+	}
+}
+
+// encode renders values followed by the year and order keys in priority
+// order; url.Values.Encode would sort them by name.
+func (q *SearchQuery) encode(values url.Values) string {
+	values.Del("year")
+	for k := range values {
+		if strings.HasPrefix(k, "order[") {
+			values.Del(k)
+		}
+	}
+	if q.Year != "" {
+		values.Set("year", q.Year)
+	}
+	s := values.Encode()
+	for _, o := range q.Order {
+		if s != "" {
+			s += "&"
+		}
+		s += url.QueryEscape("order["+string(o.Key)+"]") + "=" + string(o.Direction)
+	}
+	return s
+}
+
+// Encode returns the query string of the search, e.g. for a /manga URL.
+func (q *SearchQuery) Encode() (string, error) {
+	req, err := NewGetSearchMangaRequest("http://localhost", &q.Params)
+	if err != nil {
+		return "", err
+	}
+	return q.encode(req.URL.Query()), nil
+}
+
+// Do runs the search.
+func (q *SearchQuery) Do(ctx context.Context, client ClientWithResponsesInterface, reqEditors ...RequestEditorFn) (*GetSearchMangaResponse, error) {
+	params := q.Params
+	return client.GetSearchMangaWithResponse(ctx, &params, append(reqEditors, q.RequestEditor())...)
+}
+
+func stringsPtr(v []string) *[]string {
+	if len(v) == 0 {
+		return nil
+	}
+	v = append([]string(nil), v...)
+	return &v
+}
+
+func uuidsPtr(v []openapi_types.UUID) *[]openapi_types.UUID {
+	if len(v) == 0 {
+		return nil
+	}
+	v = append([]openapi_types.UUID(nil), v...)
+	return &v
+}
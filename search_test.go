@@ -0,0 +1,183 @@
+package mangadex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	goCache "github.com/patrickmn/go-cache"
+)
+
+// newEchoSearchServer answers manga searches with the raw query string in
+// the response field and counts the requests it receives.
+func newEchoSearchServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var n atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(MangaList{Response: String(r.URL.RawQuery)})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &n
+}
+
+func TestSearchQueryThroughCachedClient(t *testing.T) {
+	srv, requests := newEchoSearchServer(t)
+	client, err := NewClientWithResponses(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached := NewCachedClientWithResponsesInterface(client, NewLocalCache(goCache.New(time.Minute, time.Minute)))
+	ctx := context.Background()
+
+	// the queries differ only in what RequestEditor applies
+	q1, err := NewSearch().Title("berserk").Year(1989).OrderBy(SearchOrderYear, Asc).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	q2, err := NewSearch().Title("berserk").Year(1990).OrderBy(SearchOrderTitle, Desc).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := map[string]bool{}
+	for _, q := range []*SearchQuery{q1, q2, q1} {
+		resp, err := q.Do(ctx, cached)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := q.Encode()
+		if resp.JSON200 == nil || resp.JSON200.Response == nil || *resp.JSON200.Response != want {
+			t.Fatalf("response for %q = %+v", want, resp.JSON200)
+		}
+		seen[want] = true
+	}
+	if len(seen) != 2 {
+		t.Fatalf("queries encoded alike: %v", seen)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Fatalf("%d requests, want 3: calls with request editors bypass the cache", got)
+	}
+
+	// calls without editors are still cached
+	params := GetSearchMangaParams{Title: String("vagabond")}
+	for i := 0; i < 2; i++ {
+		if _, err := cached.GetSearchMangaWithResponse(ctx, &params); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := requests.Load(); got != 4 {
+		t.Fatalf("%d requests, want 4", got)
+	}
+}
+
+// tagNames resolves tag names from a fixed map.
+type tagNames map[string]uuid.UUID
+
+func (m tagNames) TagID(name string) (uuid.UUID, bool) {
+	id, ok := m[strings.ToLower(name)]
+	return id, ok
+}
+
+const (
+	fantasyTag = "cdc58593-87dd-415e-bbc0-2ec27bf404cc"
+	romanceTag = "423e2eae-a7a2-4a8b-ac03-a8351462d71d"
+)
+
+var testTags = tagNames{"fantasy": uuid.MustParse(fantasyTag), "romance": uuid.MustParse(romanceTag)}
+
+func TestSearchBuilderEncode(t *testing.T) {
+	tests := []struct {
+		name  string
+		build *SearchBuilder
+		want  string
+	}{
+		{"title", NewSearch().Title("  frieren "), "title=frieren"},
+		{"year", NewSearch().Year(1989), "year=1989"},
+		{"year none", NewSearch().Year(YearNone), "year=none"},
+		{
+			"order keeps insertion order",
+			NewSearch().OrderBy(SearchOrderYear, Asc).OrderBy(SearchOrderTitle, Desc).OrderBy(SearchOrderFollowedCount, Desc),
+			"order%5Byear%5D=asc&order%5Btitle%5D=desc&order%5BfollowedCount%5D=desc",
+		},
+		{
+			"tags by name and id",
+			NewSearch().WithTagResolver(testTags).IncludeTags("Fantasy", romanceTag, "fantasy").IncludedTagsMode("or"),
+			"includedTagsMode=OR&includedTags%5B%5D=" + fantasyTag + "&includedTags%5B%5D=" + romanceTag,
+		},
+		{
+			"enums are canonical",
+			NewSearch().ContentRatings("Safe", "SUGGESTIVE").Status("completed").Limit(20),
+			"contentRating%5B%5D=safe&contentRating%5B%5D=suggestive&limit=20&status%5B%5D=completed",
+		},
+		{
+			"order follows the other parameters",
+			NewSearch().Title("berserk").Year(1989).OrderBy(SearchOrderRelevance, Desc),
+			"title=berserk&year=1989&order%5Brelevance%5D=desc",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := tt.build.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := q.Encode()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("Encode = %s\nwant     %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchBuilderErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		build  *SearchBuilder
+		want   string
+		target error
+	}{
+		{"included and excluded", NewSearch().WithTagResolver(testTags).IncludeTags("Fantasy").ExcludeTags(fantasyTag), "both included and excluded", nil},
+		{"included mode without tags", NewSearch().IncludedTagsMode("AND"), "included tags mode set without included tags", nil},
+		{"excluded mode without tags", NewSearch().ExcludedTagsMode("or"), "excluded tags mode set without excluded tags", nil},
+		{"bad tags mode", NewSearch().IncludeTags(romanceTag).IncludedTagsMode("xor"), `included tags mode "XOR"`, nil},
+		{"unknown tag name", NewSearch().WithTagResolver(testTags).IncludeTags("Isekai"), `unknown tag "Isekai"`, ErrUnknownTag},
+		{"tag name without resolver", NewSearch().ExcludeTags("Romance"), `unknown tag "Romance"`, ErrUnknownTag},
+		{"year out of range", NewSearch().Year(0), "year 0 out of range", nil},
+		{"unknown rating", NewSearch().ContentRatings("explicit"), `unknown content rating "explicit"`, nil},
+		{"order key twice", NewSearch().OrderBy(SearchOrderYear, Asc).OrderBy(SearchOrderYear, Desc), `order key "year" given twice`, nil},
+		{"relevance without title", NewSearch().OrderBy(SearchOrderRelevance, Desc), "relevance ordering requires a title", nil},
+		{"result window", NewSearch().Offset(9950).Limit(100), "exceeds the 10000 result window", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := tt.build.Build()
+			if err == nil {
+				t.Fatalf("built %+v, want an error", q)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+			if tt.target != nil && !errors.Is(err, tt.target) {
+				t.Fatalf("err = %v, want %v", err, tt.target)
+			}
+		})
+	}
+
+	// every problem is reported at once
+	_, err := NewSearch().Year(-5).IncludedTagsMode("AND").ContentRatings("explicit").Build()
+	if err == nil || strings.Count(err.Error(), "\n") != 2 {
+		t.Fatalf("err = %v, want three errors", err)
+	}
+}
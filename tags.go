@@ -0,0 +1,311 @@
+package mangadex
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// Tag groups as returned in TagAttributes.Group.
+const (
+	TagGroupGenre   = "genre"
+	TagGroupTheme   = "theme"
+	TagGroupFormat  = "format"
+	TagGroupContent = "content"
+)
+
+// DefaultTagRefreshInterval is how often TagCatalog.Run reloads tags. The
+// tag list changes a few times a year at most.
+const DefaultTagRefreshInterval = 24 * time.Hour
+
+// tagSnapshot is a GET /manga/tag response. Regenerate it with
+// TagCatalog.Snapshot after MangaDex adds or renames tags.
+//
+//go:embed tags.json
+var tagSnapshot []byte
+
+// defaultTagAliases maps common alternative names to MangaDex tag names.
+var defaultTagAliases = map[string]string{
+	"bl":              "Boys' Love",
+	"yaoi":            "Boys' Love",
+	"shounen ai":      "Boys' Love",
+	"gl":              "Girls' Love",
+	"yuri":            "Girls' Love",
+	"shoujo ai":       "Girls' Love",
+	"scifi":           "Sci-Fi",
+	"science fiction": "Sci-Fi",
+	"sol":             "Slice of Life",
+	"webtoon":         "Long Strip",
+	"manhwa strip":    "Long Strip",
+	"4koma":           "4-Koma",
+	"yonkoma":         "4-Koma",
+	"one shot":        "Oneshot",
+	"gender bender":   "Genderswap",
+	"post apocalypse": "Post-Apocalyptic",
+	"vr":              "Virtual Reality",
+	"magical girl":    "Magical Girls",
+}
+
+// TagInfo is a tag in the catalog.
+type TagInfo struct {
+	ID    openapi_types.UUID
+	Name  string
+	Group string
+
+	// Names holds the localized names by language.
+	Names map[string]string
+}
+
+// TagCatalog resolves tag names to ids. It starts out with an embedded
+// snapshot, so it works offline, and Refresh or Run replace it with the
+// live list from GetMangaTagWithResponse.
+//
+// Names are matched case-insensitively, ignoring spaces, punctuation and
+// diacritics, against every localized name and the catalog's aliases, so
+// "romance", "Sci Fi" and "girls love" all resolve.
+type TagCatalog struct {
+	Client          ClientWithResponsesInterface
+	RefreshInterval time.Duration
+	Localizer       *Localizer
+
+	// OnRefreshError is called when a scheduled refresh fails; the previous
+	// tags are kept.
+	OnRefreshError func(error)
+
+	mu        sync.RWMutex
+	tags      []TagInfo
+	byName    map[string]int
+	aliases   map[string]string
+	updatedAt time.Time
+}
+
+var _ TagResolver = (*TagCatalog)(nil)
+
+// NewTagCatalog creates a catalog loaded from the embedded snapshot.
+func NewTagCatalog(client ClientWithResponsesInterface) *TagCatalog {
+	c := &TagCatalog{
+		Client:          client,
+		RefreshInterval: DefaultTagRefreshInterval,
+		Localizer:       NewLocalizer("en"),
+		aliases:         map[string]string{},
+	}
+	for alias, name := range defaultTagAliases {
+		c.aliases[tagKey(alias)] = name
+	}
+	if err := c.Load(tagSnapshot); err != nil {
+		panic(fmt.Sprintf("mangadex: embedded tag snapshot: %v", err))
+	}
+	return c
+}
+
+// Load replaces the catalog with tags from a GET /manga/tag response body.
+func (c *TagCatalog) Load(data []byte) error {
+	var resp TagResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return err
+	}
+	if resp.Data == nil {
+		return fmt.Errorf("tag response has no data")
+	}
+	c.set(*resp.Data, time.Time{})
+	return nil
+}
+
+// Refresh loads the current tag list from the API.
+func (c *TagCatalog) Refresh(ctx context.Context) error {
+	resp, err := c.Client.GetMangaTagWithResponse(ctx)
+	if err != nil {
+		return err
+	}
+	if resp.JSON200 == nil || resp.JSON200.Data == nil {
+		return newAPIError(resp.HTTPResponse)
+	}
+	c.set(*resp.JSON200.Data, time.Now())
+	return nil
+}
+
+// Run refreshes immediately and then every RefreshInterval until ctx is
+// done.
+func (c *TagCatalog) Run(ctx context.Context) error {
+	interval := c.RefreshInterval
+	if interval <= 0 {
+		interval = DefaultTagRefreshInterval
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		if err := c.Refresh(ctx); err != nil && c.OnRefreshError != nil {
+			c.OnRefreshError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func (c *TagCatalog) set(tags []Tag, updatedAt time.Time) {
+	loc := c.Localizer
+	if loc == nil {
+		loc = NewLocalizer("en")
+	}
+	infos := make([]TagInfo, 0, len(tags))
+	for _, t := range tags {
+		if t.Id == nil || t.Attributes == nil {
+			continue
+		}
+		info := TagInfo{ID: *t.Id, Names: map[string]string{}}
+		if a := t.Attributes; a.Name != nil {
+			info.Name, _ = loc.String(*a.Name)
+			for lang, name := range *a.Name {
+				info.Names[lang] = name
+			}
+		}
+		if t.Attributes.Group != nil {
+			info.Group = string(*t.Attributes.Group)
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+
+	byName := make(map[string]int, len(infos)*2)
+	for i, info := range infos {
+		byName[tagKey(info.ID.String())] = i
+		for _, name := range info.Names {
+			if _, ok := byName[tagKey(name)]; !ok {
+				byName[tagKey(name)] = i
+			}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tags = infos
+	c.byName = byName
+	c.updatedAt = updatedAt
+}
+
+// AddAlias makes alias resolve to the tag called name.
+func (c *TagCatalog) AddAlias(alias, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.aliases == nil {
+		c.aliases = map[string]string{}
+	}
+	c.aliases[tagKey(alias)] = name
+}
+
+// Lookup finds a tag by id, localized name or alias.
+func (c *TagCatalog) Lookup(name string) (TagInfo, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	key := tagKey(name)
+	i, ok := c.byName[key]
+	if !ok {
+		if target, alias := c.aliases[key]; alias {
+			i, ok = c.byName[tagKey(target)]
+		}
+	}
+	if !ok {
+		return TagInfo{}, false
+	}
+	return c.tags[i], true
+}
+
+// TagID implements TagResolver.
+func (c *TagCatalog) TagID(name string) (openapi_types.UUID, bool) {
+	info, ok := c.Lookup(name)
+	return info.ID, ok
+}
+
+// Tags returns all tags sorted by name.
+func (c *TagCatalog) Tags() []TagInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]TagInfo(nil), c.tags...)
+}
+
+// Group returns the tags of a group such as TagGroupGenre.
+func (c *TagCatalog) Group(group string) []TagInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var out []TagInfo
+	for _, t := range c.tags {
+		if strings.EqualFold(t.Group, group) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// UpdatedAt is when tags were last loaded from the API; zero while the
+// embedded snapshot is in use.
+func (c *TagCatalog) UpdatedAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.updatedAt
+}
+
+// Snapshot renders the catalog in the format of the embedded snapshot.
+func (c *TagCatalog) Snapshot() ([]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	data := make([]Tag, 0, len(c.tags))
+	for _, info := range c.tags {
+		id := info.ID
+		names := LocalizedString{}
+		for lang, name := range info.Names {
+			names[lang] = name
+		}
+		group := TagAttributesGroup(info.Group)
+		typ := TagType("tag")
+		data = append(data, Tag{Id: &id, Type: &typ, Attributes: &TagAttributes{Name: &names, Group: &group}})
+	}
+	total := len(data)
+	return json.MarshalIndent(TagResponse{Result: String("ok"), Response: String("collection"), Data: &data, Total: &total}, "", " ")
+}
+
+// tagKey folds a tag name for matching: lower case letters and digits only,
+// with diacritics dropped.
+func tagKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(foldDiacritic(r))
+		}
+	}
+	return b.String()
+}
+
+// foldDiacritic maps common accented Latin letters to their base letter so
+// that e.g. "Shōnen" matches "shonen".
+func foldDiacritic(r rune) rune {
+	switch r {
+	case 'á', 'à', 'â', 'ä', 'ã', 'å', 'ā':
+		return 'a'
+	case 'é', 'è', 'ê', 'ë', 'ē':
+		return 'e'
+	case 'í', 'ì', 'î', 'ï', 'ī':
+		return 'i'
+	case 'ó', 'ò', 'ô', 'ö', 'õ', 'ō':
+		return 'o'
+	case 'ú', 'ù', 'û', 'ü', 'ū':
+		return 'u'
+	case 'ç':
+		return 'c'
+	case 'ñ':
+		return 'n'
+	}
+	return r
+}
@@ -0,0 +1,78 @@
+package mangadex
+
+import "testing"
+
+func TestTagCatalogDefaultAliases(t *testing.T) {
+	c := NewTagCatalog(nil)
+	for alias, name := range defaultTagAliases {
+		info, ok := c.Lookup(alias)
+		if !ok || info.Name != name {
+			t.Errorf("alias %q = %q, %v; want %q in the embedded snapshot", alias, info.Name, ok, name)
+		}
+	}
+}
+
+func TestTagCatalogLookup(t *testing.T) {
+	c := NewTagCatalog(nil)
+	romance, _ := c.Lookup("Romance")
+	c.AddAlias("Romcom", "Romance")
+	c.AddAlias("shinobi", "Ninja")
+	c.AddAlias("missing", "No Such Tag")
+	// an alias does not shadow a real tag name
+	c.AddAlias("Action", "Romance")
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+		ok    bool
+	}{
+		{"exact", "Romance", "Romance", true},
+		{"case", "ROMANCE", "Romance", true},
+		{"punctuation", "Sci Fi", "Sci-Fi", true},
+		{"apostrophe", "girls love", "Girls' Love", true},
+		{"diacritics", "Rōmance", "Romance", true},
+		{"id", romance.ID.String(), "Romance", true},
+		{"default alias", "Yuri", "Girls' Love", true},
+		{"folded alias", "Science-Fiction", "Sci-Fi", true},
+		{"added alias", "rom com", "Romance", true},
+		{"second added alias", "Shinobi", "Ninja", true},
+		{"alias to unknown tag", "missing", "", false},
+		{"name wins over alias", "action", "Action", true},
+		{"unknown", "Cyberpunk", "", false},
+		{"empty", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, ok := c.Lookup(tt.query)
+			if ok != tt.ok || info.Name != tt.want {
+				t.Fatalf("Lookup(%q) = %q, %v; want %q, %v", tt.query, info.Name, ok, tt.want, tt.ok)
+			}
+			if id, ok := c.TagID(tt.query); ok != tt.ok || id != info.ID {
+				t.Fatalf("TagID(%q) = %s, %v", tt.query, id, ok)
+			}
+		})
+	}
+}
+
+func TestTagCatalogLoadKeepsAliases(t *testing.T) {
+	c := NewTagCatalog(nil)
+	c.AddAlias("shinobi", "Ninja")
+	err := c.Load([]byte(`{"result":"ok","data":[
+		{"id":"489dd859-9b61-4c37-af75-5b18e88daafc","type":"tag","attributes":{"name":{"en":"Ninja","ja":"忍者"},"group":"theme"}}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, query := range []string{"shinobi", "忍者", "ninja"} {
+		if info, ok := c.Lookup(query); !ok || info.Name != "Ninja" || info.Group != TagGroupTheme {
+			t.Errorf("Lookup(%q) = %+v, %v", query, info, ok)
+		}
+	}
+	if _, ok := c.Lookup("Romance"); ok {
+		t.Error("tags from the previous load kept")
+	}
+	if got := len(c.Group(TagGroupTheme)); got != 1 {
+		t.Errorf("%d theme tags, want 1", got)
+	}
+}
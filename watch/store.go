@@ -0,0 +1,121 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// Cursor is the checkpoint of a subscription.
+type Cursor struct {
+	// Since is the timestamp of the newest chapter seen so far.
+	Since time.Time `json:"since"`
+
+	// Seen holds the ids of chapters at exactly Since, which the inclusive
+	// *Since filters return again on the next poll.
+	Seen []string `json:"seen,omitempty"`
+}
+
+// CursorStore persists cursors per subscription.
+type CursorStore interface {
+	// LoadCursor returns nil, nil when the subscription has no cursor yet.
+	LoadCursor(ctx context.Context, subscriptionID string) (*Cursor, error)
+	SaveCursor(ctx context.Context, subscriptionID string, cursor Cursor) error
+}
+
+var _ CursorStore = (*InMemoryCursorStore)(nil)
+
+// InMemoryCursorStore keeps cursors for the lifetime of the process.
+type InMemoryCursorStore struct {
+	mu      sync.RWMutex
+	cursors map[string]Cursor
+}
+
+func NewInMemoryCursorStore() *InMemoryCursorStore {
+	return &InMemoryCursorStore{cursors: make(map[string]Cursor)}
+}
+
+func (m *InMemoryCursorStore) LoadCursor(_ context.Context, subscriptionID string) (*Cursor, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	c, ok := m.cursors[subscriptionID]
+	if !ok {
+		return nil, nil
+	}
+	c.Seen = append([]string(nil), c.Seen...)
+	return &c, nil
+}
+
+func (m *InMemoryCursorStore) SaveCursor(_ context.Context, subscriptionID string, cursor Cursor) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cursor.Seen = append([]string(nil), cursor.Seen...)
+	m.cursors[subscriptionID] = cursor
+	return nil
+}
+
+var _ CursorStore = (*FileCursorStore)(nil)
+
+// FileCursorStore keeps all cursors in a single JSON file, rewriting it on
+// every save.
+type FileCursorStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+func NewFileCursorStore(path string) *FileCursorStore {
+	return &FileCursorStore{Path: path}
+}
+
+func (f *FileCursorStore) LoadCursor(_ context.Context, subscriptionID string) (*Cursor, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	all, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+	c, ok := all[subscriptionID]
+	if !ok {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+func (f *FileCursorStore) SaveCursor(_ context.Context, subscriptionID string, cursor Cursor) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	all, err := f.read()
+	if err != nil {
+		return err
+	}
+	all[subscriptionID] = cursor
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := f.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.Path)
+}
+
+func (f *FileCursorStore) read() (map[string]Cursor, error) {
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]Cursor{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	all := map[string]Cursor{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
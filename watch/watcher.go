@@ -0,0 +1,440 @@
+// Package watch polls MangaDex feeds for newly uploaded chapters.
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Seann-Moser/mangadex"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// sinceLayout is the only timestamp format the *Since filters accept.
+const sinceLayout = "2006-01-02T15:04:05"
+
+const (
+	DefaultInterval = 5 * time.Minute
+	pageSize        = 100
+)
+
+// Checkpoint selects which chapter timestamp a subscription follows.
+type Checkpoint int
+
+const (
+	// CreatedAt reports chapters as soon as they are uploaded.
+	CreatedAt Checkpoint = iota
+	// PublishAt reports chapters once their publish time has been reached,
+	// which for delayed group releases can be well after upload.
+	PublishAt
+)
+
+// Subscription describes a set of chapters to watch.
+type Subscription struct {
+	// ID keys the subscription's cursor in the CursorStore.
+	ID string
+
+	// Follows polls the authenticated user's follows feed. RequestEditors
+	// must then authenticate the request, e.g. with OAuthClient.ApplyAuth.
+	Follows bool
+
+	// MangaIDs are polled through their manga feeds when Follows is false.
+	MangaIDs []openapi_types.UUID
+
+	Languages  []string
+	Checkpoint Checkpoint
+
+	// StartFrom is used when the subscription has no cursor yet. Defaults
+	// to the time of the first poll, so existing chapters are not reported.
+	StartFrom time.Time
+
+	RequestEditors []mangadex.RequestEditorFn
+}
+
+// NewChapter is emitted for every chapter not seen before.
+type NewChapter struct {
+	SubscriptionID string
+	MangaID        openapi_types.UUID
+	Chapter        mangadex.Chapter
+	CreatedAt      time.Time
+	PublishAt      time.Time
+}
+
+// Watcher polls subscriptions and emits NewChapter events.
+type Watcher struct {
+	Client mangadex.ClientWithResponsesInterface
+	Store  CursorStore
+
+	// Interval between polls in Run. Defaults to DefaultInterval.
+	Interval time.Duration
+
+	// OnChapter is called for every new chapter, oldest first. Set it
+	// before Run starts; Events chains onto it safely while running.
+	OnChapter func(context.Context, NewChapter)
+
+	// OnError is called when polling a subscription fails; Run keeps going.
+	OnError func(subscriptionID string, err error)
+
+	now func() time.Time
+
+	mu   sync.Mutex
+	subs map[string]Subscription
+}
+
+// NewWatcher creates a Watcher. A nil store keeps cursors in memory.
+func NewWatcher(client mangadex.ClientWithResponsesInterface, store CursorStore) *Watcher {
+	if store == nil {
+		store = NewInMemoryCursorStore()
+	}
+	return &Watcher{
+		Client:   client,
+		Store:    store,
+		Interval: DefaultInterval,
+		subs:     map[string]Subscription{},
+	}
+}
+
+// Subscribe adds or replaces a subscription.
+func (w *Watcher) Subscribe(sub Subscription) error {
+	if sub.ID == "" {
+		return errors.New("subscription id is required")
+	}
+	if !sub.Follows && len(sub.MangaIDs) == 0 {
+		return fmt.Errorf("subscription %s watches nothing", sub.ID)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.subs == nil {
+		w.subs = map[string]Subscription{}
+	}
+	w.subs[sub.ID] = sub
+	return nil
+}
+
+// Unsubscribe removes a subscription. Its cursor is kept in the store.
+func (w *Watcher) Unsubscribe(id string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.subs, id)
+}
+
+// Run polls every Interval until ctx is done.
+func (w *Watcher) Run(ctx context.Context) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		w.PollAll(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Events runs the watcher in the background and delivers events on the
+// returned channel, which is closed once ctx is done.
+func (w *Watcher) Events(ctx context.Context, buffer int) <-chan NewChapter {
+	out := make(chan NewChapter, buffer)
+	// a later Events call may still run this hook after out is closed
+	var (
+		outMu  sync.RWMutex
+		closed bool
+	)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	prev := w.OnChapter
+	w.OnChapter = func(runCtx context.Context, c NewChapter) {
+		if prev != nil {
+			prev(runCtx, c)
+		}
+		outMu.RLock()
+		defer outMu.RUnlock()
+		if closed {
+			return
+		}
+		select {
+		case out <- c:
+		case <-runCtx.Done():
+		case <-ctx.Done():
+		}
+	}
+	go func() {
+		_ = w.Run(ctx)
+		outMu.Lock()
+		defer outMu.Unlock()
+		closed = true
+		close(out)
+	}()
+	return out
+}
+
+// PollAll polls every subscription once.
+func (w *Watcher) PollAll(ctx context.Context) {
+	w.mu.Lock()
+	subs := make([]Subscription, 0, len(w.subs))
+	for _, s := range w.subs {
+		subs = append(subs, s)
+	}
+	onChapter := w.OnChapter
+	w.mu.Unlock()
+
+	for _, s := range subs {
+		if ctx.Err() != nil {
+			return
+		}
+		events, err := w.Poll(ctx, s)
+		if err != nil {
+			if w.OnError != nil {
+				w.OnError(s.ID, err)
+			}
+			continue
+		}
+		if onChapter != nil {
+			for _, e := range events {
+				onChapter(ctx, e)
+			}
+		}
+	}
+}
+
+// Poll fetches chapters newer than the subscription's cursor, advances the
+// cursor and returns the new chapters oldest first.
+func (w *Watcher) Poll(ctx context.Context, sub Subscription) ([]NewChapter, error) {
+	cur, err := w.Store.LoadCursor(ctx, sub.ID)
+	if err != nil {
+		return nil, err
+	}
+	if cur == nil {
+		start := sub.StartFrom
+		if start.IsZero() {
+			start = w.clock()
+		}
+		cur = &Cursor{Since: start.UTC().Truncate(time.Second)}
+	}
+
+	var chapters []mangadex.Chapter
+	if sub.Follows {
+		chapters, err = w.followsFeed(ctx, sub, cur.Since)
+	} else {
+		for _, id := range sub.MangaIDs {
+			cs, ferr := w.mangaFeed(ctx, sub, id, cur.Since)
+			if ferr != nil {
+				err = ferr
+				break
+			}
+			chapters = append(chapters, cs...)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(cur.Seen))
+	for _, id := range cur.Seen {
+		seen[id] = true
+	}
+
+	now := w.clock()
+	var events []NewChapter
+	for _, c := range chapters {
+		if c.Id == nil || seen[c.Id.String()] {
+			continue
+		}
+		e := newChapterEvent(sub.ID, c)
+		at := checkpoint(sub, e)
+		// delayed releases are reported once their publish time is reached
+		if at.Before(cur.Since) || (sub.Checkpoint == PublishAt && at.After(now)) {
+			continue
+		}
+		seen[c.Id.String()] = true
+		events = append(events, e)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return checkpoint(sub, events[i]).Before(checkpoint(sub, events[j]))
+	})
+
+	next := advance(sub, *cur, events, now)
+	if err := w.Store.SaveCursor(ctx, sub.ID, next); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// advance moves the cursor to the newest event, remembering every chapter
+// at that exact second. In PublishAt mode the cursor never moves past now,
+// so chapters whose publish time has not been reached yet are not skipped;
+// events beyond the clamped cursor are remembered too. CreatedAt times come
+// from the server alone and are followed even when ahead of the local clock.
+func advance(sub Subscription, cur Cursor, events []NewChapter, now time.Time) Cursor {
+	if len(events) == 0 {
+		return cur
+	}
+	latest := checkpoint(sub, events[len(events)-1]).Truncate(time.Second)
+	if limit := now.UTC().Truncate(time.Second); sub.Checkpoint == PublishAt && latest.After(limit) {
+		latest = limit
+	}
+	next := Cursor{Since: latest}
+	if latest.Equal(cur.Since) {
+		next.Seen = append(next.Seen, cur.Seen...)
+	}
+	for _, e := range events {
+		if !checkpoint(sub, e).Truncate(time.Second).Before(latest) {
+			next.Seen = append(next.Seen, e.Chapter.Id.String())
+		}
+	}
+	return next
+}
+
+func checkpoint(sub Subscription, e NewChapter) time.Time {
+	if sub.Checkpoint == PublishAt {
+		return e.PublishAt
+	}
+	return e.CreatedAt
+}
+
+func newChapterEvent(subID string, c mangadex.Chapter) NewChapter {
+	e := NewChapter{SubscriptionID: subID, Chapter: c}
+	if a := c.Attributes; a != nil {
+		if a.CreatedAt != nil {
+			e.CreatedAt, _ = time.Parse(time.RFC3339, *a.CreatedAt)
+		}
+		if a.PublishAt != nil {
+			e.PublishAt, _ = time.Parse(time.RFC3339, *a.PublishAt)
+		}
+	}
+	if m, err := c.Manga(); err == nil && m != nil {
+		e.MangaID = m.Id
+	}
+	return e
+}
+
+func (w *Watcher) followsFeed(ctx context.Context, sub Subscription, since time.Time) ([]mangadex.Chapter, error) {
+	params := &mangadex.GetUserFollowsMangaFeedParams{Limit: mangadex.Int(pageSize), Includes: feedIncludes()}
+	if len(sub.Languages) > 0 {
+		params.TranslatedLanguage = &sub.Languages
+	}
+	s := since.UTC().Format(sinceLayout)
+	params.Order = &struct {
+		Chapter    *mangadex.GetUserFollowsMangaFeedParamsOrderChapter    `json:"chapter,omitempty" bson:"chapter"`
+		CreatedAt  *mangadex.GetUserFollowsMangaFeedParamsOrderCreatedAt  `json:"createdAt,omitempty" bson:"createdAt"`
+		PublishAt  *mangadex.GetUserFollowsMangaFeedParamsOrderPublishAt  `json:"publishAt,omitempty" bson:"publishAt"`
+		ReadableAt *mangadex.GetUserFollowsMangaFeedParamsOrderReadableAt `json:"readableAt,omitempty" bson:"readableAt"`
+		UpdatedAt  *mangadex.GetUserFollowsMangaFeedParamsOrderUpdatedAt  `json:"updatedAt,omitempty" bson:"updatedAt"`
+		Volume     *mangadex.GetUserFollowsMangaFeedParamsOrderVolume     `json:"volume,omitempty" bson:"volume"`
+	}{}
+	if sub.Checkpoint == PublishAt {
+		asc := mangadex.GetUserFollowsMangaFeedParamsOrderPublishAt("asc")
+		future := mangadex.GetUserFollowsMangaFeedParamsIncludeFuturePublishAtN0
+		params.IncludeFuturePublishAt = &future
+		params.PublishAtSince = &s
+		params.Order.PublishAt = &asc
+	} else {
+		asc := mangadex.GetUserFollowsMangaFeedParamsOrderCreatedAt("asc")
+		params.CreatedAtSince = &s
+		params.Order.CreatedAt = &asc
+	}
+
+	var out []mangadex.Chapter
+	for offset := 0; ; {
+		params.Offset = &offset
+		resp, err := w.Client.GetUserFollowsMangaFeedWithResponse(ctx, params, sub.RequestEditors...)
+		if err != nil {
+			return nil, err
+		}
+		if resp.JSON200 == nil {
+			return nil, fmt.Errorf("follows feed: %s", resp.Status())
+		}
+		page, done := collect(resp.JSON200, &offset)
+		out = append(out, page...)
+		if done {
+			return out, nil
+		}
+	}
+}
+
+func (w *Watcher) mangaFeed(ctx context.Context, sub Subscription, mangaID openapi_types.UUID, since time.Time) ([]mangadex.Chapter, error) {
+	params := &mangadex.GetMangaIdFeedParams{Limit: mangadex.Int(pageSize), Includes: feedIncludes()}
+	if len(sub.Languages) > 0 {
+		params.TranslatedLanguage = &sub.Languages
+	}
+	s := since.UTC().Format(sinceLayout)
+	params.Order = &struct {
+		Chapter    *mangadex.GetMangaIdFeedParamsOrderChapter    `json:"chapter,omitempty" bson:"chapter"`
+		CreatedAt  *mangadex.GetMangaIdFeedParamsOrderCreatedAt  `json:"createdAt,omitempty" bson:"createdAt"`
+		PublishAt  *mangadex.GetMangaIdFeedParamsOrderPublishAt  `json:"publishAt,omitempty" bson:"publishAt"`
+		ReadableAt *mangadex.GetMangaIdFeedParamsOrderReadableAt `json:"readableAt,omitempty" bson:"readableAt"`
+		UpdatedAt  *mangadex.GetMangaIdFeedParamsOrderUpdatedAt  `json:"updatedAt,omitempty" bson:"updatedAt"`
+		Volume     *mangadex.GetMangaIdFeedParamsOrderVolume     `json:"volume,omitempty" bson:"volume"`
+	}{}
+	if sub.Checkpoint == PublishAt {
+		asc := mangadex.GetMangaIdFeedParamsOrderPublishAt("asc")
+		future := mangadex.GetMangaIdFeedParamsIncludeFuturePublishAtN0
+		params.IncludeFuturePublishAt = &future
+		params.PublishAtSince = &s
+		params.Order.PublishAt = &asc
+	} else {
+		asc := mangadex.GetMangaIdFeedParamsOrderCreatedAt("asc")
+		params.CreatedAtSince = &s
+		params.Order.CreatedAt = &asc
+	}
+
+	var out []mangadex.Chapter
+	for offset := 0; ; {
+		params.Offset = &offset
+		resp, err := w.Client.GetMangaIdFeedWithResponse(ctx, mangaID, params, sub.RequestEditors...)
+		if err != nil {
+			return nil, err
+		}
+		if resp.JSON200 == nil {
+			return nil, fmt.Errorf("manga %s feed: %s", mangaID, resp.Status())
+		}
+		page, done := collect(resp.JSON200, &offset)
+		for i := range page {
+			// chapters always carry their manga relationship, but key events
+			// by the manga we asked for should it ever be missing
+			if m, _ := page[i].Manga(); m == nil {
+				t := mangadex.RelationshipManga
+				rels := []mangadex.Relationship{{Id: &mangaID, Type: &t}}
+				if page[i].Relationships != nil {
+					rels = append(*page[i].Relationships, rels...)
+				}
+				page[i].Relationships = &rels
+			}
+		}
+		out = append(out, page...)
+		if done {
+			return out, nil
+		}
+	}
+}
+
+// feedIncludes expands the relationships event consumers filter on.
+func feedIncludes() *mangadex.ReferenceExpansionChapter {
+	return &mangadex.ReferenceExpansionChapter{mangadex.RelationshipManga, mangadex.RelationshipScanlationGroup}
+}
+
+// collect returns the chapters of a list page, advancing offset, and reports
+// whether the last page has been reached.
+func collect(list *mangadex.ChapterList, offset *int) ([]mangadex.Chapter, bool) {
+	if list.Data == nil || len(*list.Data) == 0 {
+		return nil, true
+	}
+	*offset += len(*list.Data)
+	done := list.Total == nil || *offset >= *list.Total
+	return *list.Data, done
+}
+
+func (w *Watcher) clock() time.Time {
+	if w.now != nil {
+		return w.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,321 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Seann-Moser/mangadex"
+	"github.com/google/uuid"
+)
+
+var t0 = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func event(id string, at time.Time) NewChapter {
+	uid := uuid.MustParse(id)
+	return NewChapter{Chapter: mangadex.Chapter{Id: &uid}, CreatedAt: at, PublishAt: at}
+}
+
+const (
+	idA = "00000000-0000-0000-0000-00000000000a"
+	idB = "00000000-0000-0000-0000-00000000000b"
+	idC = "00000000-0000-0000-0000-00000000000c"
+	idD = "00000000-0000-0000-0000-00000000000d"
+)
+
+func TestAdvance(t *testing.T) {
+	later := t0.Add(time.Minute)
+	tests := []struct {
+		name       string
+		checkpoint Checkpoint
+		cur        Cursor
+		events     []NewChapter
+		now        time.Time
+		want       Cursor
+	}{
+		{
+			name: "no events",
+			cur:  Cursor{Since: t0, Seen: []string{idA}},
+			now:  later,
+			want: Cursor{Since: t0, Seen: []string{idA}},
+		},
+		{
+			name:   "moves to newest",
+			cur:    Cursor{Since: t0, Seen: []string{idA}},
+			events: []NewChapter{event(idB, t0.Add(time.Second)), event(idC, later)},
+			now:    later.Add(time.Hour),
+			want:   Cursor{Since: later, Seen: []string{idC}},
+		},
+		{
+			name:   "same second keeps seen",
+			cur:    Cursor{Since: t0, Seen: []string{idA}},
+			events: []NewChapter{event(idB, t0.Add(300*time.Millisecond))},
+			now:    later,
+			want:   Cursor{Since: t0, Seen: []string{idA, idB}},
+		},
+		{
+			name:   "ties at newest second",
+			cur:    Cursor{Since: t0},
+			events: []NewChapter{event(idA, t0.Add(time.Second)), event(idB, later), event(idC, later.Add(time.Millisecond))},
+			now:    later.Add(time.Hour),
+			want:   Cursor{Since: later, Seen: []string{idB, idC}},
+		},
+		{
+			name:       "publish clamped to now",
+			checkpoint: PublishAt,
+			cur:        Cursor{Since: t0},
+			events:     []NewChapter{event(idA, later.Add(time.Hour))},
+			now:        later,
+			want:       Cursor{Since: later, Seen: []string{idA}},
+		},
+		{
+			name:   "created ahead of the clock",
+			cur:    Cursor{Since: t0},
+			events: []NewChapter{event(idA, later), event(idB, later.Add(time.Hour))},
+			now:    later,
+			want:   Cursor{Since: later.Add(time.Hour), Seen: []string{idB}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := advance(Subscription{Checkpoint: tt.checkpoint}, tt.cur, tt.events, tt.now)
+			if !got.Since.Equal(tt.want.Since) || !slices.Equal(got.Seen, tt.want.Seen) {
+				t.Fatalf("advance = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeFeed serves /manga/{id}/feed from a fixed set of chapters, applying
+// the *Since filters but leaving future chapters to the watcher.
+type fakeFeed struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	chapters []mangadex.Chapter
+	queries  []map[string][]string
+}
+
+func newFakeFeed(t *testing.T) *fakeFeed {
+	t.Helper()
+	f := &fakeFeed{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /manga/{id}/feed", f.feed)
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeFeed) Add(id string, createdAt, publishAt time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	uid := uuid.MustParse(id)
+	f.chapters = append(f.chapters, mangadex.Chapter{
+		Id: &uid,
+		Attributes: &mangadex.ChapterAttributes{
+			CreatedAt: mangadex.String(createdAt.Format(time.RFC3339)),
+			PublishAt: mangadex.String(publishAt.Format(time.RFC3339)),
+		},
+	})
+}
+
+func (f *fakeFeed) feed(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f.mu.Lock()
+	f.queries = append(f.queries, q)
+	field, since := "createdAt", q.Get("createdAtSince")
+	if s := q.Get("publishAtSince"); s != "" {
+		field, since = "publishAt", s
+	}
+	var out []mangadex.Chapter
+	for _, c := range f.chapters {
+		if at := chapterTime(c, field); since == "" || at >= since {
+			out = append(out, c)
+		}
+	}
+	f.mu.Unlock()
+	sort.SliceStable(out, func(i, j int) bool { return chapterTime(out[i], field) < chapterTime(out[j], field) })
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(mangadex.ChapterList{Data: &out, Total: mangadex.Int(len(out))})
+}
+
+// chapterTime formats the field in the *Since layout, which compares as text.
+func chapterTime(c mangadex.Chapter, field string) string {
+	v := c.Attributes.CreatedAt
+	if field == "publishAt" {
+		v = c.Attributes.PublishAt
+	}
+	at, _ := time.Parse(time.RFC3339, *v)
+	return at.UTC().Format(sinceLayout)
+}
+
+func (f *fakeFeed) lastQuery() map[string][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.queries[len(f.queries)-1]
+}
+
+func (f *fakeFeed) watcher(t *testing.T, now *time.Time) *Watcher {
+	t.Helper()
+	client, err := mangadex.NewClientWithResponses(f.URL, mangadex.WithHTTPClient(f.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWatcher(client, nil)
+	w.now = func() time.Time { return *now }
+	return w
+}
+
+func ids(events []NewChapter) []string {
+	out := make([]string, len(events))
+	for i, e := range events {
+		out[i] = e.Chapter.Id.String()
+	}
+	return out
+}
+
+func TestPollAdvancesCursor(t *testing.T) {
+	f := newFakeFeed(t)
+	now := t0.Add(time.Hour)
+	w := f.watcher(t, &now)
+	sub := Subscription{ID: "s", MangaIDs: []uuid.UUID{uuid.New()}, StartFrom: t0}
+	ctx := context.Background()
+
+	f.Add(idA, t0.Add(-time.Minute), t0)
+	f.Add(idB, t0.Add(time.Minute), t0.Add(time.Minute))
+	f.Add(idC, t0.Add(2*time.Minute), t0.Add(2*time.Minute))
+
+	steps := []struct {
+		add  string
+		want []string
+	}{
+		{"", []string{idB, idC}},
+		// the inclusive filter returns idC again
+		{"", nil},
+		{idD, []string{idD}},
+		{"", nil},
+	}
+	for i, step := range steps {
+		if step.add != "" {
+			// uploaded within the cursor's second, after the last poll
+			f.Add(step.add, t0.Add(2*time.Minute+500*time.Millisecond), t0)
+		}
+		events, err := w.Poll(ctx, sub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(events); !slices.Equal(got, step.want) {
+			t.Fatalf("poll %d = %v, want %v", i, got, step.want)
+		}
+	}
+	cur, _ := w.Store.LoadCursor(ctx, "s")
+	if want := t0.Add(2 * time.Minute); !cur.Since.Equal(want) || len(cur.Seen) != 2 {
+		t.Fatalf("cursor = %+v", cur)
+	}
+}
+
+func TestPollPublishAtWaitsForRelease(t *testing.T) {
+	f := newFakeFeed(t)
+	now := t0.Add(time.Hour)
+	w := f.watcher(t, &now)
+	sub := Subscription{ID: "s", MangaIDs: []uuid.UUID{uuid.New()}, Checkpoint: PublishAt, StartFrom: t0}
+	ctx := context.Background()
+
+	// idB was uploaded first but is held back by its group for a day
+	f.Add(idB, t0.Add(time.Minute), now.Add(24*time.Hour))
+	f.Add(idA, t0.Add(2*time.Minute), t0.Add(2*time.Minute))
+
+	events, err := w.Poll(ctx, sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(events); !slices.Equal(got, []string{idA}) {
+		t.Fatalf("events = %v, want only the released chapter", got)
+	}
+	if got := f.lastQuery()["includeFuturePublishAt"]; !slices.Equal(got, []string{"0"}) {
+		t.Fatalf("includeFuturePublishAt = %v", got)
+	}
+
+	now = now.Add(25 * time.Hour)
+	events, err = w.Poll(ctx, sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(events); !slices.Equal(got, []string{idB}) {
+		t.Fatalf("events after release = %v", got)
+	}
+}
+
+func TestEventsChainsOnChapter(t *testing.T) {
+	f := newFakeFeed(t)
+	now := t0.Add(time.Hour)
+	w := f.watcher(t, &now)
+	w.Interval = time.Millisecond
+	if err := w.Subscribe(Subscription{ID: "s", MangaIDs: []uuid.UUID{uuid.New()}, StartFrom: t0}); err != nil {
+		t.Fatal(err)
+	}
+	f.Add(idA, t0.Add(time.Minute), t0.Add(time.Minute))
+
+	var (
+		mu    sync.Mutex
+		calls int
+	)
+	w.OnChapter = func(context.Context, NewChapter) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	first := w.Events(ctx, 1)
+	// chaining a second consumer while the first one runs must not race;
+	// the first consumer gets the event whichever run polls it
+	second := w.Events(ctx, 1)
+
+	select {
+	case e := <-first:
+		if e.Chapter.Id.String() != idA {
+			t.Fatalf("event = %v", e.Chapter.Id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event")
+	}
+	cancel()
+	for range first {
+	}
+	for range second {
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if calls == 0 {
+		t.Fatal("previous OnChapter not called")
+	}
+}
+
+func TestPollCreatedAheadOfClock(t *testing.T) {
+	f := newFakeFeed(t)
+	now := t0
+	w := f.watcher(t, &now)
+	sub := Subscription{ID: "s", MangaIDs: []uuid.UUID{uuid.New()}, StartFrom: t0}
+	ctx := context.Background()
+
+	// the server clock runs ten minutes ahead of ours
+	f.Add(idA, t0.Add(10*time.Minute), t0.Add(10*time.Minute))
+	for i, want := range [][]string{{idA}, nil, nil} {
+		events, err := w.Poll(ctx, sub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(events); !slices.Equal(got, want) {
+			t.Fatalf("poll %d = %v, want %v", i, got, want)
+		}
+		now = now.Add(time.Minute)
+	}
+}
@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DeadLetter is a delivery that exhausted its retries or was rejected.
+type DeadLetter struct {
+	EndpointID string    `json:"endpointId"`
+	URL        string    `json:"url"`
+	EventID    string    `json:"eventId"`
+	EventType  string    `json:"eventType"`
+	Payload    []byte    `json:"payload"`
+	Attempts   int       `json:"attempts"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	LastError  string    `json:"lastError"`
+	FailedAt   time.Time `json:"failedAt"`
+}
+
+// DeadLetterStore keeps failed deliveries for inspection or redelivery.
+type DeadLetterStore interface {
+	SaveDeadLetter(ctx context.Context, dl DeadLetter) error
+	ListDeadLetters(ctx context.Context) ([]DeadLetter, error)
+	DeleteDeadLetter(ctx context.Context, endpointID, eventID string) error
+}
+
+var _ DeadLetterStore = (*InMemoryDeadLetterStore)(nil)
+
+// InMemoryDeadLetterStore keeps dead letters for the lifetime of the process.
+type InMemoryDeadLetterStore struct {
+	mu      sync.Mutex
+	letters []DeadLetter
+}
+
+func NewInMemoryDeadLetterStore() *InMemoryDeadLetterStore {
+	return &InMemoryDeadLetterStore{}
+}
+
+func (m *InMemoryDeadLetterStore) SaveDeadLetter(_ context.Context, dl DeadLetter) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.letters = append(m.letters, dl)
+	return nil
+}
+
+func (m *InMemoryDeadLetterStore) ListDeadLetters(_ context.Context) ([]DeadLetter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]DeadLetter(nil), m.letters...), nil
+}
+
+func (m *InMemoryDeadLetterStore) DeleteDeadLetter(_ context.Context, endpointID, eventID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, dl := range m.letters {
+		if dl.EndpointID == endpointID && dl.EventID == eventID {
+			m.letters = append(m.letters[:i], m.letters[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,468 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Seann-Moser/mangadex/watch"
+)
+
+// Request headers set on every delivery.
+const (
+	HeaderSignature = "X-Mangadex-Signature"
+	HeaderEvent     = "X-Mangadex-Event"
+	HeaderDelivery  = "X-Mangadex-Delivery"
+)
+
+const (
+	DefaultMaxAttempts    = 5
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = time.Minute
+	DefaultTimeout        = 10 * time.Second
+	DefaultQueueSize      = 256
+)
+
+// ErrQueueFull is returned by Enqueue when an endpoint has too many
+// deliveries waiting; the event is dead-lettered for that endpoint.
+var ErrQueueFull = errors.New("webhook queue full")
+
+// Endpoint is a registered webhook receiver.
+type Endpoint struct {
+	ID  string
+	URL string
+
+	// Secret signs payloads; deliveries are unsigned when empty.
+	Secret string
+
+	Filter Filter
+}
+
+// Dispatcher POSTs events to every endpoint whose filter matches, retrying
+// transient failures with exponential backoff. Enqueue hands events to a
+// queue per endpoint, so a slow endpoint delays neither the caller nor the
+// other endpoints; Dispatch delivers and waits.
+type Dispatcher struct {
+	HTTPClient *http.Client
+
+	// MaxAttempts per endpoint and event, including the first.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// Timeout bounds each individual request.
+	Timeout time.Duration
+
+	// DeadLetters receives deliveries that could not be made. Optional.
+	DeadLetters DeadLetterStore
+
+	// OnError is called for every delivery that ends up dead-lettered.
+	OnError func(Endpoint, Event, error)
+
+	// QueueSize is how many deliveries may wait per endpoint. Defaults to
+	// DefaultQueueSize.
+	QueueSize int
+
+	// sleep replaces the backoff timer in tests.
+	sleep func(context.Context, time.Duration) error
+
+	mu        sync.RWMutex
+	endpoints map[string]Endpoint
+	queues    map[string]chan delivery
+	closed    bool
+	workers   sync.WaitGroup
+}
+
+// delivery is an event waiting in an endpoint's queue.
+type delivery struct {
+	ctx     context.Context
+	ep      Endpoint
+	e       Event
+	payload []byte
+}
+
+func NewDispatcher(deadLetters DeadLetterStore) *Dispatcher {
+	return &Dispatcher{
+		HTTPClient:     http.DefaultClient,
+		MaxAttempts:    DefaultMaxAttempts,
+		InitialBackoff: DefaultInitialBackoff,
+		MaxBackoff:     DefaultMaxBackoff,
+		Timeout:        DefaultTimeout,
+		DeadLetters:    deadLetters,
+		endpoints:      map[string]Endpoint{},
+	}
+}
+
+// Register adds or replaces an endpoint.
+func (d *Dispatcher) Register(ep Endpoint) error {
+	if ep.ID == "" || ep.URL == "" {
+		return errors.New("endpoint id and url are required")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.endpoints == nil {
+		d.endpoints = map[string]Endpoint{}
+	}
+	d.endpoints[ep.ID] = ep
+	return nil
+}
+
+// Unregister removes an endpoint. Deliveries already queued for it are
+// still made.
+func (d *Dispatcher) Unregister(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.endpoints, id)
+	if q, ok := d.queues[id]; ok {
+		close(q)
+		delete(d.queues, id)
+	}
+}
+
+func (d *Dispatcher) Endpoints() []Endpoint {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	out := make([]Endpoint, 0, len(d.endpoints))
+	for _, ep := range d.endpoints {
+		out = append(out, ep)
+	}
+	return out
+}
+
+// OnChapter adapts the dispatcher to watch.Watcher.OnChapter. It queues
+// the event and returns, so polling never waits on a receiver; deliveries
+// are cancelled with ctx.
+func (d *Dispatcher) OnChapter(ctx context.Context, c watch.NewChapter) {
+	_ = d.Enqueue(ctx, ChapterEvent(c))
+}
+
+// Run queues chapters from a watcher's Events channel until it closes.
+func (d *Dispatcher) Run(ctx context.Context, events <-chan watch.NewChapter) {
+	for c := range events {
+		d.OnChapter(ctx, c)
+	}
+}
+
+// Enqueue queues e for every matching endpoint and returns without waiting
+// for delivery. Deliveries run with ctx. Endpoints whose queue is full get
+// the event dead-lettered, and the returned error joins them.
+func (d *Dispatcher) Enqueue(ctx context.Context, e Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return errors.New("dispatcher closed")
+	}
+	var full []Endpoint
+	for _, ep := range d.endpoints {
+		if !ep.Filter.Match(e) {
+			continue
+		}
+		select {
+		case d.queue(ep.ID) <- delivery{ctx: ctx, ep: ep, e: e, payload: payload}:
+		default:
+			full = append(full, ep)
+		}
+	}
+	d.mu.Unlock()
+	if len(full) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, ep := range full {
+		errs = append(errs, fmt.Errorf("endpoint %s: %w", ep.ID, d.deadLetter(ctx, ep, e, payload, 0, 0, ErrQueueFull)))
+	}
+	return errors.Join(errs...)
+}
+
+// queue returns the endpoint's queue, starting its worker on first use; the
+// caller holds d.mu.
+func (d *Dispatcher) queue(id string) chan delivery {
+	if q, ok := d.queues[id]; ok {
+		return q
+	}
+	size := d.QueueSize
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+	q := make(chan delivery, size)
+	if d.queues == nil {
+		d.queues = map[string]chan delivery{}
+	}
+	d.queues[id] = q
+	d.workers.Add(1)
+	go func() {
+		defer d.workers.Done()
+		for dl := range q {
+			_ = d.deliver(dl.ctx, dl.ep, dl.e, dl.payload)
+		}
+	}()
+	return q
+}
+
+// Close stops accepting events and waits for queued deliveries to finish.
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		for id, q := range d.queues {
+			close(q)
+			delete(d.queues, id)
+		}
+	}
+	d.mu.Unlock()
+	d.workers.Wait()
+}
+
+// Dispatch delivers e to all matching endpoints concurrently and waits for
+// them. The returned error joins every delivery that was dead-lettered.
+func (d *Dispatcher) Dispatch(ctx context.Context, e Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, ep := range d.Endpoints() {
+		if !ep.Filter.Match(e) {
+			continue
+		}
+		wg.Add(1)
+		go func(ep Endpoint) {
+			defer wg.Done()
+			if err := d.deliver(ctx, ep, e, payload); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("endpoint %s: %w", ep.ID, err))
+				mu.Unlock()
+			}
+		}(ep)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Redeliver retries a dead letter once against its endpoint's current
+// configuration and removes it from the store on success.
+func (d *Dispatcher) Redeliver(ctx context.Context, dl DeadLetter) error {
+	d.mu.RLock()
+	ep, ok := d.endpoints[dl.EndpointID]
+	d.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("endpoint %s not registered", dl.EndpointID)
+	}
+	if _, err := d.post(ctx, ep, dl.EventType, dl.EventID, dl.Payload); err != nil {
+		return err
+	}
+	if d.DeadLetters != nil {
+		return d.DeadLetters.DeleteDeadLetter(ctx, dl.EndpointID, dl.EventID)
+	}
+	return nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, ep Endpoint, e Event, payload []byte) error {
+	attempts := d.MaxAttempts
+	if attempts <= 0 {
+		attempts = DefaultMaxAttempts
+	}
+	var (
+		err    error
+		status int
+		made   int
+	)
+	for made < attempts {
+		if made > 0 {
+			wait := d.backoff(made)
+			var de *deliveryError
+			if errors.As(err, &de) && de.retryAfter > wait {
+				wait = de.retryAfter
+			}
+			if serr := d.wait(ctx, wait); serr != nil {
+				err = serr
+				break
+			}
+		}
+		st, perr := d.post(ctx, ep, e.Type, e.ID, payload)
+		if perr == nil {
+			return nil
+		}
+		err = perr
+		// an attempt cut short by cancellation is not a failed attempt
+		if ctx.Err() != nil {
+			break
+		}
+		made++
+		status = st
+		var de *deliveryError
+		if errors.As(err, &de) && !de.retry {
+			break
+		}
+	}
+	return d.deadLetter(ctx, ep, e, payload, made, status, err)
+}
+
+// deadLetter records a delivery that will not be made and returns err.
+func (d *Dispatcher) deadLetter(ctx context.Context, ep Endpoint, e Event, payload []byte, attempts, status int, err error) error {
+	if d.DeadLetters != nil {
+		dl := DeadLetter{
+			EndpointID: ep.ID,
+			URL:        ep.URL,
+			EventID:    e.ID,
+			EventType:  e.Type,
+			Payload:    payload,
+			Attempts:   attempts,
+			StatusCode: status,
+			LastError:  err.Error(),
+			FailedAt:   time.Now().UTC(),
+		}
+		// keep the dead letter even when the delivery was cancelled
+		if serr := d.DeadLetters.SaveDeadLetter(context.WithoutCancel(ctx), dl); serr != nil {
+			err = errors.Join(err, serr)
+		}
+	}
+	if d.OnError != nil {
+		d.OnError(ep, e, err)
+	}
+	return err
+}
+
+type deliveryError struct {
+	status     int
+	retry      bool
+	retryAfter time.Duration
+	err        error
+}
+
+func (e *deliveryError) Error() string {
+	if e.err != nil {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("receiver responded %d %s", e.status, http.StatusText(e.status))
+}
+
+func (e *deliveryError) Unwrap() error { return e.err }
+
+// post makes one delivery attempt. Network errors, 408, 429 and 5xx
+// responses are retried; any other non-2xx status is final.
+func (d *Dispatcher) post(ctx context.Context, ep Endpoint, eventType, eventID string, payload []byte) (int, error) {
+	timeout := d.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, &deliveryError{err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "mangadex-webhook/1")
+	req.Header.Set(HeaderEvent, eventType)
+	req.Header.Set(HeaderDelivery, eventID)
+	if ep.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(ep.Secret, payload))
+	}
+
+	client := d.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, nil
+	}
+	de := &deliveryError{
+		status: resp.StatusCode,
+		retry: resp.StatusCode == http.StatusRequestTimeout ||
+			resp.StatusCode == http.StatusTooManyRequests ||
+			resp.StatusCode >= 500,
+	}
+	if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s > 0 {
+		de.retryAfter = time.Duration(s) * time.Second
+	}
+	return resp.StatusCode, de
+}
+
+// backoff returns InitialBackoff doubled for every previous retry, capped
+// at MaxBackoff.
+func (d *Dispatcher) backoff(retry int) time.Duration {
+	wait := d.InitialBackoff
+	if wait <= 0 {
+		wait = DefaultInitialBackoff
+	}
+	max := d.MaxBackoff
+	if max <= 0 {
+		max = DefaultMaxBackoff
+	}
+	for i := 1; i < retry && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		wait = max
+	}
+	return wait
+}
+
+func (d *Dispatcher) wait(ctx context.Context, dur time.Duration) error {
+	if d.sleep != nil {
+		return d.sleep(ctx, dur)
+	}
+	t := time.NewTimer(dur)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// Sign returns the signature header value for payload: "sha256=" followed
+// by the hex HMAC-SHA256 of the raw body.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature header against the raw request body. Receivers
+// must verify before decoding the payload.
+func Verify(secret string, payload []byte, signature string) bool {
+	got, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	sig, err := hex.DecodeString(got)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
@@ -0,0 +1,268 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newReceiver starts an endpoint answering with the statuses in turn, the
+// last one repeating, and counts the deliveries it gets.
+func newReceiver(t *testing.T, header http.Header, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var n atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(n.Add(1)) - 1
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(statuses[min(i, len(statuses)-1)])
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &n
+}
+
+// newTestDispatcher records backoff waits instead of sleeping.
+func newTestDispatcher(t *testing.T, srv *httptest.Server) (*Dispatcher, *InMemoryDeadLetterStore, *[]time.Duration) {
+	t.Helper()
+	dead := NewInMemoryDeadLetterStore()
+	d := NewDispatcher(dead)
+	d.HTTPClient = srv.Client()
+	d.InitialBackoff = time.Second
+	d.MaxBackoff = 3 * time.Second
+	var (
+		mu    sync.Mutex
+		waits []time.Duration
+	)
+	d.sleep = func(ctx context.Context, dur time.Duration) error {
+		mu.Lock()
+		defer mu.Unlock()
+		waits = append(waits, dur)
+		return ctx.Err()
+	}
+	if err := d.Register(Endpoint{ID: "ep", URL: srv.URL, Secret: "s3cret"}); err != nil {
+		t.Fatal(err)
+	}
+	return d, dead, &waits
+}
+
+func testEvent() Event {
+	return Event{ID: "evt-1", Type: EventNewChapter, Time: time.Unix(0, 0).UTC(), Data: map[string]string{"k": "v"}}
+}
+
+func TestSignVerify(t *testing.T) {
+	payload := []byte(`{"id":"evt-1"}`)
+	sig := Sign("secret", payload)
+
+	tests := []struct {
+		name    string
+		secret  string
+		payload []byte
+		sig     string
+		want    bool
+	}{
+		{"valid", "secret", payload, sig, true},
+		{"wrong secret", "other", payload, sig, false},
+		{"tampered payload", "secret", []byte(`{"id":"evt-2"}`), sig, false},
+		{"missing prefix", "secret", payload, sig[len("sha256="):], false},
+		{"not hex", "secret", payload, "sha256=zz", false},
+		{"empty", "secret", payload, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.secret, tt.payload, tt.sig); got != tt.want {
+				t.Fatalf("Verify = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDispatchSignsDelivery(t *testing.T) {
+	type got struct {
+		header http.Header
+		body   []byte
+	}
+	deliveries := make(chan got, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		deliveries <- got{r.Header, body}
+	}))
+	defer srv.Close()
+	d, _, _ := newTestDispatcher(t, srv)
+
+	if err := d.Dispatch(context.Background(), testEvent()); err != nil {
+		t.Fatal(err)
+	}
+	g := <-deliveries
+	if !Verify("s3cret", g.body, g.header.Get(HeaderSignature)) {
+		t.Fatalf("signature %q does not verify", g.header.Get(HeaderSignature))
+	}
+	if g.header.Get(HeaderEvent) != EventNewChapter || g.header.Get(HeaderDelivery) != "evt-1" {
+		t.Fatalf("headers = %v", g.header)
+	}
+}
+
+func TestDispatchBackoff(t *testing.T) {
+	srv, n := newReceiver(t, nil, 500, 502, 503, 200)
+	d, dead, waits := newTestDispatcher(t, srv)
+
+	if err := d.Dispatch(context.Background(), testEvent()); err != nil {
+		t.Fatal(err)
+	}
+	if n.Load() != 4 {
+		t.Fatalf("%d attempts, want 4", n.Load())
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+	if !equalDurations(*waits, want) {
+		t.Fatalf("waits = %v, want %v", *waits, want)
+	}
+	if letters, _ := dead.ListDeadLetters(context.Background()); len(letters) != 0 {
+		t.Fatalf("dead letters after success: %+v", letters)
+	}
+}
+
+func TestDispatchRetryAfter(t *testing.T) {
+	srv, _ := newReceiver(t, http.Header{"Retry-After": {"30"}}, 429, 200)
+	d, _, waits := newTestDispatcher(t, srv)
+
+	if err := d.Dispatch(context.Background(), testEvent()); err != nil {
+		t.Fatal(err)
+	}
+	if want := []time.Duration{30 * time.Second}; !equalDurations(*waits, want) {
+		t.Fatalf("waits = %v, want %v", *waits, want)
+	}
+}
+
+func TestDispatchDeadLettersOnExhaustion(t *testing.T) {
+	srv, n := newReceiver(t, nil, 503)
+	d, dead, _ := newTestDispatcher(t, srv)
+	d.MaxAttempts = 3
+	var reported error
+	d.OnError = func(_ Endpoint, _ Event, err error) { reported = err }
+	ctx := context.Background()
+
+	if err := d.Dispatch(ctx, testEvent()); err == nil {
+		t.Fatal("dispatch succeeded")
+	}
+	if n.Load() != 3 || reported == nil {
+		t.Fatalf("%d attempts, reported %v", n.Load(), reported)
+	}
+	letters, _ := dead.ListDeadLetters(ctx)
+	if len(letters) != 1 {
+		t.Fatalf("dead letters = %+v", letters)
+	}
+	dl := letters[0]
+	if dl.EndpointID != "ep" || dl.EventID != "evt-1" || dl.Attempts != 3 || dl.StatusCode != 503 {
+		t.Fatalf("dead letter = %+v", dl)
+	}
+}
+
+func TestDispatchDoesNotRetryClientErrors(t *testing.T) {
+	srv, n := newReceiver(t, nil, 400, 200)
+	d, dead, _ := newTestDispatcher(t, srv)
+	ctx := context.Background()
+
+	if err := d.Dispatch(ctx, testEvent()); err == nil {
+		t.Fatal("dispatch succeeded")
+	}
+	letters, _ := dead.ListDeadLetters(ctx)
+	if n.Load() != 1 || len(letters) != 1 || letters[0].Attempts != 1 {
+		t.Fatalf("%d attempts, dead letters %+v", n.Load(), letters)
+	}
+
+	// the receiver recovered
+	if err := d.Redeliver(ctx, letters[0]); err != nil {
+		t.Fatal(err)
+	}
+	if letters, _ := dead.ListDeadLetters(ctx); len(letters) != 0 {
+		t.Fatalf("redelivered letter kept: %+v", letters)
+	}
+}
+
+func TestDispatchCancelledAttemptsNotCounted(t *testing.T) {
+	srv, _ := newReceiver(t, nil, 500)
+	d, dead, _ := newTestDispatcher(t, srv)
+	ctx, cancel := context.WithCancel(context.Background())
+	d.sleep = func(ctx context.Context, _ time.Duration) error {
+		cancel()
+		return ctx.Err()
+	}
+
+	err := d.Dispatch(ctx, testEvent())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	letters, _ := dead.ListDeadLetters(context.Background())
+	if len(letters) != 1 || letters[0].Attempts != 1 || letters[0].StatusCode != 500 {
+		t.Fatalf("dead letters = %+v", letters)
+	}
+}
+
+func TestEnqueueIsolatesSlowEndpoint(t *testing.T) {
+	release := make(chan struct{})
+	arrived := make(chan struct{}, 4)
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived <- struct{}{}
+		<-release
+	}))
+	defer slow.Close()
+	unblock := sync.OnceFunc(func() { close(release) })
+	defer unblock()
+	fast, fastN := newReceiver(t, nil, 200)
+
+	dead := NewInMemoryDeadLetterStore()
+	d := NewDispatcher(dead)
+	d.QueueSize = 1
+	for _, ep := range []Endpoint{{ID: "slow", URL: slow.URL}, {ID: "fast", URL: fast.URL}} {
+		if err := d.Register(ep); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+
+	// the first event is in flight to the slow endpoint, the second waits
+	// in its queue and the third does not fit; the fast endpoint gets all
+	// three meanwhile
+	for i, want := range []error{nil, nil, ErrQueueFull} {
+		e := testEvent()
+		e.ID = string(rune('a' + i))
+		if err := d.Enqueue(ctx, e); !errors.Is(err, want) {
+			t.Fatalf("enqueue %d: err = %v, want %v", i, err, want)
+		}
+		if i == 0 {
+			<-arrived
+		}
+		deadline := time.Now().Add(5 * time.Second)
+		for int(fastN.Load()) != i+1 {
+			if time.Now().After(deadline) {
+				t.Fatalf("fast endpoint got %d deliveries while the slow one hung", fastN.Load())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	unblock()
+	d.Close()
+	letters, _ := dead.ListDeadLetters(ctx)
+	if len(letters) != 1 || letters[0].EndpointID != "slow" || letters[0].EventID != "c" {
+		t.Fatalf("dead letters = %+v", letters)
+	}
+}
+
+func equalDurations(a, b []time.Duration) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+// Package webhook delivers watcher events to HTTP endpoints as signed JSON.
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/Seann-Moser/mangadex"
+	"github.com/Seann-Moser/mangadex/watch"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// Event types.
+const (
+	EventNewChapter = "chapter.new"
+)
+
+// Event is a single notification. Language, MangaID, Groups and
+// ContentRating are what endpoint filters match on; Data is sent as is.
+type Event struct {
+	ID   string    `json:"id"`
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	Data any       `json:"data"`
+
+	Language      string               `json:"-"`
+	MangaID       openapi_types.UUID   `json:"-"`
+	Groups        []openapi_types.UUID `json:"-"`
+	ContentRating string               `json:"-"`
+}
+
+// ChapterData is the payload of EventNewChapter.
+type ChapterData struct {
+	SubscriptionID string             `json:"subscriptionId"`
+	MangaID        openapi_types.UUID `json:"mangaId"`
+	Chapter        mangadex.Chapter   `json:"chapter"`
+}
+
+// ChapterEvent converts a watcher event. The content rating is taken from
+// the chapter's expanded manga relationship, which the watcher requests.
+func ChapterEvent(c watch.NewChapter) Event {
+	e := Event{
+		ID:      newID(),
+		Type:    EventNewChapter,
+		Time:    time.Now().UTC(),
+		MangaID: c.MangaID,
+		Data: ChapterData{
+			SubscriptionID: c.SubscriptionID,
+			MangaID:        c.MangaID,
+			Chapter:        c.Chapter,
+		},
+	}
+	if a := c.Chapter.Attributes; a != nil && a.TranslatedLanguage != nil {
+		e.Language = *a.TranslatedLanguage
+	}
+	if groups, err := c.Chapter.ScanlationGroups(); err == nil {
+		for _, g := range groups {
+			e.Groups = append(e.Groups, g.Id)
+		}
+	}
+	if m, err := c.Chapter.Manga(); err == nil && m != nil && m.Attributes != nil && m.Attributes.ContentRating != nil {
+		e.ContentRating = string(*m.Attributes.ContentRating)
+	}
+	return e
+}
+
+// Filter restricts the events an endpoint receives. Empty fields match
+// everything; otherwise the event must match one of the listed values.
+type Filter struct {
+	Languages []string             `json:"languages,omitempty"`
+	MangaIDs  []openapi_types.UUID `json:"mangaIds,omitempty"`
+	Groups    []openapi_types.UUID `json:"groups,omitempty"`
+
+	// ContentRatings such as "safe" or "suggestive". Events whose rating is
+	// unknown never match a non-empty list.
+	ContentRatings []string `json:"contentRatings,omitempty"`
+
+	// Types limits the event types, e.g. EventNewChapter.
+	Types []string `json:"types,omitempty"`
+}
+
+// Match reports whether e passes the filter.
+func (f Filter) Match(e Event) bool {
+	if len(f.Types) > 0 && !containsFold(f.Types, e.Type) {
+		return false
+	}
+	if len(f.Languages) > 0 {
+		ok := false
+		for _, l := range f.Languages {
+			if mangadex.NormalizeLanguage(l) == mangadex.NormalizeLanguage(e.Language) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	if len(f.MangaIDs) > 0 && !containsID(f.MangaIDs, e.MangaID) {
+		return false
+	}
+	if len(f.Groups) > 0 {
+		ok := false
+		for _, g := range e.Groups {
+			if containsID(f.Groups, g) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	if len(f.ContentRatings) > 0 && (e.ContentRating == "" || !containsFold(f.ContentRatings, e.ContentRating)) {
+		return false
+	}
+	return true
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsID(list []openapi_types.UUID, id openapi_types.UUID) bool {
+	for _, v := range list {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func TestFilterMatch(t *testing.T) {
+	manga, other := uuid.New(), uuid.New()
+	group := uuid.New()
+	e := Event{
+		Type:          EventNewChapter,
+		Language:      "pt-br",
+		MangaID:       manga,
+		Groups:        []openapi_types.UUID{uuid.New(), group},
+		ContentRating: "safe",
+	}
+	unrated := e
+	unrated.ContentRating = ""
+
+	tests := []struct {
+		name   string
+		filter Filter
+		event  Event
+		want   bool
+	}{
+		{"empty filter", Filter{}, e, true},
+		{"type", Filter{Types: []string{"CHAPTER.NEW"}}, e, true},
+		{"other type", Filter{Types: []string{"manga.updated"}}, e, false},
+		{"language normalized", Filter{Languages: []string{"en", "pt_BR"}}, e, true},
+		{"other language", Filter{Languages: []string{"pt"}}, e, false},
+		{"manga", Filter{MangaIDs: []openapi_types.UUID{other, manga}}, e, true},
+		{"other manga", Filter{MangaIDs: []openapi_types.UUID{other}}, e, false},
+		{"any group", Filter{Groups: []openapi_types.UUID{group}}, e, true},
+		{"no group", Filter{Groups: []openapi_types.UUID{other}}, e, false},
+		{"rating", Filter{ContentRatings: []string{"Safe"}}, e, true},
+		{"other rating", Filter{ContentRatings: []string{"erotica"}}, e, false},
+		{"unknown rating", Filter{ContentRatings: []string{"safe"}}, unrated, false},
+		{"unknown rating unfiltered", Filter{Languages: []string{"pt-br"}}, unrated, true},
+		{"all fields", Filter{
+			Types:          []string{EventNewChapter},
+			Languages:      []string{"pt-br"},
+			MangaIDs:       []openapi_types.UUID{manga},
+			Groups:         []openapi_types.UUID{group},
+			ContentRatings: []string{"safe", "suggestive"},
+		}, e, true},
+		{"one field fails", Filter{
+			Languages: []string{"pt-br"},
+			MangaIDs:  []openapi_types.UUID{other},
+		}, e, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(tt.event); got != tt.want {
+				t.Fatalf("Match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}